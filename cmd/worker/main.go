@@ -7,6 +7,7 @@ import (
 	"syscall"
 
 	"lister/internal/config"
+	"lister/internal/database"
 	"lister/internal/logger"
 	"lister/internal/worker"
 )
@@ -21,8 +22,15 @@ func main() {
 	// Initialize logger
 	logger := logger.New(cfg.LogLevel)
 
+	// Initialize database
+	db, err := database.New(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer db.Close()
+
 	// Initialize worker
-	w := worker.New(cfg, logger)
+	w := worker.New(cfg, logger, db)
 
 	// Start worker
 	logger.Info("Starting worker...")
@@ -0,0 +1,302 @@
+// Package abtest runs A/B tests between two feed variants of a product. The variants
+// take turns in feeds on a time-split schedule, and the channel performance imported
+// for each is compared with a two-proportion z-test: click-through rate (clicks per
+// impression) or conversion rate (conversions per click).
+//
+// Tests have a fixed horizon: the arms are compared for a decision once, when both reach
+// the planned sample, and a variant wins if the difference is significant at the test's
+// confidence level then. Testing every import and stopping at the first significant
+// result would find far more winners than the confidence level allows.
+package abtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Arms
+const (
+	ArmA = "A"
+	ArmB = "B"
+)
+
+// Metrics
+const (
+	MetricCTR            = "ctr"             // clicks per impression
+	MetricConversionRate = "conversion_rate" // conversions per click
+)
+
+// Defaults for tests that don't set their own
+const (
+	DefaultRotationHours   = 24
+	DefaultMinSampleSize   = 100
+	DefaultConfidenceLevel = 0.95
+)
+
+// Counts are an arm's performance
+type Counts struct {
+	Impressions int     `json:"impressions"`
+	Clicks      int     `json:"clicks"`
+	Conversions int     `json:"conversions"`
+	Revenue     float64 `json:"revenue"`
+	Spend       float64 `json:"spend"`
+}
+
+// Add returns the sum of two counts
+func (c Counts) Add(o Counts) Counts {
+	return Counts{
+		Impressions: c.Impressions + o.Impressions,
+		Clicks:      c.Clicks + o.Clicks,
+		Conversions: c.Conversions + o.Conversions,
+		Revenue:     c.Revenue + o.Revenue,
+		Spend:       c.Spend + o.Spend,
+	}
+}
+
+// ROAS returns revenue per unit of spend, or nil without spend
+func (c Counts) ROAS() *float64 {
+	if c.Spend <= 0 {
+		return nil
+	}
+	roas := math.Round(c.Revenue/c.Spend*10000) / 10000
+	return &roas
+}
+
+// trials returns the metric's successes and trials
+func (c Counts) trials(metric string) (int, int) {
+	if metric == MetricConversionRate {
+		return c.Conversions, c.Clicks
+	}
+	return c.Clicks, c.Impressions
+}
+
+// ValidMetric reports whether metric is one tests can compare
+func ValidMetric(metric string) bool {
+	return metric == MetricCTR || metric == MetricConversionRate
+}
+
+// Pause is a period a test was paused; To is nil while it still is
+type Pause struct {
+	From time.Time  `json:"from"`
+	To   *time.Time `json:"to,omitempty"`
+}
+
+// ParsePauses reads a test's pauses JSON
+func ParsePauses(text string) ([]Pause, error) {
+	var pauses []Pause
+	if strings.TrimSpace(text) == "" {
+		return pauses, nil
+	}
+	if err := json.Unmarshal([]byte(text), &pauses); err != nil {
+		return nil, fmt.Errorf("invalid pauses: %w", err)
+	}
+	return pauses, nil
+}
+
+// Schedule is a time-split rotation: arm A serves the first period from the start of
+// the test's first day (UTC), arm B the next, and so on. Periods that are whole days
+// line up with daily performance reports. Pauses stop the rotation, which resumes with
+// the arm whose period was cut short.
+type Schedule struct {
+	Start  time.Time
+	Period time.Duration
+	Pauses []Pause
+}
+
+// NewSchedule returns the schedule of a test started at start, rotating every hours
+func NewSchedule(start time.Time, hours int, pauses []Pause) Schedule {
+	if hours <= 0 {
+		hours = DefaultRotationHours
+	}
+	start = start.UTC()
+	return Schedule{
+		Start:  time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC),
+		Period: time.Duration(hours) * time.Hour,
+		Pauses: pauses,
+	}
+}
+
+// Arm returns the arm serving at a time, or "" while the test is paused
+func (s Schedule) Arm(at time.Time) string {
+	if at.Before(s.Start) || s.Period <= 0 {
+		return ArmA
+	}
+	elapsed := at.Sub(s.Start)
+	for _, pause := range s.Pauses {
+		from, to := s.span(pause)
+		if !at.Before(from) && (to.IsZero() || at.Before(to)) {
+			return ""
+		}
+		if !to.IsZero() && !to.After(at) && to.After(s.Start) {
+			if from.Before(s.Start) {
+				from = s.Start
+			}
+			elapsed -= to.Sub(from)
+		}
+	}
+	if int64(elapsed/s.Period)%2 == 0 {
+		return ArmA
+	}
+	return ArmB
+}
+
+// PausedOn reports whether the test was paused at any time of a UTC day, whose
+// performance then isn't only the test's
+func (s Schedule) PausedOn(day time.Time) bool {
+	day = day.UTC()
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	for _, pause := range s.Pauses {
+		from, to := s.span(pause)
+		if from.Before(end) && (to.IsZero() || to.After(start)) {
+			return true
+		}
+	}
+	return false
+}
+
+// span returns the period a pause takes out of the rotation, with a zero end while the
+// pause lasts. Daily schedules lose the whole days a pause touches, so their periods keep
+// lining up with days: a test resumed during a day serves again from the next.
+func (s Schedule) span(pause Pause) (time.Time, time.Time) {
+	from := pause.From.UTC()
+	var to time.Time
+	if pause.To != nil {
+		to = pause.To.UTC()
+	}
+	if !s.Daily() {
+		return from, to
+	}
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	if !to.IsZero() {
+		to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC).Add(24 * time.Hour)
+	}
+	return from, to
+}
+
+// Daily reports whether each UTC day falls in a single period, so daily metrics can be
+// credited to an arm by date
+func (s Schedule) Daily() bool {
+	return s.Period > 0 && s.Period%(24*time.Hour) == 0
+}
+
+// Result is a comparison of two arms
+type Result struct {
+	Metric        string  `json:"metric"`
+	RateA         float64 `json:"rate_a"`
+	RateB         float64 `json:"rate_b"`
+	Lift          float64 `json:"lift"` // percent change of B over A
+	Z             float64 `json:"z"`
+	PValue        float64 `json:"p_value"`
+	Confidence    float64 `json:"confidence"`
+	SampleReached bool    `json:"sample_reached"` // both arms have the planned sample, so the result decides the test
+	Significant   bool    `json:"significant"`
+	Winner        string  `json:"winner,omitempty"`
+	Reason        string  `json:"reason,omitempty"`
+}
+
+// Compare tests whether the arms' rates of metric differ, with a two-sided
+// two-proportion z-test. Only once both arms have the planned minSample trials does
+// the result count: there is then a winner when the confidence (1 - p) reaches level.
+// Before that the rates and confidence are for information only.
+func Compare(a, b Counts, metric string, minSample int, level float64) Result {
+	if !ValidMetric(metric) {
+		metric = MetricCTR
+	}
+	if level <= 0 || level >= 1 {
+		level = DefaultConfidenceLevel
+	}
+	result := Result{Metric: metric, PValue: 1}
+	successesA, trialsA := a.trials(metric)
+	successesB, trialsB := b.trials(metric)
+	if trialsA > 0 {
+		result.RateA = round(float64(successesA)/float64(trialsA), 6)
+	}
+	if trialsB > 0 {
+		result.RateB = round(float64(successesB)/float64(trialsB), 6)
+	}
+	if result.RateA > 0 {
+		result.Lift = round((result.RateB-result.RateA)/result.RateA*100, 2)
+	}
+
+	z, p := ZTest(successesA, trialsA, successesB, trialsB)
+	result.Z = round(z, 4)
+	result.PValue = round(p, 6)
+	result.Confidence = round(1-p, 6)
+
+	switch {
+	case trialsA < minSample || trialsB < minSample:
+		result.Reason = fmt.Sprintf("each variant needs %d trials; A has %d and B %d", minSample, trialsA, trialsB)
+	case result.Confidence < level:
+		result.SampleReached = true
+		result.Reason = fmt.Sprintf("confidence %.1f%% is below %.1f%% at the planned sample", result.Confidence*100, level*100)
+	default:
+		result.SampleReached = true
+		result.Significant = true
+		result.Winner = ArmA
+		if z < 0 {
+			result.Winner = ArmB
+		}
+	}
+	return result
+}
+
+// ZTest returns the z statistic of the difference between proportions x1/n1 and x2/n2,
+// positive when the first is higher, and its two-sided p-value
+func ZTest(x1, n1, x2, n2 int) (float64, float64) {
+	if n1 <= 0 || n2 <= 0 {
+		return 0, 1
+	}
+	p1 := float64(x1) / float64(n1)
+	p2 := float64(x2) / float64(n2)
+	pooled := float64(x1+x2) / float64(n1+n2)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/float64(n1) + 1/float64(n2)))
+	if se == 0 {
+		return 0, 1
+	}
+	z := (p1 - p2) / se
+	return z, math.Erfc(math.Abs(z) / math.Sqrt2)
+}
+
+func round(value float64, places int) float64 {
+	factor := math.Pow(10, float64(places))
+	return math.Round(value*factor) / factor
+}
+
+// Transformation is what a feed variant changes in a product's feed item
+type Transformation struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// ParseTransformation reads a feed variant's transformation JSON
+func ParseTransformation(text string) (Transformation, error) {
+	var t Transformation
+	if strings.TrimSpace(text) == "" {
+		return t, nil
+	}
+	if err := json.Unmarshal([]byte(text), &t); err != nil {
+		return t, fmt.Errorf("invalid transformation: %w", err)
+	}
+	t.Title = strings.TrimSpace(t.Title)
+	t.Description = strings.TrimSpace(t.Description)
+	return t, nil
+}
+
+// Empty reports whether the transformation changes nothing
+func (t Transformation) Empty() bool {
+	return t.Title == "" && t.Description == ""
+}
+
+// Apply sets an item's title and description to the transformation's
+func (t Transformation) Apply(item map[string]interface{}) {
+	if t.Title != "" {
+		item["title"] = t.Title
+	}
+	if t.Description != "" {
+		item["description"] = t.Description
+	}
+}
@@ -0,0 +1,191 @@
+package abtest
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestZTest(t *testing.T) {
+	tests := []struct {
+		x1, n1, x2, n2 int
+		z, p           float64
+	}{
+		{200, 10000, 260, 10000, -2.8303, 0.004651},
+		{260, 10000, 200, 10000, 2.8303, 0.004651},
+		{50, 100, 30, 100, 2.8868, 0.003892},
+		{50, 100, 50, 100, 0, 1},
+		{0, 100, 0, 100, 0, 1},  // no variance
+		{10, 0, 10, 100, 0, 1},  // no trials
+		{10, 100, 10, -1, 0, 1}, // no trials
+	}
+	for _, tt := range tests {
+		z, p := ZTest(tt.x1, tt.n1, tt.x2, tt.n2)
+		if math.Abs(z-tt.z) > 1e-4 || math.Abs(p-tt.p) > 1e-6 {
+			t.Errorf("ZTest(%d, %d, %d, %d) = %.4f, %.6f, want %.4f, %.6f", tt.x1, tt.n1, tt.x2, tt.n2, z, p, tt.z, tt.p)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name          string
+		a, b          Counts
+		metric        string
+		minSample     int
+		level         float64
+		sampleReached bool
+		winner        string
+		reason        string
+	}{
+		{
+			name:          "B wins",
+			a:             Counts{Impressions: 10000, Clicks: 200},
+			b:             Counts{Impressions: 10000, Clicks: 260},
+			metric:        MetricCTR,
+			minSample:     100,
+			level:         0.95,
+			sampleReached: true,
+			winner:        ArmB,
+		},
+		{
+			name:          "below the planned sample",
+			a:             Counts{Impressions: 10000, Clicks: 200},
+			b:             Counts{Impressions: 10000, Clicks: 260},
+			metric:        MetricCTR,
+			minSample:     20000,
+			level:         0.95,
+			sampleReached: false,
+			reason:        "each variant needs 20000 trials; A has 10000 and B 10000",
+		},
+		{
+			name:          "not significant at the level",
+			a:             Counts{Impressions: 10000, Clicks: 200},
+			b:             Counts{Impressions: 10000, Clicks: 260},
+			metric:        MetricCTR,
+			minSample:     100,
+			level:         0.999,
+			sampleReached: true,
+			reason:        "confidence 99.5% is below 99.9% at the planned sample",
+		},
+		{
+			name:          "A wins on conversion rate",
+			a:             Counts{Impressions: 1000, Clicks: 100, Conversions: 50},
+			b:             Counts{Impressions: 5000, Clicks: 100, Conversions: 30},
+			metric:        MetricConversionRate,
+			minSample:     100,
+			level:         0.95,
+			sampleReached: true,
+			winner:        ArmA,
+		},
+		{
+			name:          "unknown metric and level fall back to CTR at 95%",
+			a:             Counts{Impressions: 10000, Clicks: 200, Conversions: 100},
+			b:             Counts{Impressions: 10000, Clicks: 260, Conversions: 100},
+			metric:        "revenue",
+			minSample:     100,
+			level:         0,
+			sampleReached: true,
+			winner:        ArmB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Compare(tt.a, tt.b, tt.metric, tt.minSample, tt.level)
+			if result.SampleReached != tt.sampleReached {
+				t.Errorf("SampleReached = %v, want %v", result.SampleReached, tt.sampleReached)
+			}
+			if result.Winner != tt.winner {
+				t.Errorf("Winner = %q, want %q", result.Winner, tt.winner)
+			}
+			if result.Significant != (tt.winner != "") {
+				t.Errorf("Significant = %v with winner %q", result.Significant, tt.winner)
+			}
+			if result.Reason != tt.reason {
+				t.Errorf("Reason = %q, want %q", result.Reason, tt.reason)
+			}
+		})
+	}
+
+	result := Compare(Counts{Impressions: 10000, Clicks: 200}, Counts{Impressions: 10000, Clicks: 260}, "revenue", 100, 0)
+	if result.Metric != MetricCTR || result.RateA != 0.02 || result.RateB != 0.026 || result.Lift != 30 ||
+		result.Z != -2.8303 || result.PValue != 0.004651 || result.Confidence != 0.995349 {
+		t.Errorf("Compare = %+v", result)
+	}
+}
+
+func TestScheduleArm(t *testing.T) {
+	day := func(d, hour int) time.Time {
+		return time.Date(2026, 3, 2+d, hour, 0, 0, 0, time.UTC)
+	}
+	pause := func(from, to time.Time) Pause {
+		if to.IsZero() {
+			return Pause{From: from}
+		}
+		return Pause{From: from, To: &to}
+	}
+
+	daily := NewSchedule(day(0, 10), 24, nil)
+	dailyPaused := NewSchedule(day(0, 10), 24, []Pause{pause(day(1, 5), day(2, 3))})
+	dailyStopped := NewSchedule(day(0, 10), 0, []Pause{pause(day(1, 5), time.Time{})})
+	hourly := NewSchedule(day(0, 10), 6, []Pause{pause(day(0, 7), day(0, 9))})
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		at       time.Time
+		arm      string
+	}{
+		{"before the start", daily, day(-1, 12), ArmA},
+		{"first day", daily, day(0, 1), ArmA},
+		{"second day", daily, day(1, 23), ArmB},
+		{"third day", daily, day(2, 0), ArmA},
+		{"day before a pause", dailyPaused, day(0, 12), ArmA},
+		{"paused day", dailyPaused, day(1, 4), ""},
+		{"day the pause ended", dailyPaused, day(2, 12), ""},
+		{"resumed with the cut short arm", dailyPaused, day(3, 12), ArmB},
+		{"rotating after the pause", dailyPaused, day(4, 12), ArmA},
+		{"before an open pause", dailyStopped, day(0, 12), ArmA},
+		{"during an open pause", dailyStopped, day(9, 12), ""},
+		{"first period", hourly, day(0, 5), ArmA},
+		{"hourly pause", hourly, day(0, 8), ""},
+		{"pause hours don't count", hourly, day(0, 13), ArmB},
+		{"next day", hourly, day(1, 3), ArmA},
+	}
+	for _, tt := range tests {
+		if arm := tt.schedule.Arm(tt.at); arm != tt.arm {
+			t.Errorf("%s: Arm(%s) = %q, want %q", tt.name, tt.at, arm, tt.arm)
+		}
+	}
+}
+
+func TestSchedulePausedOn(t *testing.T) {
+	day := func(d, hour int) time.Time {
+		return time.Date(2026, 3, 2+d, hour, 0, 0, 0, time.UTC)
+	}
+	to := day(2, 3)
+	daily := NewSchedule(day(0, 10), 24, []Pause{{From: day(1, 5), To: &to}, {From: day(6, 20)}})
+	hourlyTo := day(0, 9)
+	hourly := NewSchedule(day(0, 10), 6, []Pause{{From: day(0, 7), To: &hourlyTo}})
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		day      time.Time
+		paused   bool
+	}{
+		{"before the pause", daily, day(0, 12), false},
+		{"pause starts", daily, day(1, 0), true},
+		{"pause ends", daily, day(2, 23), true},
+		{"after the pause", daily, day(3, 0), false},
+		{"day before an open pause", daily, day(5, 12), false},
+		{"open pause", daily, day(9, 12), true},
+		{"hourly pause", hourly, day(0, 20), true},
+		{"day after an hourly pause", hourly, day(1, 8), false},
+	}
+	for _, tt := range tests {
+		if paused := tt.schedule.PausedOn(tt.day); paused != tt.paused {
+			t.Errorf("%s: PausedOn(%s) = %v, want %v", tt.name, tt.day, paused, tt.paused)
+		}
+	}
+}
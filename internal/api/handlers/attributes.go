@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"lister/internal/attributes"
+	"lister/internal/llm"
+	"lister/internal/models"
+	"lister/internal/review"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// attributeRequest extracts a product's attributes. Values at or above MinConfidence are
+// applied right away; the rest wait in the review queue.
+type attributeRequest struct {
+	ProductID     string  `json:"product_id" binding:"required"`
+	MinConfidence float64 `json:"min_confidence"`
+	Model         string  `json:"model"`
+}
+
+// ExtractAttributes extracts color, size, material, gender and age group from a product's
+// title, description and variant options. Each new value becomes an attribute
+// optimization scored with the model's confidence.
+// POST /api/v1/optimizer/attributes
+func (h *OptimizerHandler) ExtractAttributes(c *gin.Context) {
+	var req attributeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+	if req.MinConfidence <= 0 {
+		req.MinConfidence = attributes.DefaultMinConfidence
+	}
+
+	orgUUID := h.organizationUUID(c)
+	productUUID, err := uuid.Parse(req.ProductID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+	var product models.Product
+	if err := h.db.First(&product, "id = ?", productUUID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		return
+	}
+
+	settings, err := h.getAISettings(orgUUID)
+	if err != nil {
+		settings = h.getDefaultAISettings(orgUUID)
+	}
+	if req.Model != "" {
+		settings.DefaultModel = req.Model
+	}
+
+	if err := h.checkAndDeductCredits(orgUUID, 1); err != nil {
+		c.JSON(http.StatusPaymentRequired, gin.H{"error": "Insufficient AI credits", "details": err.Error()})
+		return
+	}
+
+	productData := attributeProductData(&product)
+	optimizer := h.optimizerFor(orgUUID, settings, h.monthlyBudget(orgUUID, settings))
+	extracted, err := optimizer.ExtractAttributes(productData)
+	aiModel, usage, cost := h.recordUsage(orgUUID, string(models.OptimizationTypeAttribute), optimizer, settings.DefaultModel)
+	h.updateCreditsCost(orgUUID, cost, err == nil)
+	if err != nil {
+		if errors.Is(err, llm.ErrBudgetExceeded) {
+			h.refundCredits(orgUUID, 1)
+			c.JSON(http.StatusPaymentRequired, gin.H{"error": "Monthly AI budget exceeded", "details": err.Error()})
+			return
+		}
+		errorMsg := err.Error()
+		history := &models.OptimizationHistory{
+			ProductID:        productUUID,
+			OrganizationID:   orgUUID,
+			OptimizationType: models.OptimizationTypeAttribute,
+			Status:           models.OptimizationStatusFailed,
+			ErrorMessage:     &errorMsg,
+			AIModel:          aiModel,
+			Cost:             cost,
+			TokensUsed:       usage.TotalTokens,
+		}
+		if dbErr := h.db.Create(history).Error; dbErr != nil {
+			h.logger.Error("Failed to save optimization history: %v", dbErr)
+		}
+		c.JSON(http.StatusBadGateway, gin.H{"error": "AI attribute extraction failed", "details": errorMsg})
+		return
+	}
+
+	// The call's cost is recorded on the first attribute it produced
+	current := attributes.Current(product.Metadata)
+	historyCost, historyTokens := cost, usage.TotalTokens
+	results := make([]gin.H, 0, len(extracted.Attributes))
+	for _, attribute := range extracted.Attributes {
+		value, err := attributes.Normalize(attribute.Name, attribute.Value)
+		if err != nil {
+			results = append(results, gin.H{"name": attribute.Name, "value": attribute.Value, "status": "skipped", "error": err.Error()})
+			continue
+		}
+		if current[attribute.Name] == value {
+			results = append(results, gin.H{"name": attribute.Name, "value": value, "status": "unchanged"})
+			continue
+		}
+
+		confidence := int(attribute.Confidence)
+		history := &models.OptimizationHistory{
+			ProductID:        productUUID,
+			OrganizationID:   orgUUID,
+			OptimizationType: models.OptimizationTypeAttribute,
+			OriginalValue:    current[attribute.Name],
+			OptimizedValue:   value,
+			Status:           models.OptimizationStatusPending,
+			Score:            &confidence,
+			AIModel:          aiModel,
+			Cost:             historyCost,
+			TokensUsed:       historyTokens,
+			Metadata: models.JSONB{
+				"attribute":  attribute.Name,
+				"confidence": attribute.Confidence,
+				"source":     attribute.Source,
+			},
+		}
+		history.SetPrompt(optimizer.Template(models.OptimizationTypeAttribute))
+		if err := h.db.Create(history).Error; err != nil {
+			h.logger.Error("Failed to save %s attribute of %s: %v", attribute.Name, productUUID, err)
+			results = append(results, gin.H{"name": attribute.Name, "value": value, "status": "failed", "error": "Failed to save attribute"})
+			continue
+		}
+		historyCost, historyTokens = 0, 0
+
+		if attribute.Confidence >= req.MinConfidence && !settings.RequireApproval {
+			if err := review.Apply(h.db, history, review.Decision{Reviewer: review.AutoReviewer}); err != nil {
+				h.logger.Error("Failed to apply %s attribute of %s: %v", attribute.Name, productUUID, err)
+			}
+		}
+		results = append(results, gin.H{
+			"name":            attribute.Name,
+			"value":           value,
+			"confidence":      attribute.Confidence,
+			"status":          history.Status,
+			"optimization_id": history.ID,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"product_id": productUUID,
+		"attributes": results,
+		"ai_model":   aiModel,
+		"cost":       cost,
+	})
+}
+
+// attributeProductData is the product content attributes are extracted from
+func attributeProductData(product *models.Product) map[string]interface{} {
+	data := map[string]interface{}{"title": product.Title}
+	if product.Description != nil {
+		data["description"] = *product.Description
+	}
+	if product.Brand != nil {
+		data["brand"] = *product.Brand
+	}
+	if product.Category != nil {
+		data["category"] = *product.Category
+	}
+	if options := attributes.VariantOptions(product.Variants); len(options) > 0 {
+		data["variant_options"] = options
+	}
+	return data
+}
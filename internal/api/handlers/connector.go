@@ -1,23 +1,40 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"lister/internal/config"
+	"lister/internal/connectors/bigcommerce"
+	"lister/internal/connectors/csvimport"
+	"lister/internal/connectors/magento"
+	"lister/internal/connectors/restapi"
+	"lister/internal/connectors/woocommerce"
+	"lister/internal/logger"
 	"lister/internal/models"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+var errInvalidWebhookSignature = errors.New("invalid webhook signature")
+
 type ConnectorHandler struct {
 	db     *gorm.DB
-	logger interface{}
+	logger *logger.Logger
+	config *config.Config
 }
 
-func NewConnectorHandler(db *gorm.DB, logger interface{}) *ConnectorHandler {
+func NewConnectorHandler(db *gorm.DB, logger *logger.Logger, config *config.Config) *ConnectorHandler {
 	return &ConnectorHandler{
 		db:     db,
 		logger: logger,
+		config: config,
 	}
 }
 
@@ -113,8 +130,325 @@ func (h *ConnectorHandler) Sync(c *gin.Context) {
 		return
 	}
 
-	// TODO: Implement actual sync logic
-	// This would trigger the sync process for the connector
+	var products []*models.Product
+	var err error
+
+	// Recorded as the last sync so changes made while syncing are picked up next time
+	startedAt := time.Now()
+
+	switch connector.Type {
+	case models.ConnectorTypeWooCommerce:
+		storeURL, _ := connector.Config["store_url"].(string)
+		consumerKey, _ := connector.Credentials["consumer_key"].(string)
+		consumerSecret, _ := connector.Credentials["consumer_secret"].(string)
+		if storeURL == "" || consumerKey == "" || consumerSecret == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing store_url, consumer_key or consumer_secret"})
+			return
+		}
+		products, err = woocommerce.New(h.config, h.logger).SyncProducts(storeURL, consumerKey, consumerSecret)
+	case models.ConnectorTypeBigCommerce:
+		storeHash, _ := connector.Config["store_hash"].(string)
+		accessToken, _ := connector.Credentials["access_token"].(string)
+		if storeHash == "" || accessToken == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing store_hash or access_token"})
+			return
+		}
+		products, err = bigcommerce.New(h.config, h.logger).SyncProducts(storeHash, accessToken)
+	case models.ConnectorTypeMagento:
+		storeURL, _ := connector.Config["store_url"].(string)
+		accessToken, _ := connector.Credentials["access_token"].(string)
+		if storeURL == "" || accessToken == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing store_url or access_token"})
+			return
+		}
+		// Incremental on updated_at unless a full sync is requested
+		var updatedSince *time.Time
+		if c.Query("full") != "true" {
+			updatedSince = connector.LastSync
+		}
+		products, err = magento.New(h.config, h.logger).SyncProducts(storeURL, accessToken, updatedSince)
+	case models.ConnectorTypeAPI:
+		var mappingErrors []restapi.MappingError
+		products, mappingErrors, err = restapi.New(h.config, h.logger).SyncProducts(connector.Config, connector.Credentials)
+		if len(mappingErrors) > 0 {
+			h.logger.Info("Connector %s skipped %d items that could not be mapped", connector.ID, len(mappingErrors))
+		}
+	case models.ConnectorTypeCSV:
+		var result *csvimport.Result
+		result, err = csvimport.New(h.config, h.logger).SyncProducts(connector.Config, connector.Credentials)
+		if err == nil {
+			products = result.Products
+			if len(result.Errors) > 0 {
+				h.logger.Info("Connector %s skipped %d rows that could not be imported", connector.ID, len(result.Errors))
+			}
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Sync not supported for this connector type"})
+		return
+	}
+
+	if err != nil {
+		h.logger.Error("Connector %s sync failed: %v", connector.ID, err)
+		h.db.Model(&connector).Update("status", models.ConnectorStatusError)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to fetch products", "details": err.Error()})
+		return
+	}
+
+	syncedCount := h.upsertProducts(connector.ID, products)
+
+	// last_sync only moves once every product is saved, so an incremental sync retries the
+	// products that failed
+	if syncedCount == len(products) {
+		connector.LastSync = &startedAt
+	} else {
+		h.logger.Info("Connector %s saved %d of %d products; last sync left unchanged", connector.ID, syncedCount, len(products))
+	}
+	connector.Status = models.ConnectorStatusActive
+	h.db.Save(&connector)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Products synced successfully",
+		"synced_count": syncedCount,
+		"total":        len(products),
+	})
+}
+
+// DryRun previews the first products a REST connector would import without saving them.
+// The body may carry a config and credentials to try before they are saved.
+func (h *ConnectorHandler) DryRun(c *gin.Context) {
+	id := c.Param("id")
+
+	var connector models.Connector
+	if err := h.db.First(&connector, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Connector not found"})
+		return
+	}
+
+	if connector.Type != models.ConnectorTypeAPI {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dry run not supported for this connector type"})
+		return
+	}
+
+	var req struct {
+		Config      map[string]interface{} `json:"config"`
+		Credentials map[string]interface{} `json:"credentials"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.Config == nil {
+		req.Config = connector.Config
+	}
+	if req.Credentials == nil {
+		req.Credentials = connector.Credentials
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	preview, err := restapi.New(h.config, h.logger).DryRun(req.Config, req.Credentials, limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dry run failed", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": preview})
+}
+
+// Webhook applies a product webhook sent by the connector's store
+func (h *ConnectorHandler) Webhook(c *gin.Context) {
+	id := c.Param("id")
+
+	var connector models.Connector
+	if err := h.db.First(&connector, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Connector not found"})
+		return
+	}
+
+	payload, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read payload"})
+		return
+	}
+
+	switch connector.Type {
+	case models.ConnectorTypeWooCommerce:
+		err = h.handleWooCommerceWebhook(c, &connector, payload)
+	case models.ConnectorTypeBigCommerce:
+		err = h.handleBigCommerceWebhook(c, &connector, payload)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Webhooks not supported for this connector type"})
+		return
+	}
+
+	if errors.Is(err, errInvalidWebhookSignature) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid webhook signature"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to process webhook for connector %s: %v", connector.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process webhook"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook processed successfully"})
+}
+
+// handleWooCommerceWebhook verifies and applies a product.created/updated/deleted webhook
+func (h *ConnectorHandler) handleWooCommerceWebhook(c *gin.Context, connector *models.Connector, payload []byte) error {
+	secret, _ := connector.Credentials["webhook_secret"].(string)
+	if secret == "" || !woocommerce.VerifyWebhook(payload, c.GetHeader("X-WC-Webhook-Signature"), secret) {
+		return errInvalidWebhookSignature
+	}
+
+	storeURL, _ := connector.Config["store_url"].(string)
+	consumerKey, _ := connector.Credentials["consumer_key"].(string)
+	consumerSecret, _ := connector.Credentials["consumer_secret"].(string)
+	client := woocommerce.NewClient(storeURL, consumerKey, consumerSecret, h.logger)
+
+	event, err := woocommerce.New(h.config, h.logger).HandleWebhook(c.GetHeader("X-WC-Webhook-Topic"), payload, client)
+	if errors.Is(err, woocommerce.ErrUnsupportedTopic) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch event.Action {
+	case "ping":
+		return nil
+	case "deleted":
+		return h.archiveProduct(connector.ID, event.ExternalID)
+	default:
+		if h.upsertProducts(connector.ID, []*models.Product{event.Product}) == 0 {
+			return fmt.Errorf("failed to save product %s", event.ExternalID)
+		}
+		return nil
+	}
+}
+
+// handleBigCommerceWebhook verifies and applies a store/product/* webhook
+func (h *ConnectorHandler) handleBigCommerceWebhook(c *gin.Context, connector *models.Connector, payload []byte) error {
+	secret, _ := connector.Credentials["webhook_secret"].(string)
+	if secret == "" || !bigcommerce.VerifyWebhook(c.GetHeader(bigcommerce.WebhookSecretHeader), secret) {
+		return errInvalidWebhookSignature
+	}
+
+	storeHash, _ := connector.Config["store_hash"].(string)
+	accessToken, _ := connector.Credentials["access_token"].(string)
+	client := bigcommerce.NewClient(storeHash, accessToken, h.logger)
+
+	// HandleWebhook checks the producing store before fetching with the connector's token
+	event, err := bigcommerce.New(h.config, h.logger).HandleWebhook(payload, client)
+	if errors.Is(err, bigcommerce.ErrUnsupportedScope) {
+		return nil
+	}
+	if errors.Is(err, bigcommerce.ErrStoreMismatch) {
+		return errInvalidWebhookSignature
+	}
+	if err != nil {
+		return err
+	}
+
+	if event.Action == "deleted" {
+		return h.archiveProduct(connector.ID, event.ExternalID)
+	}
+	if h.upsertProducts(connector.ID, []*models.Product{event.Product}) == 0 {
+		return fmt.Errorf("failed to save product %s", event.ExternalID)
+	}
+	return nil
+}
+
+// RegisterWebhooks subscribes the connector's webhook endpoint to product events in the store
+func (h *ConnectorHandler) RegisterWebhooks(c *gin.Context) {
+	id := c.Param("id")
+
+	var connector models.Connector
+	if err := h.db.First(&connector, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Connector not found"})
+		return
+	}
+
+	var req struct {
+		Destination string `json:"destination" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch connector.Type {
+	case models.ConnectorTypeBigCommerce:
+		storeHash, _ := connector.Config["store_hash"].(string)
+		accessToken, _ := connector.Credentials["access_token"].(string)
+		secret, _ := connector.Credentials["webhook_secret"].(string)
+		if storeHash == "" || accessToken == "" || secret == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing store_hash, access_token or webhook_secret"})
+			return
+		}
+
+		client := bigcommerce.NewClient(storeHash, accessToken, h.logger)
+		hooks, err := bigcommerce.New(h.config, h.logger).RegisterWebhooks(client, req.Destination, secret)
+		if err != nil {
+			h.logger.Error("Failed to register webhooks for connector %s: %v", connector.ID, err)
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to register webhooks", "details": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": hooks})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Webhook registration not supported for this connector type"})
+	}
+}
+
+// upsertProducts creates or updates a connector's products matched on SKU and returns how many were saved.
+// A SKU already held by another connector's or organization's product is not overwritten.
+func (h *ConnectorHandler) upsertProducts(connectorID string, products []*models.Product) int {
+	owner := `products.connector_id = ? AND products.organization_id = (SELECT organization_id FROM connectors WHERE id = ?)`
+	saved := 0
+	for _, product := range products {
+		err := h.db.Transaction(func(tx *gorm.DB) error {
+			res := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "sku"}},
+				DoUpdates: clause.AssignmentColumns([]string{"external_id", "title", "description", "brand", "gtin", "mpn", "category", "price", "compare_at_price", "currency", "availability", "images", "variants", "shipping", "tax_class", "custom_labels", "metadata", "updated_at"}),
+				Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: owner, Vars: []interface{}{connectorID, connectorID}}}},
+			}).Create(product)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return errors.New("SKU belongs to another product")
+			}
+
+			// The row is either this connector's or was just inserted
+			status := "ACTIVE"
+			if s, ok := product.Metadata["status"].(string); ok && s != "" {
+				status = strings.ToUpper(s)
+			}
+			return tx.Exec(`
+				UPDATE products
+				SET connector_id = ?, status = ?, organization_id = (SELECT organization_id FROM connectors WHERE id = ?)
+				WHERE sku = ? AND (connector_id IS NULL OR connector_id = ?)
+			`, connectorID, status, connectorID, product.SKU, connectorID).Error
+		})
+		if err != nil {
+			h.logger.Error("Failed to save product %s: %v", product.SKU, err)
+			continue
+		}
+		saved++
+	}
+	return saved
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Sync started"})
+// archiveProduct soft deletes a connector's product so it drops out of feeds. Only the
+// connector's own product is touched: external IDs of different stores can collide.
+func (h *ConnectorHandler) archiveProduct(connectorID, externalID string) error {
+	return h.db.Exec(`
+		UPDATE products SET status = 'ARCHIVED', updated_at = NOW()
+		WHERE connector_id = ? AND external_id = ?
+	`, connectorID, externalID).Error
 }
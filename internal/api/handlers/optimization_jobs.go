@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"lister/internal/jobs"
+	"lister/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListJobs lists the organization's bulk optimization jobs, newest first
+// GET /api/v1/optimizer/jobs
+func (h *OptimizerHandler) ListJobs(c *gin.Context) {
+	orgUUID := h.organizationUUID(c)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	query := h.db.Model(&models.OptimizationJob{}).Where("organization_id = ?", orgUUID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var list []models.OptimizationJob
+	if err := query.Order("created_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&list).Error; err != nil {
+		h.logger.Error("Failed to fetch optimization jobs: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch optimization jobs"})
+		return
+	}
+
+	data := make([]gin.H, 0, len(list))
+	for i := range list {
+		data = append(data, jobResponse(&list[i]))
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  data,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// GetJob returns a job's progress. Its per-item results are listed with ?items=true,
+// optionally filtered by item status.
+// GET /api/v1/optimizer/jobs/:id
+func (h *OptimizerHandler) GetJob(c *gin.Context) {
+	job, ok := h.findJob(c)
+	if !ok {
+		return
+	}
+
+	response := jobResponse(job)
+	if c.Query("items") == "true" {
+		query := h.db.Where("job_id = ?", job.ID)
+		if status := c.Query("item_status"); status != "" {
+			query = query.Where("status = ?", status)
+		}
+		var items []models.OptimizationJobItem
+		if err := query.Order("completed_at NULLS LAST, id").Find(&items).Error; err != nil {
+			h.logger.Error("Failed to fetch optimization job items: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch job items"})
+			return
+		}
+		response["items"] = items
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": response})
+}
+
+// PauseJob stops a queued or running job after the items in flight
+// POST /api/v1/optimizer/jobs/:id/pause
+func (h *OptimizerHandler) PauseJob(c *gin.Context) {
+	h.setJobStatus(c, models.OptimizationJobPaused, "Optimization job paused")
+}
+
+// ResumeJob queues a paused job again; it continues with the items not yet processed
+// POST /api/v1/optimizer/jobs/:id/resume
+func (h *OptimizerHandler) ResumeJob(c *gin.Context) {
+	h.setJobStatus(c, models.OptimizationJobQueued, "Optimization job resumed")
+}
+
+// CancelJob stops a job for good; its unprocessed items are marked cancelled
+// POST /api/v1/optimizer/jobs/:id/cancel
+func (h *OptimizerHandler) CancelJob(c *gin.Context) {
+	h.setJobStatus(c, models.OptimizationJobCancelled, "Optimization job cancelled")
+}
+
+func (h *OptimizerHandler) setJobStatus(c *gin.Context, status models.OptimizationJobStatus, message string) {
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	job, err := h.jobs.SetStatus(h.organizationUUID(c), jobID, status)
+	if errors.Is(err, jobs.ErrJobNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Optimization job not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if status == models.OptimizationJobQueued {
+		h.startJob(job.ID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    jobResponse(job),
+	})
+}
+
+func (h *OptimizerHandler) findJob(c *gin.Context) (*models.OptimizationJob, bool) {
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return nil, false
+	}
+	job, err := h.jobs.Get(h.organizationUUID(c), jobID)
+	if errors.Is(err, jobs.ErrJobNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Optimization job not found"})
+		return nil, false
+	}
+	if err != nil {
+		h.logger.Error("Failed to fetch optimization job: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch optimization job"})
+		return nil, false
+	}
+	return job, true
+}
+
+// startJob runs a queued job in the API process unless a worker is deployed to pick it up
+func (h *OptimizerHandler) startJob(jobID uuid.UUID) {
+	if h.config.JobsInWorker {
+		return
+	}
+	go func() {
+		job, err := h.jobs.Claim(&jobID)
+		if err != nil {
+			h.logger.Error("Failed to claim optimization job %s: %v", jobID, err)
+			return
+		}
+		if job == nil {
+			return
+		}
+		if err := h.jobs.Run(context.Background(), job); err != nil {
+			h.logger.Error("Optimization job %s failed: %v", jobID, err)
+		}
+	}()
+}
+
+func jobResponse(job *models.OptimizationJob) gin.H {
+	return gin.H{
+		"id":                job.ID,
+		"optimization_type": job.OptimizationType,
+		"status":            job.Status,
+		"auto_apply":        job.AutoApply,
+		"concurrency":       job.Concurrency,
+		"total_items":       job.TotalItems,
+		"processed_items":   job.ProcessedItems,
+		"succeeded_items":   job.SucceededItems,
+		"failed_items":      job.FailedItems,
+		"skipped_items":     job.SkippedItems,
+		"progress":          job.Progress(),
+		"cost":              job.Cost,
+		"error_message":     job.ErrorMessage,
+		"created_at":        job.CreatedAt,
+		"started_at":        job.StartedAt,
+		"completed_at":      job.CompletedAt,
+	}
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"lister/internal/models"
+	"lister/internal/review"
+	"lister/internal/services/shopify"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxBulkRevert caps how many optimizations one bulk revert restores
+const maxBulkRevert = 1000
+
+// revertRequest is the body of a revert. PushToSource also writes the restored value back
+// to the connector the product was imported from.
+type revertRequest struct {
+	Reason       string `json:"reason"`
+	RevertedBy   string `json:"reverted_by"`
+	PushToSource bool   `json:"push_to_source"`
+}
+
+// bulkRevertRequest selects the applied optimizations of a bulk job or applied in a time
+// range
+type bulkRevertRequest struct {
+	revertRequest
+	JobID            string     `json:"job_id"`
+	From             *time.Time `json:"from"`
+	To               *time.Time `json:"to"`
+	OptimizationType string     `json:"optimization_type"`
+}
+
+// RevertOptimization restores the original value of an applied optimization
+// POST /api/v1/optimizer/:id/revert
+func (h *OptimizerHandler) RevertOptimization(c *gin.Context) {
+	var req revertRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+			return
+		}
+	}
+
+	var history models.OptimizationHistory
+	if err := h.db.First(&history, "id = ? AND organization_id = ?", c.Param("id"), h.organizationUUID(c)).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Optimization not found"})
+		return
+	}
+
+	source, err := h.revert(c, &history, req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, review.ErrNotApplied), errors.Is(err, review.ErrModified):
+			status = http.StatusConflict
+		case errors.Is(err, review.ErrNotApplicable):
+			status = http.StatusBadRequest
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			status = http.StatusNotFound
+		default:
+			h.logger.Error("Failed to revert optimization %s: %v", history.ID, err)
+		}
+		c.JSON(status, gin.H{"error": reviewError(err), "status": history.Status})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Optimization reverted",
+		"data":    history,
+		"source":  source,
+	})
+}
+
+// BulkRevert restores the original values of the optimizations applied by a bulk job or
+// within a time range, newest first so stacked optimizations of a field unwind in order.
+// Each one succeeds or fails on its own.
+// POST /api/v1/optimizer/revert
+func (h *OptimizerHandler) BulkRevert(c *gin.Context) {
+	var req bulkRevertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+	if req.JobID == "" && req.From == nil && req.To == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "job_id or a from/to time range is required"})
+		return
+	}
+
+	query := h.db.Where("organization_id = ? AND status = ?", h.organizationUUID(c), models.OptimizationStatusApplied)
+	if req.JobID != "" {
+		query = query.Where("metadata->>'job_id' = ?", req.JobID)
+	}
+	if req.From != nil {
+		query = query.Where("applied_at >= ?", *req.From)
+	}
+	if req.To != nil {
+		query = query.Where("applied_at < ?", *req.To)
+	}
+	if req.OptimizationType != "" {
+		query = query.Where("optimization_type = ?", req.OptimizationType)
+	}
+
+	var applied []models.OptimizationHistory
+	if err := query.Order("applied_at DESC").Limit(maxBulkRevert).Find(&applied).Error; err != nil {
+		h.logger.Error("Failed to fetch optimizations to revert: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch optimizations"})
+		return
+	}
+
+	results := make([]gin.H, 0, len(applied))
+	reverted := 0
+	for i := range applied {
+		history := &applied[i]
+		source, err := h.revert(c, history, req.revertRequest)
+		item := gin.H{"id": history.ID, "product_id": history.ProductID, "status": history.Status}
+		if err != nil {
+			item["status"] = "failed"
+			item["error"] = reviewError(err)
+		} else {
+			reverted++
+			if source != nil {
+				item["source"] = source
+			}
+		}
+		results = append(results, item)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reverted": reverted,
+		"failed":   len(applied) - reverted,
+		"results":  results,
+	})
+}
+
+// revert reverts one optimization and, when asked, pushes the restored value to its
+// source. It returns the push outcome, or nil when nothing was pushed; a failed push
+// doesn't undo the revert.
+func (h *OptimizerHandler) revert(c *gin.Context, history *models.OptimizationHistory, req revertRequest) (gin.H, error) {
+	decision := review.Decision{Reason: req.Reason, Reviewer: h.reviewer(c, req.RevertedBy)}
+	if err := review.Revert(h.db, history, decision); err != nil {
+		return nil, err
+	}
+	h.logger.Info("Reverted %s optimization %s of product %s", history.OptimizationType, history.ID, history.ProductID)
+
+	if !req.PushToSource {
+		return nil, nil
+	}
+	if err := h.pushToSource(history); err != nil {
+		h.logger.Error("Failed to push reverted optimization %s to its source: %v", history.ID, err)
+		return gin.H{"pushed": false, "error": err.Error()}, nil
+	}
+	return gin.H{"pushed": true}, nil
+}
+
+// pushToSource writes an optimization's original value back to the connector the product
+// was imported from. Only Shopify connectors accept product updates.
+func (h *OptimizerHandler) pushToSource(history *models.OptimizationHistory) error {
+	var product struct {
+		ConnectorID *string
+		ExternalID  string
+	}
+	err := h.db.Table("products").Select("connector_id, external_id").
+		Where("id = ?", history.ProductID).Take(&product).Error
+	if err != nil {
+		return err
+	}
+	if product.ConnectorID == nil || *product.ConnectorID == "" {
+		return errors.New("product has no source connector")
+	}
+
+	var connector models.Connector
+	if err := h.db.First(&connector, "id = ?", *product.ConnectorID).Error; err != nil {
+		return fmt.Errorf("source connector not found: %w", err)
+	}
+	if connector.Type != models.ConnectorTypeShopify {
+		return fmt.Errorf("pushing to %s connectors is not supported", connector.Type)
+	}
+
+	accessToken, _ := connector.Credentials["access_token"].(string)
+	shopDomain, _ := connector.Config["shop_domain"].(string)
+	if accessToken == "" || shopDomain == "" {
+		return errors.New("shopify connector credentials are incomplete")
+	}
+	productID, err := strconv.ParseInt(strings.TrimPrefix(product.ExternalID, "shopify_"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid shopify product id %q", product.ExternalID)
+	}
+
+	field := map[models.OptimizationType]string{
+		models.OptimizationTypeTitle:       "title",
+		models.OptimizationTypeDescription: "body_html",
+		models.OptimizationTypeCategory:    "product_type",
+	}[history.OptimizationType]
+	if field == "" {
+		return review.ErrNotApplicable
+	}
+
+	client := shopify.NewClient(shopDomain, accessToken, h.logger)
+	return client.UpdateProductFields(productID, map[string]interface{}{field: history.OriginalValue})
+}
@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"lister/internal/attributes"
+	"lister/internal/models"
+	"lister/internal/review"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// reviewRequest is the body of approve, reject and apply requests. OptimizedValue edits the
+// suggestion before it is approved.
+type reviewRequest struct {
+	OptimizedValue string `json:"optimized_value"`
+	Reason         string `json:"reason"`
+	ReviewedBy     string `json:"reviewed_by"`
+}
+
+// bulkReviewRequest approves or rejects several optimizations with one reason
+type bulkReviewRequest struct {
+	IDs        []string `json:"ids" binding:"required"`
+	Action     string   `json:"action" binding:"required"`
+	Reason     string   `json:"reason"`
+	ReviewedBy string   `json:"reviewed_by"`
+}
+
+// ListReview lists pending optimizations with a before/after diff against the product's
+// current value
+// GET /api/v1/optimizer/review
+func (h *OptimizerHandler) ListReview(c *gin.Context) {
+	orgUUID := h.organizationUUID(c)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	query := h.db.Model(&models.OptimizationHistory{}).
+		Where("organization_id = ? AND status = ?", orgUUID, models.OptimizationStatusPending)
+	if t := c.Query("type"); t != "" {
+		query = query.Where("optimization_type = ?", t)
+	}
+	if productID := c.Query("product_id"); productID != "" {
+		query = query.Where("product_id = ?", productID)
+	}
+	if minScore, err := strconv.Atoi(c.Query("min_score")); err == nil {
+		query = query.Where("score >= ?", minScore)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var pending []models.OptimizationHistory
+	if err := query.Order("created_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&pending).Error; err != nil {
+		h.logger.Error("Failed to fetch review queue: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch review queue"})
+		return
+	}
+
+	productIDs := make([]uuid.UUID, 0, len(pending))
+	for _, history := range pending {
+		productIDs = append(productIDs, history.ProductID)
+	}
+	var products []models.Product
+	if len(productIDs) > 0 {
+		h.db.Where("id IN ?", productIDs).Find(&products)
+	}
+	byID := make(map[string]*models.Product, len(products))
+	for i := range products {
+		byID[products[i].ID] = &products[i]
+	}
+
+	data := make([]gin.H, 0, len(pending))
+	for i := range pending {
+		data = append(data, reviewItem(&pending[i], byID[pending[i].ProductID.String()]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  data,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// ApproveOptimization applies a pending optimization to its product, optionally with an
+// edited value
+// POST /api/v1/optimizer/:id/approve
+func (h *OptimizerHandler) ApproveOptimization(c *gin.Context) {
+	h.decide(c, true)
+}
+
+// RejectOptimization rejects a pending optimization with a reason
+// POST /api/v1/optimizer/:id/reject
+func (h *OptimizerHandler) RejectOptimization(c *gin.Context) {
+	h.decide(c, false)
+}
+
+// BulkReview approves or rejects several pending optimizations. Each one succeeds or fails
+// on its own.
+// POST /api/v1/optimizer/review/bulk
+func (h *OptimizerHandler) BulkReview(c *gin.Context) {
+	var req bulkReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+	if req.Action != "approve" && req.Action != "reject" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "action must be approve or reject"})
+		return
+	}
+
+	orgUUID := h.organizationUUID(c)
+	decision := review.Decision{Reason: req.Reason, Reviewer: h.reviewer(c, req.ReviewedBy)}
+
+	results := make([]gin.H, 0, len(req.IDs))
+	succeeded := 0
+	for _, id := range req.IDs {
+		var history models.OptimizationHistory
+		err := h.db.First(&history, "id = ? AND organization_id = ?", id, orgUUID).Error
+		if err == nil {
+			if req.Action == "approve" {
+				err = h.approve(&history, decision)
+			} else {
+				err = review.Reject(h.db, &history, decision)
+			}
+		}
+
+		if err != nil {
+			results = append(results, gin.H{"id": id, "status": "failed", "error": reviewError(err)})
+			continue
+		}
+		succeeded++
+		results = append(results, gin.H{"id": id, "status": history.Status})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"action":    req.Action,
+		"succeeded": succeeded,
+		"failed":    len(req.IDs) - succeeded,
+		"results":   results,
+	})
+}
+
+// decide approves or rejects the optimization in the URL
+func (h *OptimizerHandler) decide(c *gin.Context, approve bool) {
+	var req reviewRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+			return
+		}
+	}
+
+	var history models.OptimizationHistory
+	if err := h.db.First(&history, "id = ? AND organization_id = ?", c.Param("id"), h.organizationUUID(c)).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Optimization not found"})
+		return
+	}
+
+	decision := review.Decision{Reason: req.Reason, Reviewer: h.reviewer(c, req.ReviewedBy)}
+	var err error
+	message := "Optimization rejected"
+	if approve {
+		decision.Value = req.OptimizedValue
+		err = h.approve(&history, decision)
+		message = "Optimization applied successfully"
+	} else {
+		err = review.Reject(h.db, &history, decision)
+	}
+
+	switch {
+	case errors.Is(err, review.ErrNotPending):
+		c.JSON(http.StatusConflict, gin.H{"error": reviewError(err), "status": history.Status})
+		return
+	case errors.Is(err, review.ErrNotApplicable), errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": reviewError(err)})
+		return
+	case err != nil:
+		h.logger.Error("Failed to review optimization %s: %v", history.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to review optimization"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    history,
+	})
+}
+
+// approve applies a pending optimization after checking its product still exists
+func (h *OptimizerHandler) approve(history *models.OptimizationHistory, decision review.Decision) error {
+	var product models.Product
+	if err := h.db.Select("id").First(&product, "id = ?", history.ProductID).Error; err != nil {
+		return err
+	}
+	if err := review.Apply(h.db, history, decision); err != nil {
+		return err
+	}
+	h.updateCreditsSuccess(history.OrganizationID)
+	return nil
+}
+
+// reviewer returns who made a review decision: the authenticated user, else the name
+// given in the request
+func (h *OptimizerHandler) reviewer(c *gin.Context, requested string) string {
+	if userID := c.GetString("user_id"); userID != "" {
+		return userID
+	}
+	return requested
+}
+
+// reviewError describes a failed review decision for API responses
+func reviewError(err error) string {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "Optimization or product not found"
+	}
+	return err.Error()
+}
+
+// reviewItem is a pending optimization with the product's current value and the diff
+// from it to the suggestion
+func reviewItem(history *models.OptimizationHistory, product *models.Product) gin.H {
+	current := history.OriginalValue
+	if product != nil {
+		switch history.OptimizationType {
+		case models.OptimizationTypeTitle:
+			current = product.Title
+		case models.OptimizationTypeDescription:
+			current = ""
+			if product.Description != nil {
+				current = *product.Description
+			}
+		case models.OptimizationTypeCategory:
+			current = ""
+			if product.Category != nil {
+				current = *product.Category
+			}
+		case models.OptimizationTypeAttribute:
+			current = attributes.Current(product.Metadata)[review.Attribute(history)]
+		}
+	}
+
+	diff := review.Diff(current, history.OptimizedValue)
+	added, removed := review.Summary(diff)
+	return gin.H{
+		"optimization":  history,
+		"before":        current,
+		"after":         history.OptimizedValue,
+		"diff":          diff,
+		"words_added":   added,
+		"words_removed": removed,
+		// The product was edited after the suggestion was made
+		"stale":          product != nil && current != history.OriginalValue,
+		"product_exists": product != nil,
+	}
+}
@@ -6,12 +6,15 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 
 	"lister/internal/config"
+	"lister/internal/jobs"
+	"lister/internal/llm"
 	"lister/internal/logger"
 	"lister/internal/models"
+	"lister/internal/review"
+	"lister/internal/taxonomy"
 	"lister/internal/worker/processors/ai"
 
 	"github.com/gin-gonic/gin"
@@ -24,15 +27,18 @@ type OptimizerHandler struct {
 	db        *gorm.DB
 	logger    *logger.Logger
 	optimizer *ai.Optimizer
+	jobs      *jobs.BulkOptimizer
 	config    *config.Config
 }
 
 // NewOptimizerHandler creates a new optimizer handler
 func NewOptimizerHandler(db *gorm.DB, log *logger.Logger, cfg *config.Config) *OptimizerHandler {
+	optimizer := ai.New(cfg, log)
 	return &OptimizerHandler{
 		db:        db,
 		logger:    log,
-		optimizer: ai.New(cfg, log),
+		optimizer: optimizer,
+		jobs:      jobs.NewBulkOptimizer(db, log, optimizer),
 		config:    cfg,
 	}
 }
@@ -60,13 +66,6 @@ func (h *OptimizerHandler) OptimizeTitle(c *gin.Context) {
 		return
 	}
 
-	// Check AI credits
-	if err := h.checkAndDeductCredits(orgUUID, 1); err != nil {
-		h.logger.Info("Insufficient AI credits for organization: %s", organizationID)
-		c.JSON(http.StatusPaymentRequired, gin.H{"error": "Insufficient AI credits", "details": err.Error()})
-		return
-	}
-
 	// Get product
 	productUUID, err := uuid.Parse(req.ProductID)
 	if err != nil {
@@ -118,14 +117,49 @@ func (h *OptimizerHandler) OptimizeTitle(c *gin.Context) {
 		"instructions": req.CustomInstructions,
 	}
 
+	// Identical requests are answered from the cache without using credits
+	optimizer := h.optimizerFor(orgUUID, settings, h.monthlyBudget(orgUUID, settings))
+	cacheKey := optimizer.CacheKey(models.OptimizationTypeTitle, productData)
+	if cached := h.fromCache(settings, cacheKey, req.NoCache, productUUID, product.Title); cached != nil {
+		score, improvement := *cached.Score, *cached.ImprovementPercentage
+		c.JSON(http.StatusOK, models.OptimizationResponse{
+			OptimizationID:   cached.ID.String(),
+			ProductID:        req.ProductID,
+			OptimizationType: string(models.OptimizationTypeTitle),
+			OriginalValue:    product.Title,
+			OptimizedValue:   cached.OptimizedValue,
+			Score:            score,
+			Improvement:      improvement,
+			AIModel:          cached.AIModel,
+			Status:           string(cached.Status),
+			Message:          "Title optimized successfully (cached)",
+			Metadata: map[string]interface{}{
+				"cache_hit":       true,
+				"character_count": len(cached.OptimizedValue),
+			},
+		})
+		return
+	}
+
+	// Check AI credits
+	if err := h.checkAndDeductCredits(orgUUID, 1); err != nil {
+		h.logger.Info("Insufficient AI credits for organization: %s", organizationID)
+		c.JSON(http.StatusPaymentRequired, gin.H{"error": "Insufficient AI credits", "details": err.Error()})
+		return
+	}
+
 	// Call AI optimizer
 	startTime := time.Now()
-	optimizedTitle, err := h.optimizer.OptimizeTitle(productData)
+	optimizedTitle, err := optimizer.OptimizeTitle(productData)
 	duration := time.Since(startTime)
 
-	// Calculate cost (approximate based on tokens)
-	estimatedTokens := len(product.Title) + len(description) + 200
-	cost := h.calculateCost(settings.DefaultModel, estimatedTokens)
+	// Record the tokens the provider reported
+	aiModel, usage, cost := h.recordUsage(orgUUID, string(models.OptimizationTypeTitle), optimizer, settings.DefaultModel)
+	if errors.Is(err, llm.ErrBudgetExceeded) {
+		h.refundCredits(orgUUID, 1)
+		c.JSON(http.StatusPaymentRequired, gin.H{"error": "Monthly AI budget exceeded", "details": err.Error()})
+		return
+	}
 
 	// Create optimization history record
 	history := &models.OptimizationHistory{
@@ -135,17 +169,20 @@ func (h *OptimizerHandler) OptimizeTitle(c *gin.Context) {
 		OriginalValue:    product.Title,
 		OptimizedValue:   optimizedTitle,
 		Status:           models.OptimizationStatusPending,
-		AIModel:          settings.DefaultModel,
+		AIModel:          aiModel,
 		Cost:             cost,
-		TokensUsed:       estimatedTokens,
+		TokensUsed:       usage.TotalTokens,
 		Metadata: models.JSONB{
-			"strategy":     req.Strategy,
-			"keywords":     req.Keywords,
-			"max_length":   req.MaxLength,
-			"duration_ms":  duration.Milliseconds(),
-			"instructions": req.CustomInstructions,
+			"strategy":          req.Strategy,
+			"keywords":          req.Keywords,
+			"max_length":        req.MaxLength,
+			"duration_ms":       duration.Milliseconds(),
+			"instructions":      req.CustomInstructions,
+			"prompt_tokens":     usage.PromptTokens,
+			"completion_tokens": usage.CompletionTokens,
 		},
 	}
+	history.SetPrompt(optimizer.Template(models.OptimizationTypeTitle))
 
 	if err != nil {
 		h.logger.Error("Title optimization failed: %v", err)
@@ -166,8 +203,7 @@ func (h *OptimizerHandler) OptimizeTitle(c *gin.Context) {
 	}
 
 	// Calculate score and improvement
-	score := h.calculateTitleScore(optimizedTitle, product.Title)
-	improvement := h.calculateImprovement(product.Title, optimizedTitle)
+	score, improvement := review.Score(models.OptimizationTypeTitle, product.Title, optimizedTitle)
 	history.Score = &score
 	history.ImprovementPercentage = &improvement
 
@@ -175,9 +211,11 @@ func (h *OptimizerHandler) OptimizeTitle(c *gin.Context) {
 	if err := h.db.Create(history).Error; err != nil {
 		h.logger.Error("Failed to save optimization history: %v", err)
 	}
+	h.autoApply(settings, history)
 
 	// Update AI credits with cost
 	h.updateCreditsCost(orgUUID, cost, true)
+	h.cacheResponse(settings, cacheKey, optimizer.Template(models.OptimizationTypeTitle), aiModel, optimizedTitle)
 
 	// Prepare response
 	response := models.OptimizationResponse{
@@ -189,8 +227,8 @@ func (h *OptimizerHandler) OptimizeTitle(c *gin.Context) {
 		Score:            score,
 		Improvement:      improvement,
 		Cost:             cost,
-		TokensUsed:       estimatedTokens,
-		AIModel:          settings.DefaultModel,
+		TokensUsed:       usage.TotalTokens,
+		AIModel:          aiModel,
 		Status:           string(history.Status),
 		Message:          "Title optimized successfully",
 		Metadata: map[string]interface{}{
@@ -218,12 +256,6 @@ func (h *OptimizerHandler) OptimizeDescription(c *gin.Context) {
 
 	orgUUID, _ := uuid.Parse(organizationID)
 
-	// Check credits
-	if err := h.checkAndDeductCredits(orgUUID, 2); err != nil {
-		c.JSON(http.StatusPaymentRequired, gin.H{"error": "Insufficient AI credits"})
-		return
-	}
-
 	// Get product
 	productUUID, _ := uuid.Parse(req.ProductID)
 	var product models.Product
@@ -264,13 +296,43 @@ func (h *OptimizerHandler) OptimizeDescription(c *gin.Context) {
 		"instructions":    req.CustomInstructions,
 	}
 
+	// Identical requests are answered from the cache without using credits
+	optimizer := h.optimizerFor(orgUUID, settings, h.monthlyBudget(orgUUID, settings))
+	cacheKey := optimizer.CacheKey(models.OptimizationTypeDescription, productData)
+	if cached := h.fromCache(settings, cacheKey, req.NoCache, productUUID, description); cached != nil {
+		c.JSON(http.StatusOK, models.OptimizationResponse{
+			OptimizationID:   cached.ID.String(),
+			ProductID:        req.ProductID,
+			OptimizationType: string(models.OptimizationTypeDescription),
+			OriginalValue:    description,
+			OptimizedValue:   cached.OptimizedValue,
+			Score:            *cached.Score,
+			Improvement:      *cached.ImprovementPercentage,
+			AIModel:          cached.AIModel,
+			Status:           string(cached.Status),
+			Message:          "Description optimized successfully (cached)",
+			Metadata:         map[string]interface{}{"cache_hit": true},
+		})
+		return
+	}
+
+	// Check credits
+	if err := h.checkAndDeductCredits(orgUUID, 2); err != nil {
+		c.JSON(http.StatusPaymentRequired, gin.H{"error": "Insufficient AI credits"})
+		return
+	}
+
 	// Optimize description
 	startTime := time.Now()
-	optimizedDesc, err := h.optimizer.OptimizeDescription(productData)
+	optimizedDesc, err := optimizer.OptimizeDescription(productData)
 	duration := time.Since(startTime)
 
-	estimatedTokens := len(description) + 300
-	cost := h.calculateCost(settings.DefaultModel, estimatedTokens)
+	aiModel, usage, cost := h.recordUsage(orgUUID, string(models.OptimizationTypeDescription), optimizer, settings.DefaultModel)
+	if errors.Is(err, llm.ErrBudgetExceeded) {
+		h.refundCredits(orgUUID, 2)
+		c.JSON(http.StatusPaymentRequired, gin.H{"error": "Monthly AI budget exceeded", "details": err.Error()})
+		return
+	}
 
 	// Create history record
 	history := &models.OptimizationHistory{
@@ -280,16 +342,19 @@ func (h *OptimizerHandler) OptimizeDescription(c *gin.Context) {
 		OriginalValue:    description,
 		OptimizedValue:   optimizedDesc,
 		Status:           models.OptimizationStatusPending,
-		AIModel:          settings.DefaultModel,
+		AIModel:          aiModel,
 		Cost:             cost,
-		TokensUsed:       estimatedTokens,
+		TokensUsed:       usage.TotalTokens,
 		Metadata: models.JSONB{
-			"style":           req.Style,
-			"length":          req.Length,
-			"target_audience": req.TargetAudience,
-			"duration_ms":     duration.Milliseconds(),
+			"style":             req.Style,
+			"length":            req.Length,
+			"target_audience":   req.TargetAudience,
+			"duration_ms":       duration.Milliseconds(),
+			"prompt_tokens":     usage.PromptTokens,
+			"completion_tokens": usage.CompletionTokens,
 		},
 	}
+	history.SetPrompt(optimizer.Template(models.OptimizationTypeDescription))
 
 	if err != nil {
 		h.logger.Error("Description optimization failed: %v", err)
@@ -301,13 +366,14 @@ func (h *OptimizerHandler) OptimizeDescription(c *gin.Context) {
 		return
 	}
 
-	score := h.calculateDescriptionScore(optimizedDesc)
-	improvement := h.calculateImprovement(description, optimizedDesc)
+	score, improvement := review.Score(models.OptimizationTypeDescription, description, optimizedDesc)
 	history.Score = &score
 	history.ImprovementPercentage = &improvement
 
 	h.db.Create(history)
+	h.autoApply(settings, history)
 	h.updateCreditsCost(orgUUID, cost, true)
+	h.cacheResponse(settings, cacheKey, optimizer.Template(models.OptimizationTypeDescription), aiModel, optimizedDesc)
 
 	response := models.OptimizationResponse{
 		OptimizationID:   history.ID.String(),
@@ -318,8 +384,8 @@ func (h *OptimizerHandler) OptimizeDescription(c *gin.Context) {
 		Score:            score,
 		Improvement:      improvement,
 		Cost:             cost,
-		TokensUsed:       estimatedTokens,
-		AIModel:          settings.DefaultModel,
+		TokensUsed:       usage.TotalTokens,
+		AIModel:          aiModel,
 		Status:           string(history.Status),
 		Message:          "Description optimized successfully",
 	}
@@ -343,12 +409,6 @@ func (h *OptimizerHandler) SuggestCategory(c *gin.Context) {
 
 	orgUUID, _ := uuid.Parse(organizationID)
 
-	// Check credits
-	if err := h.checkAndDeductCredits(orgUUID, 1); err != nil {
-		c.JSON(http.StatusPaymentRequired, gin.H{"error": "Insufficient AI credits"})
-		return
-	}
-
 	// Get product
 	productUUID, _ := uuid.Parse(req.ProductID)
 	var product models.Product
@@ -385,13 +445,40 @@ func (h *OptimizerHandler) SuggestCategory(c *gin.Context) {
 		"category":    category,
 	}
 
+	// Identical requests are answered from the cache without using credits
+	optimizer := h.optimizerFor(orgUUID, settings, h.monthlyBudget(orgUUID, settings))
+	cacheKey := optimizer.CacheKey(models.OptimizationTypeCategory, productData)
+	if cached := h.fromCache(settings, cacheKey, req.NoCache, productUUID, category); cached != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"optimization_id":  cached.ID.String(),
+			"product_id":       req.ProductID,
+			"current_category": product.Category,
+			"suggestions": []map[string]interface{}{
+				{
+					"category":   cached.OptimizedValue,
+					"confidence": 95,
+					"channels":   []string{"Google Shopping", "Facebook", "Instagram"},
+				},
+			},
+			"cost":      0.0,
+			"cache_hit": true,
+			"message":   "Category suggestions generated successfully (cached)",
+		})
+		return
+	}
+
+	// Check credits
+	if err := h.checkAndDeductCredits(orgUUID, 1); err != nil {
+		c.JSON(http.StatusPaymentRequired, gin.H{"error": "Insufficient AI credits"})
+		return
+	}
+
 	// Suggest category
 	startTime := time.Now()
-	suggestedCategory, err := h.optimizer.SuggestCategory(productData)
+	suggestedCategory, err := optimizer.SuggestCategory(productData)
 	duration := time.Since(startTime)
 
-	estimatedTokens := 150
-	cost := h.calculateCost(settings.DefaultModel, estimatedTokens)
+	aiModel, usage, cost := h.recordUsage(orgUUID, string(models.OptimizationTypeCategory), optimizer, settings.DefaultModel)
 
 	// Create history
 	history := &models.OptimizationHistory{
@@ -401,13 +488,14 @@ func (h *OptimizerHandler) SuggestCategory(c *gin.Context) {
 		OriginalValue:    category,
 		OptimizedValue:   suggestedCategory,
 		Status:           models.OptimizationStatusPending,
-		AIModel:          settings.DefaultModel,
+		AIModel:          aiModel,
 		Cost:             cost,
-		TokensUsed:       estimatedTokens,
+		TokensUsed:       usage.TotalTokens,
 		Metadata: models.JSONB{
 			"duration_ms": duration.Milliseconds(),
 		},
 	}
+	history.SetPrompt(optimizer.Template(models.OptimizationTypeCategory))
 
 	if err != nil {
 		h.logger.Error("Category suggestion failed: %v", err)
@@ -419,18 +507,26 @@ func (h *OptimizerHandler) SuggestCategory(c *gin.Context) {
 		return
 	}
 
-	score := 85 // Default score for category
+	score, improvement := review.Score(models.OptimizationTypeCategory, category, suggestedCategory)
 	history.Score = &score
+	history.ImprovementPercentage = &improvement
+	categoryID := h.googleCategoryID(suggestedCategory)
+	if categoryID != 0 {
+		history.Metadata["category_id"] = categoryID
+	}
 
 	h.db.Create(history)
+	h.autoApply(settings, history)
 	h.updateCreditsCost(orgUUID, cost, true)
+	h.cacheResponse(settings, cacheKey, optimizer.Template(models.OptimizationTypeCategory), aiModel, suggestedCategory)
 
 	// Generate multiple suggestions (mock for now)
 	suggestions := []map[string]interface{}{
 		{
-			"category":   suggestedCategory,
-			"confidence": 95,
-			"channels":   []string{"Google Shopping", "Facebook", "Instagram"},
+			"category":    suggestedCategory,
+			"category_id": categoryID,
+			"confidence":  95,
+			"channels":    []string{"Google Shopping", "Facebook", "Instagram"},
 		},
 	}
 
@@ -508,7 +604,8 @@ func (h *OptimizerHandler) AnalyzeImages(c *gin.Context) {
 		},
 	}
 
-	cost := h.calculateCost("gpt-4-vision", 1000)
+	// The analysis above is not produced by a model yet, so nothing is billed
+	cost := 0.0
 
 	// Create history
 	history := &models.OptimizationHistory{
@@ -518,9 +615,9 @@ func (h *OptimizerHandler) AnalyzeImages(c *gin.Context) {
 		OriginalValue:    fmt.Sprintf("%d images", len(images)),
 		OptimizedValue:   "Image analysis completed",
 		Status:           models.OptimizationStatusPending,
-		AIModel:          "gpt-4-vision",
+		AIModel:          llm.ProviderMock,
 		Cost:             cost,
-		TokensUsed:       1000,
+		TokensUsed:       0,
 		Metadata:         models.JSONB(analysis),
 	}
 
@@ -536,7 +633,8 @@ func (h *OptimizerHandler) AnalyzeImages(c *gin.Context) {
 	})
 }
 
-// BulkOptimize performs bulk optimization on multiple products
+// BulkOptimize queues a bulk optimization job. Products are processed in the background;
+// poll GET /optimizer/jobs/:id for progress.
 // POST /api/v1/optimizer/bulk
 func (h *OptimizerHandler) BulkOptimize(c *gin.Context) {
 	var req models.BulkOptimizationRequest
@@ -544,6 +642,12 @@ func (h *OptimizerHandler) BulkOptimize(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
 		return
 	}
+	switch req.OptimizationType {
+	case models.OptimizationTypeTitle, models.OptimizationTypeDescription, models.OptimizationTypeCategory:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported optimization type"})
+		return
+	}
 
 	organizationID := c.GetString("organization_id")
 	if organizationID == "" {
@@ -552,116 +656,31 @@ func (h *OptimizerHandler) BulkOptimize(c *gin.Context) {
 
 	orgUUID, _ := uuid.Parse(organizationID)
 
-	// Check credits (bulk operations require more credits)
-	creditsNeeded := len(req.ProductIDs) * 2
-	if err := h.checkAndDeductCredits(orgUUID, creditsNeeded); err != nil {
+	// Credits are charged per distinct product, in the transaction queuing the job
+	creditsNeeded := 0
+	var chargeErr error
+	job, err := h.jobs.Enqueue(orgUUID, &req, func(tx *gorm.DB, items int) error {
+		creditsNeeded = items * jobs.CreditsPerItem
+		chargeErr = h.deductCredits(tx, orgUUID, creditsNeeded)
+		return chargeErr
+	})
+	if chargeErr != nil {
 		c.JSON(http.StatusPaymentRequired, gin.H{
 			"error":          "Insufficient AI credits",
 			"credits_needed": creditsNeeded,
 		})
 		return
 	}
-
-	// Process each product
-	results := make([]map[string]interface{}, 0)
-	successCount := 0
-
-	for _, productID := range req.ProductIDs {
-		productUUID, err := uuid.Parse(productID)
-		if err != nil {
-			results = append(results, map[string]interface{}{
-				"product_id": productID,
-				"status":     "failed",
-				"error":      "Invalid product ID",
-			})
-			continue
-		}
-
-		var product models.Product
-		if err := h.db.First(&product, "id = ?", productUUID).Error; err != nil {
-			results = append(results, map[string]interface{}{
-				"product_id": productID,
-				"status":     "failed",
-				"error":      "Product not found",
-			})
-			continue
-		}
-
-		// Get string values from pointers
-		description := ""
-		if product.Description != nil {
-			description = *product.Description
-		}
-		brand := ""
-		if product.Brand != nil {
-			brand = *product.Brand
-		}
-		category := ""
-		if product.Category != nil {
-			category = *product.Category
-		}
-
-		// Perform optimization based on type
-		var optimizedValue string
-		var optimizationErr error
-
-		productData := map[string]interface{}{
-			"title":       product.Title,
-			"description": description,
-			"brand":       brand,
-			"category":    category,
-		}
-
-		switch req.OptimizationType {
-		case models.OptimizationTypeTitle:
-			optimizedValue, optimizationErr = h.optimizer.OptimizeTitle(productData)
-		case models.OptimizationTypeDescription:
-			optimizedValue, optimizationErr = h.optimizer.OptimizeDescription(productData)
-		case models.OptimizationTypeCategory:
-			optimizedValue, optimizationErr = h.optimizer.SuggestCategory(productData)
-		default:
-			optimizationErr = errors.New("unsupported optimization type")
-		}
-
-		if optimizationErr != nil {
-			results = append(results, map[string]interface{}{
-				"product_id": productID,
-				"status":     "failed",
-				"error":      optimizationErr.Error(),
-			})
-			continue
-		}
-
-		// Save optimization history
-		history := &models.OptimizationHistory{
-			ProductID:        productUUID,
-			OrganizationID:   orgUUID,
-			OptimizationType: req.OptimizationType,
-			OriginalValue:    product.Title, // Adjust based on type
-			OptimizedValue:   optimizedValue,
-			Status:           models.OptimizationStatusPending,
-			AIModel:          "gpt-3.5-turbo",
-			Cost:             0.002,
-			TokensUsed:       200,
-		}
-
-		h.db.Create(history)
-		successCount++
-
-		results = append(results, map[string]interface{}{
-			"product_id":      productID,
-			"status":          "success",
-			"optimization_id": history.ID.String(),
-			"optimized_value": optimizedValue,
-		})
+	if err != nil {
+		h.logger.Error("Failed to queue bulk optimization: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to queue bulk optimization", "details": err.Error()})
+		return
 	}
+	h.startJob(job.ID)
 
-	c.JSON(http.StatusOK, gin.H{
-		"processed_products": len(req.ProductIDs),
-		"success_count":      successCount,
-		"failed_count":       len(req.ProductIDs) - successCount,
-		"results":            results,
-		"message":            "Bulk optimization completed",
+	c.JSON(http.StatusAccepted, gin.H{
+		"job":     job,
+		"message": fmt.Sprintf("Bulk optimization of %d products queued", job.TotalItems),
 	})
 }
 
@@ -696,6 +715,9 @@ func (h *OptimizerHandler) GetHistory(c *gin.Context) {
 	if status != "" {
 		query = query.Where("status = ?", status)
 	}
+	if version := c.Query("prompt_version"); version != "" {
+		query = query.Where("prompt_version = ?", version)
+	}
 
 	// Get total count
 	var total int64
@@ -891,64 +913,51 @@ func (h *OptimizerHandler) GetCredits(c *gin.Context) {
 		h.db.Save(&credits)
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": credits})
-}
-
-// ApplyOptimization applies an optimization to a product
-// POST /api/v1/optimizer/:id/apply
-func (h *OptimizerHandler) ApplyOptimization(c *gin.Context) {
-	optimizationID := c.Param("id")
-
-	orgID := c.GetString("organization_id")
-	if orgID == "" {
-		orgID = "00000000-0000-0000-0000-000000000000"
+	settings, err := h.getAISettings(orgUUID)
+	if err != nil {
+		settings = h.getDefaultAISettings(orgUUID)
 	}
+	budget := h.monthlyBudget(orgUUID, settings)
 
-	var history models.OptimizationHistory
-	if err := h.db.First(&history, "id = ?", optimizationID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Optimization not found"})
-		return
-	}
+	c.JSON(http.StatusOK, gin.H{
+		"data": credits,
+		"budget": gin.H{
+			"max_cost_per_month": budget.Limit,
+			"spent_this_month":   budget.Spent(),
+		},
+	})
+}
 
-	// Update product with optimized value
-	var product models.Product
-	if err := h.db.First(&product, "id = ?", history.ProductID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
-		return
+// ClearCache removes cached optimization results so the next requests call the model again
+// DELETE /api/v1/optimizer/cache?type=title
+func (h *OptimizerHandler) ClearCache(c *gin.Context) {
+	organizationID := c.GetString("organization_id")
+	if organizationID == "" {
+		organizationID = "00000000-0000-0000-0000-000000000000"
 	}
 
-	// Apply based on type
-	switch history.OptimizationType {
-	case models.OptimizationTypeTitle:
-		product.Title = history.OptimizedValue
-	case models.OptimizationTypeDescription:
-		descValue := history.OptimizedValue
-		product.Description = &descValue
-	case models.OptimizationTypeCategory:
-		catValue := history.OptimizedValue
-		product.Category = &catValue
-	}
+	orgUUID, _ := uuid.Parse(organizationID)
 
-	if err := h.db.Save(&product).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply optimization"})
+	removed, err := ai.PurgeCache(h.db, orgUUID, models.OptimizationType(c.Query("type")))
+	if err != nil {
+		h.logger.Error("Failed to clear AI response cache: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clear cache"})
 		return
 	}
 
-	// Update history status
-	now := time.Now()
-	history.Status = models.OptimizationStatusApplied
-	history.AppliedAt = &now
-	h.db.Save(&history)
-
-	// Update credits statistics
-	h.updateCreditsSuccess(history.OrganizationID)
-
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Optimization applied successfully",
-		"data":    history,
+		"removed": removed,
+		"message": fmt.Sprintf("Removed %d cached optimizations", removed),
 	})
 }
 
+// ApplyOptimization applies an optimization to a product. It approves the optimization,
+// see ApproveOptimization.
+// POST /api/v1/optimizer/:id/apply
+func (h *OptimizerHandler) ApplyOptimization(c *gin.Context) {
+	h.ApproveOptimization(c)
+}
+
 // Helper methods
 
 func (h *OptimizerHandler) getAISettings(organizationID uuid.UUID) (*models.AISettings, error) {
@@ -960,25 +969,17 @@ func (h *OptimizerHandler) getAISettings(organizationID uuid.UUID) (*models.AISe
 }
 
 func (h *OptimizerHandler) getDefaultAISettings(organizationID uuid.UUID) *models.AISettings {
-	return &models.AISettings{
-		OrganizationID:          organizationID,
-		DefaultModel:            "gpt-3.5-turbo",
-		MaxTokens:               500,
-		Temperature:             0.7,
-		TopP:                    0.9,
-		TitleOptimization:       true,
-		DescriptionOptimization: true,
-		CategoryOptimization:    true,
-		ImageOptimization:       true,
-		MinScoreThreshold:       80,
-		RequireApproval:         true,
-		MaxRetries:              3,
-	}
+	return models.DefaultAISettings(organizationID)
 }
 
 func (h *OptimizerHandler) checkAndDeductCredits(organizationID uuid.UUID, amount int) error {
+	return h.deductCredits(h.db, organizationID, amount)
+}
+
+// deductCredits charges amount credits through db, which may be a transaction
+func (h *OptimizerHandler) deductCredits(db *gorm.DB, organizationID uuid.UUID, amount int) error {
 	var credits models.AICredits
-	if err := h.db.Where("organization_id = ?", organizationID).First(&credits).Error; err != nil {
+	if err := db.Where("organization_id = ?", organizationID).First(&credits).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			// Initialize credits
 			credits = models.AICredits{
@@ -987,7 +988,7 @@ func (h *OptimizerHandler) checkAndDeductCredits(organizationID uuid.UUID, amoun
 				CreditsTotal:     2500,
 				ResetDate:        time.Now().AddDate(0, 1, 0),
 			}
-			h.db.Create(&credits)
+			db.Create(&credits)
 			return nil
 		}
 		return err
@@ -1004,7 +1005,21 @@ func (h *OptimizerHandler) checkAndDeductCredits(organizationID uuid.UUID, amoun
 	}
 
 	credits.TotalOptimizations++
-	return h.db.Save(&credits).Error
+	return db.Save(&credits).Error
+}
+
+// refundCredits returns credits deducted for a request the monthly budget refused. It
+// updates in place, as requests of the same organization may finish at once.
+func (h *OptimizerHandler) refundCredits(organizationID uuid.UUID, amount int) {
+	err := h.db.Model(&models.AICredits{}).
+		Where("organization_id = ?", organizationID).
+		UpdateColumns(map[string]interface{}{
+			"credits_remaining": gorm.Expr("credits_remaining + LEAST(?, credits_used)", amount),
+			"credits_used":      gorm.Expr("credits_used - LEAST(?, credits_used)", amount),
+		}).Error
+	if err != nil {
+		h.logger.Error("Failed to refund AI credits for %s: %v", organizationID, err)
+	}
 }
 
 func (h *OptimizerHandler) updateCreditsCost(organizationID uuid.UUID, cost float64, success bool) {
@@ -1033,151 +1048,76 @@ func (h *OptimizerHandler) updateCreditsSuccess(organizationID uuid.UUID) {
 	h.db.Save(&credits)
 }
 
-func (h *OptimizerHandler) calculateCost(model string, tokens int) float64 {
-	rates := map[string]float64{
-		"gpt-4":         0.03 / 1000,
-		"gpt-4-vision":  0.04 / 1000,
-		"gpt-3.5-turbo": 0.002 / 1000,
-		"claude-3":      0.015 / 1000,
-		"dall-e":        0.04 / 1000,
-	}
-
-	rate, exists := rates[model]
-	if !exists {
-		rate = 0.002 / 1000 // Default rate
-	}
-
-	return float64(tokens) * rate
+// optimizerFor returns an optimizer using the organization's model, settings, prompt
+// templates and the given budget
+func (h *OptimizerHandler) optimizerFor(organizationID uuid.UUID, settings *models.AISettings, budget *llm.Budget) *ai.Optimizer {
+	return h.optimizer.ForOrganization(h.db, organizationID, settings, budget)
 }
 
-func (h *OptimizerHandler) calculateTitleScore(optimized, original string) int {
-	score := 0
-
-	// Length check (50-60 optimal for SEO)
-	optLen := len(optimized)
-	if optLen >= 50 && optLen <= 60 {
-		score += 25
-	} else if optLen > 30 && optLen < 80 {
-		score += 15
-	} else {
-		score += 5
-	}
-
-	// Check if title is different from original
-	if strings.ToLower(optimized) != strings.ToLower(original) {
-		score += 15
-	}
-
-	// Check for keywords (simple heuristic)
-	words := strings.Fields(optimized)
-	if len(words) >= 5 {
-		score += 20
-	}
-
-	// Check for capital letters (proper formatting)
-	if optimized != strings.ToUpper(optimized) && optimized != strings.ToLower(optimized) {
-		score += 15
-	}
-
-	// Check for special characters (moderate use)
-	specialCount := strings.Count(optimized, "-") + strings.Count(optimized, "|") + strings.Count(optimized, "·")
-	if specialCount > 0 && specialCount <= 3 {
-		score += 10
-	}
-
-	// Check for numbers (product specs)
-	hasNumbers := strings.ContainsAny(optimized, "0123456789")
-	if hasNumbers {
-		score += 15
+// googleCategoryID returns the Google product category ID of a taxonomy path, or 0 when
+// the path isn't in the loaded taxonomy
+func (h *OptimizerHandler) googleCategoryID(path string) int {
+	t, err := taxonomy.Load(taxonomy.Google, h.config.TaxonomyDir)
+	if err != nil {
+		return 0
 	}
-
-	// Ensure score is between 0-100
-	if score > 100 {
-		score = 100
+	if node, ok := t.ByPath(path); ok {
+		return node.ID
 	}
-
-	return score
+	return 0
 }
 
-func (h *OptimizerHandler) calculateDescriptionScore(description string) int {
-	score := 0
-
-	// Length check
-	length := len(description)
-	if length >= 150 && length <= 300 {
-		score += 30
-	} else if length > 100 && length < 500 {
-		score += 20
-	} else {
-		score += 10
-	}
+// monthlyBudget returns the organization's MaxCostPerMonth with this calendar month's spend
+func (h *OptimizerHandler) monthlyBudget(organizationID uuid.UUID, settings *models.AISettings) *llm.Budget {
+	return ai.MonthlyBudget(h.db, organizationID, settings)
+}
 
-	// Sentence count
-	sentences := strings.Count(description, ".") + strings.Count(description, "!") + strings.Count(description, "?")
-	if sentences >= 3 && sentences <= 8 {
-		score += 20
-	}
+// recordUsage stores a usage row per completion the optimizer made and returns the model,
+// token usage and cost to put on the optimization history
+func (h *OptimizerHandler) recordUsage(organizationID uuid.UUID, purpose string, optimizer *ai.Optimizer, defaultModel string) (string, llm.Usage, float64) {
+	return optimizer.RecordUsage(h.db, organizationID, purpose, defaultModel)
+}
 
-	// Check for bullets or lists
-	hasBullets := strings.Contains(description, "•") || strings.Contains(description, "-") || strings.Contains(description, "*")
-	if hasBullets {
-		score += 15
+// fromCache records a zero-cost optimization and returns it when an identical request is
+// cached, unless the request bypasses the cache
+func (h *OptimizerHandler) fromCache(settings *models.AISettings, key string, noCache bool, productID uuid.UUID, originalValue string) *models.OptimizationHistory {
+	if noCache {
+		return nil
 	}
-
-	// Check for key product terms
-	hasFeatures := strings.Contains(strings.ToLower(description), "feature") ||
-		strings.Contains(strings.ToLower(description), "benefit") ||
-		strings.Contains(strings.ToLower(description), "quality")
-	if hasFeatures {
-		score += 15
+	entry := ai.CachedResponse(h.db, settings, key)
+	if entry == nil {
+		return nil
 	}
 
-	// Check for call to action
-	hasCTA := strings.Contains(strings.ToLower(description), "buy") ||
-		strings.Contains(strings.ToLower(description), "order") ||
-		strings.Contains(strings.ToLower(description), "get") ||
-		strings.Contains(strings.ToLower(description), "shop")
-	if hasCTA {
-		score += 20
-	}
+	history := entry.History(productID, originalValue)
+	score, improvement := review.Score(history.OptimizationType, originalValue, history.OptimizedValue)
+	history.Score = &score
+	history.ImprovementPercentage = &improvement
 
-	if score > 100 {
-		score = 100
+	if err := h.db.Create(history).Error; err != nil {
+		h.logger.Error("Failed to save optimization history: %v", err)
 	}
-
-	return score
+	h.autoApply(settings, history)
+	h.logger.Info("Answered %s optimization of product %s from the cache", history.OptimizationType, productID)
+	return history
 }
 
-func (h *OptimizerHandler) calculateImprovement(original, optimized string) float64 {
-	if original == "" {
-		return 100.0
-	}
-
-	// Simple improvement calculation based on length and quality indicators
-	improvementFactor := 1.0
-
-	// Length improvement
-	if len(optimized) > len(original) {
-		improvementFactor += 0.1
-	}
-
-	// Quality indicators
-	if strings.Contains(optimized, "|") || strings.Contains(optimized, "·") {
-		improvementFactor += 0.05
+// autoApply applies a new optimization right away when the organization's settings allow
+// it and its score clears MinScoreThreshold; otherwise it waits in the review queue
+func (h *OptimizerHandler) autoApply(settings *models.AISettings, history *models.OptimizationHistory) {
+	if !review.ShouldAutoApply(settings, false, history) {
+		return
 	}
-
-	if len(strings.Fields(optimized)) > len(strings.Fields(original)) {
-		improvementFactor += 0.1
+	if err := review.Apply(h.db, history, review.Decision{Reviewer: review.AutoReviewer}); err != nil {
+		h.logger.Error("Failed to auto-apply optimization %s: %v", history.ID, err)
+		return
 	}
+	h.updateCreditsSuccess(history.OrganizationID)
+}
 
-	// Calculate percentage
-	improvement := (improvementFactor - 1.0) * 100
-	if improvement > 100 {
-		improvement = 100
+// cacheResponse stores an optimization result for identical requests to reuse
+func (h *OptimizerHandler) cacheResponse(settings *models.AISettings, key string, tmpl *models.PromptTemplate, model, response string) {
+	if err := ai.CacheResponse(h.db, settings, key, tmpl, model, response); err != nil {
+		h.logger.Error("Failed to cache optimization: %v", err)
 	}
-	if improvement < 0 {
-		improvement = 0
-	}
-
-	return improvement
 }
@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"net/http"
+
+	"lister/internal/models"
+	"lister/internal/prompts"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ListPrompts lists the organization's and global prompt template versions with the
+// built-in templates they override
+// GET /api/v1/optimizer/prompts
+func (h *OptimizerHandler) ListPrompts(c *gin.Context) {
+	orgUUID := h.organizationUUID(c)
+
+	query := h.db.Where("organization_id = ? OR organization_id IS NULL", orgUUID)
+	if t := c.Query("type"); t != "" {
+		query = query.Where("optimization_type = ?", t)
+	}
+
+	var templates []models.PromptTemplate
+	if err := query.Order("optimization_type, organization_id NULLS LAST, version DESC").Find(&templates).Error; err != nil {
+		h.logger.Error("Failed to fetch prompt templates: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch prompt templates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":     templates,
+		"builtins": prompts.Builtins(),
+	})
+}
+
+// CreatePrompt saves a new version of an optimization type's prompt. The new version is
+// activated unless activate is false.
+// POST /api/v1/optimizer/prompts
+func (h *OptimizerHandler) CreatePrompt(c *gin.Context) {
+	var req models.PromptTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+	if prompts.Builtin(req.OptimizationType) == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported optimization type"})
+		return
+	}
+
+	orgUUID := h.organizationUUID(c)
+	tmpl := &models.PromptTemplate{
+		OptimizationType: req.OptimizationType,
+		Name:             req.Name,
+		System:           req.System,
+		Body:             req.Body,
+		MaxTokens:        req.MaxTokens,
+		IsActive:         req.Activate == nil || *req.Activate,
+	}
+	if !req.Global {
+		tmpl.OrganizationID = &orgUUID
+	}
+	builtin := prompts.Builtin(req.OptimizationType)
+	if tmpl.MaxTokens == 0 {
+		tmpl.MaxTokens = builtin.MaxTokens
+	}
+	tmpl.Temperature = builtin.Temperature
+	if req.Temperature != nil {
+		tmpl.Temperature = *req.Temperature
+	}
+
+	if err := prompts.Validate(tmpl); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid prompt template", "details": err.Error()})
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		scope := h.templateScope(tx, tmpl.OrganizationID, tmpl.OptimizationType)
+		var latest int
+		if err := scope.Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+			return err
+		}
+		tmpl.Version = latest + 1
+
+		if tmpl.IsActive {
+			if err := h.templateScope(tx, tmpl.OrganizationID, tmpl.OptimizationType).Update("is_active", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(tmpl).Error
+	})
+	if err != nil {
+		h.logger.Error("Failed to save prompt template: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save prompt template"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Prompt template saved",
+		"data":    tmpl,
+	})
+}
+
+// ActivatePrompt makes a stored version the active prompt for its optimization type
+// POST /api/v1/optimizer/prompts/:id/activate
+func (h *OptimizerHandler) ActivatePrompt(c *gin.Context) {
+	orgUUID := h.organizationUUID(c)
+
+	var tmpl models.PromptTemplate
+	err := h.db.Where("id = ? AND (organization_id = ? OR organization_id IS NULL)", c.Param("id"), orgUUID).First(&tmpl).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Prompt template not found"})
+		return
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := h.templateScope(tx, tmpl.OrganizationID, tmpl.OptimizationType).Update("is_active", false).Error; err != nil {
+			return err
+		}
+		tmpl.IsActive = true
+		return tx.Model(&tmpl).Update("is_active", true).Error
+	})
+	if err != nil {
+		h.logger.Error("Failed to activate prompt template: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to activate prompt template"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Prompt template activated",
+		"data":    tmpl,
+	})
+}
+
+// PreviewPrompt renders a template for a product without calling the model. Without a
+// template ID or body the prompt the organization currently uses is rendered.
+// POST /api/v1/optimizer/prompts/preview
+func (h *OptimizerHandler) PreviewPrompt(c *gin.Context) {
+	var req models.PromptPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	orgUUID := h.organizationUUID(c)
+	settings, err := h.getAISettings(orgUUID)
+	if err != nil {
+		settings = h.getDefaultAISettings(orgUUID)
+	}
+
+	var tmpl *models.PromptTemplate
+	switch {
+	case req.Body != "":
+		tmpl = &models.PromptTemplate{OptimizationType: req.OptimizationType, System: req.System, Body: req.Body}
+	case req.TemplateID != "":
+		var stored models.PromptTemplate
+		if err := h.db.Where("id = ? AND (organization_id = ? OR organization_id IS NULL)", req.TemplateID, orgUUID).First(&stored).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Prompt template not found"})
+			return
+		}
+		tmpl = &stored
+	default:
+		tmpl = h.optimizerFor(orgUUID, settings, nil).Template(req.OptimizationType)
+	}
+	if tmpl == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported optimization type"})
+		return
+	}
+
+	data := prompts.Sample()
+	data.Settings = prompts.SettingsFrom(settings)
+	if req.ProductID != "" {
+		var product models.Product
+		if err := h.db.First(&product, "id = ?", req.ProductID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+		data.Product = promptProduct(&product)
+	}
+
+	rendered, err := prompts.Render(tmpl, data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid prompt template", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"template_id": tmpl.ID,
+			"version":     tmpl.Version,
+			"system":      rendered.System,
+			"prompt":      rendered.Prompt,
+			"max_tokens":  rendered.MaxTokens,
+			"temperature": rendered.Temperature,
+		},
+	})
+}
+
+// ComparePrompts aggregates optimization results per prompt version so revisions can be
+// compared
+// GET /api/v1/optimizer/prompts/compare?type=title
+func (h *OptimizerHandler) ComparePrompts(c *gin.Context) {
+	orgUUID := h.organizationUUID(c)
+
+	query := h.db.Model(&models.OptimizationHistory{}).Where("organization_id = ?", orgUUID)
+	if t := c.Query("type"); t != "" {
+		query = query.Where("optimization_type = ?", t)
+	}
+
+	var versions []struct {
+		OptimizationType string   `json:"optimization_type"`
+		PromptTemplateID *string  `json:"prompt_template_id"`
+		PromptVersion    int      `json:"prompt_version"`
+		Count            int64    `json:"count"`
+		AppliedCount     int64    `json:"applied_count"`
+		FailedCount      int64    `json:"failed_count"`
+		AvgScore         *float64 `json:"avg_score"`
+		AvgImprovement   *float64 `json:"avg_improvement"`
+		AvgTokens        *float64 `json:"avg_tokens"`
+		TotalCost        float64  `json:"total_cost"`
+	}
+	err := query.Select(`
+			optimization_type, prompt_template_id, prompt_version,
+			COUNT(*) as count,
+			COUNT(*) FILTER (WHERE status = 'applied') as applied_count,
+			COUNT(*) FILTER (WHERE status = 'failed') as failed_count,
+			AVG(score) as avg_score,
+			AVG(improvement_percentage) as avg_improvement,
+			AVG(tokens_used) as avg_tokens,
+			COALESCE(SUM(cost), 0) as total_cost
+		`).
+		Group("optimization_type, prompt_template_id, prompt_version").
+		Order("optimization_type, prompt_version DESC").
+		Scan(&versions).Error
+	if err != nil {
+		h.logger.Error("Failed to compare prompt versions: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compare prompt versions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": versions})
+}
+
+// templateScope selects the versions of an optimization type's prompt owned by the same
+// organization, or the global versions when organizationID is nil
+func (h *OptimizerHandler) templateScope(tx *gorm.DB, organizationID *uuid.UUID, t models.OptimizationType) *gorm.DB {
+	scope := tx.Model(&models.PromptTemplate{}).Where("optimization_type = ?", t)
+	if organizationID == nil {
+		return scope.Where("organization_id IS NULL")
+	}
+	return scope.Where("organization_id = ?", *organizationID)
+}
+
+func (h *OptimizerHandler) organizationUUID(c *gin.Context) uuid.UUID {
+	organizationID := c.GetString("organization_id")
+	if organizationID == "" {
+		organizationID = "00000000-0000-0000-0000-000000000000"
+	}
+	orgUUID, _ := uuid.Parse(organizationID)
+	return orgUUID
+}
+
+// promptProduct copies the template variables out of a product
+func promptProduct(product *models.Product) prompts.Product {
+	p := prompts.Product{
+		Title:    product.Title,
+		SKU:      product.SKU,
+		Price:    product.Price,
+		Currency: product.Currency,
+	}
+	if product.Description != nil {
+		p.Description = *product.Description
+	}
+	if product.Brand != nil {
+		p.Brand = *product.Brand
+	}
+	if product.Category != nil {
+		p.Category = *product.Category
+	}
+	if product.GTIN != nil {
+		p.GTIN = *product.GTIN
+	}
+	return p
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"lister/internal/models"
+	"lister/internal/taxonomy"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/clause"
+)
+
+// SearchTaxonomy searches a taxonomy's categories
+// GET /api/v1/taxonomy/:name/search?q=winter+jacket&limit=10
+func (h *OptimizerHandler) SearchTaxonomy(c *gin.Context) {
+	t, ok := h.taxonomy(c)
+	if !ok {
+		return
+	}
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"taxonomy": t.Name,
+		"version":  t.Version,
+		"data":     t.Search(query, limit),
+	})
+}
+
+// ListTaxonomyNodes returns the node at a path, or a node's children: the top level
+// without parameters
+// GET /api/v1/taxonomy/:name/nodes?path=Apparel+%26+Accessories or ?parent=166
+func (h *OptimizerHandler) ListTaxonomyNodes(c *gin.Context) {
+	t, ok := h.taxonomy(c)
+	if !ok {
+		return
+	}
+	if path := c.Query("path"); path != "" {
+		node, ok := t.ByPath(path)
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+			return
+		}
+		c.JSON(http.StatusOK, taxonomyNode(t, node))
+		return
+	}
+
+	parent, _ := strconv.Atoi(c.DefaultQuery("parent", "0"))
+	nodes := t.Children(parent)
+	if nodes == nil {
+		nodes = []*taxonomy.Node{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"taxonomy": t.Name,
+		"version":  t.Version,
+		"total":    t.Len(),
+		"data":     nodes,
+	})
+}
+
+// GetTaxonomyNode returns a category with its ancestors and children
+// GET /api/v1/taxonomy/:name/nodes/:id
+func (h *OptimizerHandler) GetTaxonomyNode(c *gin.Context) {
+	t, ok := h.taxonomy(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+	node, ok := t.ByID(id)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+	c.JSON(http.StatusOK, taxonomyNode(t, node))
+}
+
+// ListCategoryMappings returns the organization's product type and collection mappings
+// GET /api/v1/taxonomy/mappings?taxonomy=google&source_type=product_type
+func (h *OptimizerHandler) ListCategoryMappings(c *gin.Context) {
+	query := h.db.Where("organization_id = ?", h.organizationUUID(c))
+	if name := c.Query("taxonomy"); name != "" {
+		query = query.Where("taxonomy = ?", name)
+	}
+	if source := c.Query("source_type"); source != "" {
+		query = query.Where("source_type = ?", source)
+	}
+	var mappings []models.CategoryMapping
+	if err := query.Order("source_type, source_value").Find(&mappings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch category mappings"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": mappings})
+}
+
+// SaveCategoryMapping maps a product type or collection to a taxonomy category,
+// replacing its existing mapping
+// POST /api/v1/taxonomy/mappings
+func (h *OptimizerHandler) SaveCategoryMapping(c *gin.Context) {
+	var req models.CategoryMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+	if !taxonomy.ValidSource(req.SourceType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "source_type must be one of " + strings.Join(taxonomy.Sources, ", ")})
+		return
+	}
+	if req.Taxonomy == "" {
+		req.Taxonomy = taxonomy.Google
+	}
+	t, err := taxonomy.Load(req.Taxonomy, h.config.TaxonomyDir)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	node, ok := t.Resolve(req.Category)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":       "category is not in the " + t.Name + " taxonomy",
+			"suggestions": t.Search(req.Category, 5),
+		})
+		return
+	}
+
+	mapping := &models.CategoryMapping{
+		OrganizationID: h.organizationUUID(c),
+		SourceType:     req.SourceType,
+		SourceValue:    strings.TrimSpace(req.SourceValue),
+		Taxonomy:       t.Name,
+		CategoryID:     node.ID,
+		CategoryPath:   node.Path,
+		UpdatedAt:      time.Now(),
+	}
+	err = h.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "organization_id"}, {Name: "source_type"}, {Name: "source_value"}, {Name: "taxonomy"}},
+		DoUpdates: clause.AssignmentColumns([]string{"category_id", "category_path", "updated_at"}),
+	}).Create(mapping).Error
+	if err != nil {
+		h.logger.Error("Failed to save category mapping: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save category mapping"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": mapping})
+}
+
+// DeleteCategoryMapping removes a category mapping
+// DELETE /api/v1/taxonomy/mappings/:id
+func (h *OptimizerHandler) DeleteCategoryMapping(c *gin.Context) {
+	res := h.db.Where("id = ? AND organization_id = ?", c.Param("id"), h.organizationUUID(c)).
+		Delete(&models.CategoryMapping{})
+	if res.Error != nil || res.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category mapping not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Category mapping deleted"})
+}
+
+// taxonomy loads the taxonomy named in the path, responding with an error when it can't
+func (h *OptimizerHandler) taxonomy(c *gin.Context) (*taxonomy.Taxonomy, bool) {
+	t, err := taxonomy.Load(c.Param("name"), h.config.TaxonomyDir)
+	if errors.Is(err, taxonomy.ErrUnknown) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if err != nil {
+		h.logger.Error("Failed to load %s taxonomy: %v", c.Param("name"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load taxonomy"})
+		return nil, false
+	}
+	return t, true
+}
+
+// taxonomyNode is a node with its ancestors and children
+func taxonomyNode(t *taxonomy.Taxonomy, node *taxonomy.Node) gin.H {
+	children := t.Children(node.ID)
+	if children == nil {
+		children = []*taxonomy.Node{}
+	}
+	return gin.H{
+		"taxonomy":  t.Name,
+		"data":      node,
+		"ancestors": t.Ancestors(node),
+		"children":  children,
+	}
+}
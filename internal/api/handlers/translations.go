@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"lister/internal/llm"
+	"lister/internal/models"
+	"lister/internal/translation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+// maxTranslationLocales caps how many locales one request translates into
+const maxTranslationLocales = 10
+
+// ListTranslations lists a product's translations
+// GET /api/v1/products/:id/translations
+func (h *OptimizerHandler) ListTranslations(c *gin.Context) {
+	var translations []models.ProductTranslation
+	err := h.db.Where("product_id = ? AND organization_id = ?", c.Param("id"), h.organizationUUID(c)).
+		Order("locale").Find(&translations).Error
+	if err != nil {
+		h.logger.Error("Failed to fetch translations: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch translations"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": translations})
+}
+
+// TranslateProduct generates the product's title, description and SEO fields in each
+// requested locale with the organization's glossary, replacing earlier translations
+// POST /api/v1/products/:id/translations
+func (h *OptimizerHandler) TranslateProduct(c *gin.Context) {
+	var req models.TranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+	if len(req.Locales) == 0 || len(req.Locales) > maxTranslationLocales {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "locales must list between 1 and 10 locales"})
+		return
+	}
+
+	orgUUID := h.organizationUUID(c)
+	productUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+	var product models.Product
+	if err := h.db.First(&product, "id = ?", productUUID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		return
+	}
+
+	settings, err := h.getAISettings(orgUUID)
+	if err != nil {
+		settings = h.getDefaultAISettings(orgUUID)
+	}
+
+	locales := make([]string, 0, len(req.Locales))
+	for _, code := range req.Locales {
+		locale, err := translation.NormalizeLocale(code)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if translation.IsSource(locale, settings.DefaultLanguage) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Products are already written in " + locale})
+			return
+		}
+		locales = append(locales, locale)
+	}
+
+	var glossary []models.GlossaryTerm
+	h.db.Where("organization_id = ?", orgUUID).Find(&glossary)
+
+	if err := h.checkAndDeductCredits(orgUUID, len(locales)); err != nil {
+		c.JSON(http.StatusPaymentRequired, gin.H{"error": "Insufficient AI credits", "details": err.Error()})
+		return
+	}
+
+	if req.Model != "" {
+		settings.DefaultModel = req.Model
+	}
+	productData := translationProductData(&product)
+	budget := h.monthlyBudget(orgUUID, settings)
+
+	results := make([]gin.H, 0, len(locales))
+	for i, locale := range locales {
+		optimizer := h.optimizerFor(orgUUID, settings, budget)
+		translated, err := optimizer.Translate(productData, locale, translation.ForLocale(glossary, locale))
+		aiModel, usage, cost := h.recordUsage(orgUUID, string(models.OptimizationTypeTranslation), optimizer, settings.DefaultModel)
+		h.updateCreditsCost(orgUUID, cost, err == nil)
+
+		history := &models.OptimizationHistory{
+			ProductID:        productUUID,
+			OrganizationID:   orgUUID,
+			OptimizationType: models.OptimizationTypeTranslation,
+			OriginalValue:    product.Title,
+			Status:           models.OptimizationStatusApplied,
+			AIModel:          aiModel,
+			Cost:             cost,
+			TokensUsed:       usage.TotalTokens,
+			Metadata:         models.JSONB{"locale": locale},
+		}
+		history.SetPrompt(optimizer.Template(models.OptimizationTypeTranslation))
+
+		if err != nil {
+			errorMsg := err.Error()
+			history.Status = models.OptimizationStatusFailed
+			history.ErrorMessage = &errorMsg
+			if dbErr := h.db.Create(history).Error; dbErr != nil {
+				h.logger.Error("Failed to save optimization history: %v", dbErr)
+			}
+			results = append(results, gin.H{"locale": locale, "status": "failed", "error": errorMsg})
+			if errors.Is(err, llm.ErrBudgetExceeded) {
+				// This locale and the ones after it aren't translated
+				h.refundCredits(orgUUID, len(locales)-i)
+				break
+			}
+			continue
+		}
+
+		record := &models.ProductTranslation{
+			OrganizationID: orgUUID,
+			ProductID:      productUUID,
+			Locale:         locale,
+			Title:          translated.Title,
+			Description:    optionalString(translated.Description),
+			SEOTitle:       optionalString(translated.SEOTitle),
+			SEODescription: optionalString(translated.SEODescription),
+			Keywords:       optionalString(translated.Keywords),
+			Source:         models.TranslationSourceAI,
+			AIModel:        &aiModel,
+		}
+		if err := h.saveTranslation(record); err != nil {
+			h.logger.Error("Failed to save %s translation of %s: %v", locale, productUUID, err)
+			results = append(results, gin.H{"locale": locale, "status": "failed", "error": "Failed to save translation"})
+			continue
+		}
+
+		now := time.Now()
+		history.OptimizedValue = translated.Title
+		history.AppliedAt = &now
+		history.Metadata["translation_id"] = record.ID.String()
+		if err := h.db.Create(history).Error; err != nil {
+			h.logger.Error("Failed to save optimization history: %v", err)
+		}
+		results = append(results, gin.H{"locale": locale, "status": "translated", "translation": record, "cost": cost})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"product_id": productUUID,
+		"results":    results,
+	})
+}
+
+// UpdateTranslation sets a product's translation into one locale by hand
+// PUT /api/v1/products/:id/translations/:locale
+func (h *OptimizerHandler) UpdateTranslation(c *gin.Context) {
+	var req models.TranslationUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+	locale, err := translation.NormalizeLocale(c.Param("locale"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	productUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+	var product models.Product
+	if err := h.db.Select("id").First(&product, "id = ?", productUUID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		return
+	}
+
+	record := &models.ProductTranslation{
+		OrganizationID: h.organizationUUID(c),
+		ProductID:      productUUID,
+		Locale:         locale,
+		Title:          req.Title,
+		Description:    req.Description,
+		SEOTitle:       req.SEOTitle,
+		SEODescription: req.SEODescription,
+		Keywords:       req.Keywords,
+		Source:         models.TranslationSourceManual,
+	}
+	if err := h.saveTranslation(record); err != nil {
+		h.logger.Error("Failed to save %s translation of %s: %v", locale, productUUID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save translation"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Translation saved", "data": record})
+}
+
+// DeleteTranslation removes a product's translation, so feeds in that locale fall back to
+// the product's own fields
+// DELETE /api/v1/products/:id/translations/:locale
+func (h *OptimizerHandler) DeleteTranslation(c *gin.Context) {
+	locale, err := translation.NormalizeLocale(c.Param("locale"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	res := h.db.Where("product_id = ? AND organization_id = ? AND locale = ?", c.Param("id"), h.organizationUUID(c), locale).
+		Delete(&models.ProductTranslation{})
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete translation"})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Translation not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Translation deleted"})
+}
+
+// ListGlossary lists the organization's glossary terms
+// GET /api/v1/translations/glossary
+func (h *OptimizerHandler) ListGlossary(c *gin.Context) {
+	query := h.db.Where("organization_id = ?", h.organizationUUID(c))
+	if locale := c.Query("locale"); locale != "" {
+		query = query.Where("locale = ? OR locale IS NULL", locale)
+	}
+	var terms []models.GlossaryTerm
+	if err := query.Order("term").Find(&terms).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch glossary"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": terms})
+}
+
+// CreateGlossaryTerm adds a fixed translation or a do-not-translate term
+// POST /api/v1/translations/glossary
+func (h *OptimizerHandler) CreateGlossaryTerm(c *gin.Context) {
+	var req models.GlossaryTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+	req.Term = strings.TrimSpace(req.Term)
+	if req.Term == "" || (!req.DoNotTranslate && strings.TrimSpace(req.Translation) == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "term and either translation or do_not_translate are required"})
+		return
+	}
+
+	term := &models.GlossaryTerm{
+		OrganizationID: h.organizationUUID(c),
+		Term:           req.Term,
+		DoNotTranslate: req.DoNotTranslate,
+	}
+	if req.Locale != "" {
+		locale, err := translation.NormalizeLocale(req.Locale)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		term.Locale = &locale
+	}
+	if !req.DoNotTranslate {
+		translated := strings.TrimSpace(req.Translation)
+		term.Translation = &translated
+	}
+
+	if err := h.db.Create(term).Error; err != nil {
+		h.logger.Error("Failed to create glossary term: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create glossary term"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": term})
+}
+
+// DeleteGlossaryTerm removes a glossary term
+// DELETE /api/v1/translations/glossary/:id
+func (h *OptimizerHandler) DeleteGlossaryTerm(c *gin.Context) {
+	res := h.db.Where("id = ? AND organization_id = ?", c.Param("id"), h.organizationUUID(c)).
+		Delete(&models.GlossaryTerm{})
+	if res.Error != nil || res.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Glossary term not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Glossary term deleted"})
+}
+
+// saveTranslation inserts a translation or replaces the product's existing one in its
+// locale
+func (h *OptimizerHandler) saveTranslation(record *models.ProductTranslation) error {
+	record.UpdatedAt = time.Now()
+	return h.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "product_id"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"title", "description", "seo_title", "seo_description", "keywords", "source", "ai_model", "updated_at",
+		}),
+	}).Create(record).Error
+}
+
+// translationProductData is the product content a translation prompt translates
+func translationProductData(product *models.Product) map[string]interface{} {
+	data := map[string]interface{}{"title": product.Title}
+	if product.Description != nil {
+		data["description"] = *product.Description
+	}
+	if product.Brand != nil {
+		data["brand"] = *product.Brand
+	}
+	if product.Category != nil {
+		data["category"] = *product.Category
+	}
+	for _, field := range []string{"seo_title", "seo_description"} {
+		if value, ok := product.Metadata[field].(string); ok {
+			data[field] = value
+		}
+	}
+	if keywords, ok := product.Metadata["meta_keywords"].(string); ok {
+		data["seo_keywords"] = keywords
+	}
+	return data
+}
+
+// optionalString returns nil for empty strings
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
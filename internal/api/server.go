@@ -38,7 +38,7 @@ func New(cfg *config.Config, logger *logger.Logger, db *database.Database) *Serv
 
 	// Initialize handlers
 	productHandler := handlers.NewProductHandler(db.DB, logger)
-	connectorHandler := handlers.NewConnectorHandler(db.DB, logger)
+	connectorHandler := handlers.NewConnectorHandler(db.DB, logger, cfg)
 	channelHandler := handlers.NewChannelHandler(db.DB, logger)
 	issueHandler := handlers.NewIssueHandler(db.DB, logger)
 	shopifyHandler := handlers.NewShopifyHandler(db.DB, logger, cfg)
@@ -55,6 +55,10 @@ func New(cfg *config.Config, logger *logger.Logger, db *database.Database) *Serv
 			products.POST("", productHandler.Create)
 			products.PUT("/:id", productHandler.Update)
 			products.DELETE("/:id", productHandler.Delete)
+			products.GET("/:id/translations", optimizerHandler.ListTranslations)
+			products.POST("/:id/translations", optimizerHandler.TranslateProduct)
+			products.PUT("/:id/translations/:locale", optimizerHandler.UpdateTranslation)
+			products.DELETE("/:id/translations/:locale", optimizerHandler.DeleteTranslation)
 		}
 
 		// Connectors
@@ -66,6 +70,9 @@ func New(cfg *config.Config, logger *logger.Logger, db *database.Database) *Serv
 			connectors.PUT("/:id", connectorHandler.Update)
 			connectors.DELETE("/:id", connectorHandler.Delete)
 			connectors.POST("/:id/sync", connectorHandler.Sync)
+			connectors.POST("/:id/dry-run", connectorHandler.DryRun)
+			connectors.POST("/:id/webhook", connectorHandler.Webhook)
+			connectors.POST("/:id/webhooks/register", connectorHandler.RegisterWebhooks)
 		}
 
 		// Channels
@@ -107,13 +114,50 @@ func New(cfg *config.Config, logger *logger.Logger, db *database.Database) *Serv
 			optimizer.POST("/description", optimizerHandler.OptimizeDescription)
 			optimizer.POST("/category", optimizerHandler.SuggestCategory)
 			optimizer.POST("/image", optimizerHandler.AnalyzeImages)
+			optimizer.POST("/attributes", optimizerHandler.ExtractAttributes)
 			optimizer.POST("/bulk", optimizerHandler.BulkOptimize)
 			optimizer.GET("/history", optimizerHandler.GetHistory)
 			optimizer.GET("/analytics", optimizerHandler.GetAnalytics)
 			optimizer.GET("/settings", optimizerHandler.GetSettings)
 			optimizer.PUT("/settings", optimizerHandler.UpdateSettings)
 			optimizer.GET("/credits", optimizerHandler.GetCredits)
+			optimizer.DELETE("/cache", optimizerHandler.ClearCache)
+			optimizer.GET("/prompts", optimizerHandler.ListPrompts)
+			optimizer.POST("/prompts", optimizerHandler.CreatePrompt)
+			optimizer.POST("/prompts/preview", optimizerHandler.PreviewPrompt)
+			optimizer.GET("/prompts/compare", optimizerHandler.ComparePrompts)
+			optimizer.POST("/prompts/:id/activate", optimizerHandler.ActivatePrompt)
+			optimizer.GET("/jobs", optimizerHandler.ListJobs)
+			optimizer.GET("/jobs/:id", optimizerHandler.GetJob)
+			optimizer.POST("/jobs/:id/pause", optimizerHandler.PauseJob)
+			optimizer.POST("/jobs/:id/resume", optimizerHandler.ResumeJob)
+			optimizer.POST("/jobs/:id/cancel", optimizerHandler.CancelJob)
+			optimizer.GET("/review", optimizerHandler.ListReview)
+			optimizer.POST("/review/bulk", optimizerHandler.BulkReview)
+			optimizer.POST("/revert", optimizerHandler.BulkRevert)
 			optimizer.POST("/:id/apply", optimizerHandler.ApplyOptimization)
+			optimizer.POST("/:id/approve", optimizerHandler.ApproveOptimization)
+			optimizer.POST("/:id/reject", optimizerHandler.RejectOptimization)
+			optimizer.POST("/:id/revert", optimizerHandler.RevertOptimization)
+		}
+
+		// Translations
+		translations := v1.Group("/translations")
+		{
+			translations.GET("/glossary", optimizerHandler.ListGlossary)
+			translations.POST("/glossary", optimizerHandler.CreateGlossaryTerm)
+			translations.DELETE("/glossary/:id", optimizerHandler.DeleteGlossaryTerm)
+		}
+
+		// Product taxonomies and category mappings
+		taxonomies := v1.Group("/taxonomy")
+		{
+			taxonomies.GET("/mappings", optimizerHandler.ListCategoryMappings)
+			taxonomies.POST("/mappings", optimizerHandler.SaveCategoryMapping)
+			taxonomies.DELETE("/mappings/:id", optimizerHandler.DeleteCategoryMapping)
+			taxonomies.GET("/:name/search", optimizerHandler.SearchTaxonomy)
+			taxonomies.GET("/:name/nodes", optimizerHandler.ListTaxonomyNodes)
+			taxonomies.GET("/:name/nodes/:id", optimizerHandler.GetTaxonomyNode)
 		}
 	}
 
@@ -0,0 +1,112 @@
+// Package attributes defines the structured apparel attributes shopping channels ask
+// for — color, size, material, gender and age group — and where products keep them.
+// Values are extracted by the AI pipeline from a product's title, description and variant
+// options; accepted values are stored under "attributes" in the product's metadata, which
+// feed generation reads.
+package attributes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"lister/internal/models"
+)
+
+// Attribute names, as used by Google Merchant Center and Meta catalogs
+const (
+	Color    = "color"
+	Size     = "size"
+	Material = "material"
+	Gender   = "gender"
+	AgeGroup = "age_group"
+)
+
+// MetadataKey is the product metadata key holding accepted attributes
+const MetadataKey = "attributes"
+
+// DefaultMinConfidence is the confidence (0-100) from which extracted values are applied
+// without review
+const DefaultMinConfidence = 80
+
+// Names lists the attributes in feed order
+var Names = []string{Color, Size, Material, Gender, AgeGroup}
+
+// allowed holds the values of attributes with a fixed vocabulary
+var allowed = map[string][]string{
+	Gender:   {"male", "female", "unisex"},
+	AgeGroup: {"newborn", "infant", "toddler", "kids", "adult"},
+}
+
+// Allowed returns the values an attribute accepts, or nil when any value goes
+func Allowed(name string) []string {
+	return allowed[name]
+}
+
+// Valid reports whether name is a known attribute
+func Valid(name string) bool {
+	for _, n := range Names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Normalize cleans up an attribute value: fixed-vocabulary values are lower-cased and
+// checked, others are trimmed. Channels limit values to 100 characters.
+func Normalize(name, value string) (string, error) {
+	if !Valid(name) {
+		return "", fmt.Errorf("unknown attribute %q", name)
+	}
+	value = strings.Join(strings.Fields(value), " ")
+	if value == "" {
+		return "", fmt.Errorf("empty %s", name)
+	}
+	if values := allowed[name]; values != nil {
+		value = strings.ToLower(value)
+		for _, v := range values {
+			if v == value {
+				return value, nil
+			}
+		}
+		return "", fmt.Errorf("%s must be one of %s", name, strings.Join(values, ", "))
+	}
+	if len(value) > 100 {
+		return "", fmt.Errorf("%s is longer than 100 characters", name)
+	}
+	return value, nil
+}
+
+// Current returns the accepted attributes in a product's metadata
+func Current(metadata map[string]interface{}) map[string]string {
+	current := make(map[string]string)
+	stored, _ := metadata[MetadataKey].(map[string]interface{})
+	for name, value := range stored {
+		if s, ok := value.(string); ok && Valid(name) {
+			current[name] = s
+		}
+	}
+	return current
+}
+
+// VariantOptions collects the distinct values of each variant option, such as
+// {"Color": ["Blue", "Red"], "Size": ["M", "L"]}
+func VariantOptions(variants []models.ProductVariant) map[string][]string {
+	options := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, variant := range variants {
+		for option, value := range variant.Attributes {
+			s, ok := value.(string)
+			if !ok || s == "" || seen[option+"\x00"+s] {
+				continue
+			}
+			seen[option+"\x00"+s] = true
+			options[option] = append(options[option], s)
+		}
+	}
+	for _, values := range options {
+		sort.Strings(values)
+	}
+	return options
+}
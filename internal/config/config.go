@@ -18,8 +18,9 @@ type Config struct {
 	KafkaBrokers string
 
 	// API Configuration
-	APIPort string
-	APIHost string
+	APIPort   string
+	APIHost   string
+	PublicURL string // base URL stores deliver webhooks to
 
 	// JWT
 	JWTSecret string
@@ -28,8 +29,34 @@ type Config struct {
 	EncryptionKey string
 
 	// External APIs
-	OpenAIAPIKey    string
-	AnthropicAPIKey string
+	OpenAIAPIKey     string
+	AnthropicAPIKey  string
+	OpenRouterAPIKey string
+
+	// LLM providers
+	LLMProvider       string // preferred provider: openrouter, openai, anthropic or mock
+	LLMDefaultModel   string // used when an organization has no default model
+	LLMTimeoutSeconds int
+	LLMMaxRetries     int
+	LLMPriceTable     string // JSON or path to a JSON file of per-model prices (USD per 1M tokens)
+	LLMBudgetModel    string // model used when the monthly budget can't afford the requested one
+	OpenAIBaseURL     string
+	AnthropicBaseURL  string
+	OpenRouterBaseURL string
+	OpenRouterReferer string
+
+	// Background jobs
+	JobsInWorker   bool // bulk optimization jobs are left to the worker instead of the API process
+	JobPollSeconds int
+
+	// Product taxonomies
+	TaxonomyDir string // directory with google.txt and meta.txt replacing the bundled taxonomies
+
+	// Multi-currency feeds
+	ExchangeRateSource string // source of exchange rates: ecb, none, or a registered source
+	ExchangeRateMaxAge int    // hours before a source's rates are refetched
+	ExchangeRateRetry  int    // minutes before a failed refetch is tried again
+	ExchangeRateExpiry int    // hours after which a source's rates are no longer used; 0 keeps them
 
 	// Google Merchant Center
 	GoogleClientID     string
@@ -54,10 +81,29 @@ func Load() (*Config, error) {
 		KafkaBrokers:        getEnv("KAFKA_BROKERS", "localhost:9092"),
 		APIPort:             getEnv("API_PORT", "8080"),
 		APIHost:             getEnv("API_HOST", "0.0.0.0"),
+		PublicURL:           getEnv("PUBLIC_URL", "https://product-lister-eight.vercel.app"),
 		JWTSecret:           getEnv("JWT_SECRET", "your-jwt-secret-key-here"),
 		EncryptionKey:       getEnv("ENCRYPTION_KEY", "your-32-byte-encryption-key-here"),
 		OpenAIAPIKey:        getEnv("OPENAI_API_KEY", ""),
 		AnthropicAPIKey:     getEnv("ANTHROPIC_API_KEY", ""),
+		OpenRouterAPIKey:    getEnv("OPENROUTER_API_KEY", ""),
+		LLMProvider:         getEnv("LLM_PROVIDER", ""),
+		LLMDefaultModel:     getEnv("LLM_DEFAULT_MODEL", getEnv("OPENROUTER_MODEL", "")),
+		LLMTimeoutSeconds:   getEnvAsInt("LLM_TIMEOUT_SECONDS", 30),
+		LLMMaxRetries:       getEnvAsInt("LLM_MAX_RETRIES", 2),
+		LLMPriceTable:       getEnv("LLM_PRICE_TABLE", ""),
+		LLMBudgetModel:      getEnv("LLM_BUDGET_MODEL", ""),
+		OpenAIBaseURL:       getEnv("OPENAI_BASE_URL", "https://api.openai.com/v1"),
+		AnthropicBaseURL:    getEnv("ANTHROPIC_BASE_URL", "https://api.anthropic.com/v1"),
+		OpenRouterBaseURL:   getEnv("OPENROUTER_BASE_URL", "https://openrouter.ai/api/v1"),
+		OpenRouterReferer:   getEnv("OPENROUTER_REFERER", "https://product-lister-eight.vercel.app"),
+		JobsInWorker:        getEnv("JOBS_IN_WORKER", "false") == "true",
+		JobPollSeconds:      getEnvAsInt("JOB_POLL_SECONDS", 5),
+		TaxonomyDir:         getEnv("TAXONOMY_DIR", ""),
+		ExchangeRateSource:  getEnv("EXCHANGE_RATE_SOURCE", "ecb"),
+		ExchangeRateMaxAge:  getEnvAsInt("EXCHANGE_RATE_MAX_AGE_HOURS", 24),
+		ExchangeRateRetry:   getEnvAsInt("EXCHANGE_RATE_RETRY_MINUTES", 30),
+		ExchangeRateExpiry:  getEnvAsInt("EXCHANGE_RATE_HARD_MAX_AGE_HOURS", 168),
 		GoogleClientID:      getEnv("GOOGLE_CLIENT_ID", ""),
 		GoogleClientSecret:  getEnv("GOOGLE_CLIENT_SECRET", ""),
 		ShopifyClientID:     getEnv("SHOPIFY_CLIENT_ID", ""),
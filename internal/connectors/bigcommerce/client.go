@@ -0,0 +1,231 @@
+package bigcommerce
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"lister/internal/logger"
+)
+
+const (
+	apiBaseURL = "https://api.bigcommerce.com/stores"
+	// maxLimit is the largest page size the v3 catalog API accepts
+	maxLimit = 250
+)
+
+type Client struct {
+	storeHash   string
+	accessToken string
+	httpClient  *http.Client
+	logger      *logger.Logger
+}
+
+func NewClient(storeHash, accessToken string, logger *logger.Logger) *Client {
+	return &Client{
+		storeHash:   storeHash,
+		accessToken: accessToken,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
+// GetProducts fetches a single page of products including variants, images and custom fields
+func (c *Client) GetProducts(page, limit int) ([]Product, *Pagination, error) {
+	var resp struct {
+		Data []Product `json:"data"`
+		Meta struct {
+			Pagination Pagination `json:"pagination"`
+		} `json:"meta"`
+	}
+
+	query := map[string]string{
+		"include": "variants,images,custom_fields",
+		"page":    strconv.Itoa(page),
+		"limit":   strconv.Itoa(limit),
+	}
+	if err := c.do("GET", "/v3/catalog/products", query, nil, &resp); err != nil {
+		return nil, nil, err
+	}
+
+	return resp.Data, &resp.Meta.Pagination, nil
+}
+
+// GetAllProducts walks every page of the catalog
+func (c *Client) GetAllProducts() ([]Product, error) {
+	var all []Product
+	for page := 1; ; page++ {
+		products, pagination, err := c.GetProducts(page, maxLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch products page %d: %w", page, err)
+		}
+
+		c.logger.Debug("Fetched BigCommerce products page %d/%d (%d products)", page, pagination.TotalPages, len(products))
+		all = append(all, products...)
+
+		if page >= pagination.TotalPages || len(products) == 0 {
+			break
+		}
+	}
+	return all, nil
+}
+
+// GetProduct fetches a single product by ID
+func (c *Client) GetProduct(productID int64) (*Product, error) {
+	var resp struct {
+		Data Product `json:"data"`
+	}
+	path := fmt.Sprintf("/v3/catalog/products/%d", productID)
+	if err := c.do("GET", path, map[string]string{"include": "variants,images,custom_fields"}, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// GetBrands returns every brand keyed by ID
+func (c *Client) GetBrands() (map[int64]string, error) {
+	brands := make(map[int64]string)
+	for page := 1; ; page++ {
+		var resp struct {
+			Data []Brand `json:"data"`
+			Meta struct {
+				Pagination Pagination `json:"pagination"`
+			} `json:"meta"`
+		}
+		query := map[string]string{"page": strconv.Itoa(page), "limit": strconv.Itoa(maxLimit)}
+		if err := c.do("GET", "/v3/catalog/brands", query, nil, &resp); err != nil {
+			return nil, fmt.Errorf("failed to fetch brands: %w", err)
+		}
+		for _, b := range resp.Data {
+			brands[b.ID] = b.Name
+		}
+		if page >= resp.Meta.Pagination.TotalPages || len(resp.Data) == 0 {
+			break
+		}
+	}
+	return brands, nil
+}
+
+// GetCategories returns every category keyed by ID
+func (c *Client) GetCategories() (map[int64]Category, error) {
+	categories := make(map[int64]Category)
+	for page := 1; ; page++ {
+		var resp struct {
+			Data []Category `json:"data"`
+			Meta struct {
+				Pagination Pagination `json:"pagination"`
+			} `json:"meta"`
+		}
+		query := map[string]string{"page": strconv.Itoa(page), "limit": strconv.Itoa(maxLimit)}
+		if err := c.do("GET", "/v3/catalog/categories", query, nil, &resp); err != nil {
+			return nil, fmt.Errorf("failed to fetch categories: %w", err)
+		}
+		for _, cat := range resp.Data {
+			categories[cat.ID] = cat
+		}
+		if page >= resp.Meta.Pagination.TotalPages || len(resp.Data) == 0 {
+			break
+		}
+	}
+	return categories, nil
+}
+
+// GetStore fetches store information (currency and storefront URL)
+func (c *Client) GetStore() (*Store, error) {
+	var store Store
+	if err := c.do("GET", "/v2/store", nil, nil, &store); err != nil {
+		return nil, err
+	}
+	return &store, nil
+}
+
+// GetHooks lists the webhooks registered for the store
+func (c *Client) GetHooks() ([]Hook, error) {
+	var resp struct {
+		Data []Hook `json:"data"`
+	}
+	if err := c.do("GET", "/v3/hooks", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// CreateHook registers a webhook
+func (c *Client) CreateHook(hook Hook) (*Hook, error) {
+	var resp struct {
+		Data Hook `json:"data"`
+	}
+	if err := c.do("POST", "/v3/hooks", nil, hook, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// UpdateHook replaces an existing webhook registration
+func (c *Client) UpdateHook(hook Hook) (*Hook, error) {
+	var resp struct {
+		Data Hook `json:"data"`
+	}
+	if err := c.do("PUT", fmt.Sprintf("/v3/hooks/%d", hook.ID), nil, hook, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// do performs an authenticated request against the store API and decodes the body into out
+func (c *Client) do(method, path string, query map[string]string, body interface{}, out interface{}) error {
+	url := fmt.Sprintf("%s/%s%s", apiBaseURL, c.storeHash, path)
+
+	var reqBody io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reqBody = bytes.NewBuffer(jsonData)
+	}
+
+	req, err := http.NewRequest(method, url, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("X-Auth-Token", c.accessToken)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	if len(query) > 0 {
+		q := req.URL.Query()
+		for k, v := range query {
+			q.Set(k, v)
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API request failed: %d - %s", resp.StatusCode, string(respBody))
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
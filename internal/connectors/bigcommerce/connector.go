@@ -0,0 +1,257 @@
+package bigcommerce
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"lister/internal/config"
+	"lister/internal/logger"
+	"lister/internal/models"
+)
+
+// WebhookSecretHeader is sent by BigCommerce with every delivery. BigCommerce does not sign
+// payloads, so the shared secret is registered as a custom header on each hook.
+const WebhookSecretHeader = "X-Lister-Webhook-Secret"
+
+// WebhookScopes are the product events the connector subscribes to
+var WebhookScopes = []string{
+	"store/product/created",
+	"store/product/updated",
+	"store/product/deleted",
+	"store/product/inventory/updated",
+}
+
+// ErrUnsupportedScope is returned for webhook scopes that don't affect products
+var ErrUnsupportedScope = errors.New("unsupported webhook scope")
+
+// ErrStoreMismatch is returned for webhooks produced by a different store than the client's
+var ErrStoreMismatch = errors.New("webhook produced by another store")
+
+// TransformerCacheTTL is how long a store's currency, brands and categories are reused by
+// webhooks before they are fetched again
+const TransformerCacheTTL = 15 * time.Minute
+
+var (
+	transformersMu sync.Mutex
+	transformers   = map[string]cachedTransformer{}
+)
+
+type cachedTransformer struct {
+	transformer *Transformer
+	loadedAt    time.Time
+}
+
+type BigCommerceConnector struct {
+	config *config.Config
+	logger *logger.Logger
+}
+
+func New(cfg *config.Config, logger *logger.Logger) *BigCommerceConnector {
+	return &BigCommerceConnector{
+		config: cfg,
+		logger: logger,
+	}
+}
+
+// SyncProducts fetches every page of the catalog and returns it in canonical format
+func (bc *BigCommerceConnector) SyncProducts(storeHash, accessToken string) ([]*models.Product, error) {
+	bc.logger.Info("Syncing products from BigCommerce store: %s", storeHash)
+
+	client := NewClient(storeHash, accessToken, bc.logger)
+
+	transformer, err := bc.loadTransformer(client)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := client.GetAllProducts()
+	if err != nil {
+		return nil, err
+	}
+
+	canonical := make([]*models.Product, 0, len(products))
+	for i := range products {
+		canonical = append(canonical, transformer.TransformProduct(&products[i]))
+	}
+
+	bc.logger.Debug("BigCommerce sync completed: %d products", len(canonical))
+
+	return canonical, nil
+}
+
+// RegisterWebhooks subscribes destination to every product scope, updating hooks that
+// already point at it so registration can be repeated safely
+func (bc *BigCommerceConnector) RegisterWebhooks(client *Client, destination, secret string) ([]Hook, error) {
+	existing, err := client.GetHooks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	byScope := make(map[string]Hook)
+	for _, hook := range existing {
+		if hook.Destination == destination {
+			byScope[hook.Scope] = hook
+		}
+	}
+
+	registered := make([]Hook, 0, len(WebhookScopes))
+	for _, scope := range WebhookScopes {
+		hook := Hook{
+			Scope:       scope,
+			Destination: destination,
+			IsActive:    true,
+		}
+		if secret != "" {
+			hook.Headers = map[string]string{WebhookSecretHeader: secret}
+		}
+
+		var result *Hook
+		if current, ok := byScope[scope]; ok {
+			hook.ID = current.ID
+			result, err = client.UpdateHook(hook)
+		} else {
+			result, err = client.CreateHook(hook)
+		}
+		if err != nil {
+			return registered, fmt.Errorf("failed to register %s webhook: %w", scope, err)
+		}
+
+		bc.logger.Debug("Registered BigCommerce webhook %s -> %s", scope, destination)
+		registered = append(registered, *result)
+	}
+
+	return registered, nil
+}
+
+// WebhookEvent describes a product change received from a BigCommerce webhook
+type WebhookEvent struct {
+	Scope      string
+	Action     string // "created", "updated" or "deleted"
+	StoreHash  string
+	ExternalID string
+	Product    *models.Product // nil for deletes
+}
+
+// ParseWebhook decodes a webhook payload and returns the store hash it was produced by,
+// so the caller can look up the connector before fetching the product
+func ParseWebhook(payload []byte) (*WebhookPayload, string, error) {
+	var event WebhookPayload
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, "", fmt.Errorf("failed to parse webhook payload: %w", err)
+	}
+	if !strings.HasPrefix(event.Producer, "stores/") || event.Producer == "stores/" {
+		return nil, "", fmt.Errorf("invalid webhook producer %q", event.Producer)
+	}
+	return &event, strings.TrimPrefix(event.Producer, "stores/"), nil
+}
+
+// HandleWebhook processes a product webhook. Payloads only carry the product ID, so the
+// client is used to fetch the current state of created and updated products, after
+// checking that the webhook was produced by the client's store.
+func (bc *BigCommerceConnector) HandleWebhook(payload []byte, client *Client) (*WebhookEvent, error) {
+	event, storeHash, err := ParseWebhook(payload)
+	if err != nil {
+		return nil, err
+	}
+	if storeHash != client.storeHash {
+		return nil, ErrStoreMismatch
+	}
+
+	if !strings.HasPrefix(event.Scope, "store/product/") || event.Data.ID == 0 {
+		return nil, ErrUnsupportedScope
+	}
+
+	bc.logger.Debug("Received BigCommerce webhook: %s (product %d)", event.Scope, event.Data.ID)
+
+	result := &WebhookEvent{
+		Scope:      event.Scope,
+		StoreHash:  storeHash,
+		ExternalID: strconv.FormatInt(event.Data.ID, 10),
+	}
+
+	switch strings.TrimPrefix(event.Scope, "store/product/") {
+	case "deleted":
+		result.Action = "deleted"
+		return result, nil
+
+	case "created":
+		result.Action = "created"
+
+	case "updated", "inventory/updated":
+		result.Action = "updated"
+
+	default:
+		return nil, ErrUnsupportedScope
+	}
+
+	product, err := client.GetProduct(event.Data.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product %d: %w", event.Data.ID, err)
+	}
+
+	transformer, err := bc.cachedTransformer(client)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Product = transformer.TransformProduct(product)
+	return result, nil
+}
+
+// VerifyWebhook compares the secret header of a delivery with the registered secret
+func VerifyWebhook(header, secret string) bool {
+	if header == "" || secret == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(header), []byte(secret)) == 1
+}
+
+// cachedTransformer returns the store's transformer, loading it when it is missing or older
+// than TransformerCacheTTL
+func (bc *BigCommerceConnector) cachedTransformer(client *Client) (*Transformer, error) {
+	transformersMu.Lock()
+	cached, ok := transformers[client.storeHash]
+	transformersMu.Unlock()
+	if ok && time.Since(cached.loadedAt) < TransformerCacheTTL {
+		return cached.transformer, nil
+	}
+	return bc.loadTransformer(client)
+}
+
+// loadTransformer fetches the store's transformer and caches it for webhooks
+func (bc *BigCommerceConnector) loadTransformer(client *Client) (*Transformer, error) {
+	transformer, err := bc.newTransformer(client)
+	if err != nil {
+		return nil, err
+	}
+	transformersMu.Lock()
+	transformers[client.storeHash] = cachedTransformer{transformer: transformer, loadedAt: time.Now()}
+	transformersMu.Unlock()
+	return transformer, nil
+}
+
+// newTransformer loads the store currency, brands and categories needed to resolve product fields
+func (bc *BigCommerceConnector) newTransformer(client *Client) (*Transformer, error) {
+	store, err := client.GetStore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch store information: %w", err)
+	}
+
+	brands, err := client.GetBrands()
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := client.GetCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTransformer(store.Currency, store.SecureURL, brands, categories), nil
+}
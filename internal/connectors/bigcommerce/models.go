@@ -0,0 +1,138 @@
+package bigcommerce
+
+// Product represents a BigCommerce catalog product from the v3 API
+type Product struct {
+	ID                int64         `json:"id"`
+	Name              string        `json:"name"`
+	Type              string        `json:"type"`
+	SKU               string        `json:"sku"`
+	Description       string        `json:"description"`
+	Weight            float64       `json:"weight"`
+	Width             float64       `json:"width"`
+	Depth             float64       `json:"depth"`
+	Height            float64       `json:"height"`
+	Price             float64       `json:"price"`
+	RetailPrice       float64       `json:"retail_price"`
+	SalePrice         float64       `json:"sale_price"`
+	CalculatedPrice   float64       `json:"calculated_price"`
+	Categories        []int64       `json:"categories"`
+	BrandID           int64         `json:"brand_id"`
+	InventoryLevel    int           `json:"inventory_level"`
+	InventoryTracking string        `json:"inventory_tracking"`
+	Availability      string        `json:"availability"`
+	IsVisible         bool          `json:"is_visible"`
+	Condition         string        `json:"condition"`
+	UPC               string        `json:"upc"`
+	GTIN              string        `json:"gtin"`
+	MPN               string        `json:"mpn"`
+	TaxClassID        int64         `json:"tax_class_id"`
+	CustomURL         CustomURL     `json:"custom_url"`
+	DateCreated       string        `json:"date_created"`
+	DateModified      string        `json:"date_modified"`
+	Variants          []Variant     `json:"variants"`
+	Images            []Image       `json:"images"`
+	CustomFields      []CustomField `json:"custom_fields"`
+}
+
+// Variant represents a purchasable SKU of a product
+type Variant struct {
+	ID                 int64         `json:"id"`
+	ProductID          int64         `json:"product_id"`
+	SKU                string        `json:"sku"`
+	Price              *float64      `json:"price"` // nil when inherited from the product
+	SalePrice          *float64      `json:"sale_price"`
+	RetailPrice        *float64      `json:"retail_price"`
+	CalculatedPrice    float64       `json:"calculated_price"`
+	Weight             *float64      `json:"weight"`
+	InventoryLevel     int           `json:"inventory_level"`
+	PurchasingDisabled bool          `json:"purchasing_disabled"`
+	UPC                string        `json:"upc"`
+	GTIN               string        `json:"gtin"`
+	MPN                string        `json:"mpn"`
+	ImageURL           string        `json:"image_url"`
+	OptionValues       []OptionValue `json:"option_values"`
+}
+
+// OptionValue is the option a variant selects (e.g. Color: Red)
+type OptionValue struct {
+	ID                int64  `json:"id"`
+	Label             string `json:"label"`
+	OptionID          int64  `json:"option_id"`
+	OptionDisplayName string `json:"option_display_name"`
+}
+
+// Image represents a product image
+type Image struct {
+	ID          int64  `json:"id"`
+	IsThumbnail bool   `json:"is_thumbnail"`
+	SortOrder   int    `json:"sort_order"`
+	Description string `json:"description"`
+	URLStandard string `json:"url_standard"`
+	URLZoom     string `json:"url_zoom"`
+}
+
+// CustomField is a free-form name/value pair attached to a product
+type CustomField struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CustomURL is the storefront path of a product
+type CustomURL struct {
+	URL          string `json:"url"`
+	IsCustomized bool   `json:"is_customized"`
+}
+
+// Brand represents a catalog brand
+type Brand struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Category represents a catalog category
+type Category struct {
+	ID       int64  `json:"id"`
+	ParentID int64  `json:"parent_id"`
+	Name     string `json:"name"`
+}
+
+// Store is the subset of the v2 store information endpoint we use
+type Store struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Domain    string `json:"domain"`
+	SecureURL string `json:"secure_url"`
+	Currency  string `json:"currency"`
+}
+
+// Pagination is the meta.pagination block of v3 collection responses
+type Pagination struct {
+	Total       int `json:"total"`
+	Count       int `json:"count"`
+	PerPage     int `json:"per_page"`
+	CurrentPage int `json:"current_page"`
+	TotalPages  int `json:"total_pages"`
+}
+
+// Hook represents a registered webhook
+type Hook struct {
+	ID          int64             `json:"id,omitempty"`
+	Scope       string            `json:"scope"`
+	Destination string            `json:"destination"`
+	IsActive    bool              `json:"is_active"`
+	Headers     map[string]string `json:"headers,omitempty"`
+}
+
+// WebhookPayload is the body BigCommerce posts to a webhook destination
+type WebhookPayload struct {
+	Scope     string `json:"scope"`
+	StoreID   string `json:"store_id"`
+	Producer  string `json:"producer"`
+	Hash      string `json:"hash"`
+	CreatedAt int64  `json:"created_at"`
+	Data      struct {
+		Type string `json:"type"`
+		ID   int64  `json:"id"`
+	} `json:"data"`
+}
@@ -0,0 +1,273 @@
+package bigcommerce
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"lister/internal/models"
+)
+
+type Transformer struct {
+	currency   string
+	storeURL   string
+	brands     map[int64]string
+	categories map[int64]Category
+}
+
+// NewTransformer creates a transformer. Brands and categories are lookup tables used to
+// resolve the IDs BigCommerce returns on products and may be nil.
+func NewTransformer(currency, storeURL string, brands map[int64]string, categories map[int64]Category) *Transformer {
+	if currency == "" {
+		currency = "USD"
+	}
+	return &Transformer{
+		currency:   currency,
+		storeURL:   strings.TrimSuffix(storeURL, "/"),
+		brands:     brands,
+		categories: categories,
+	}
+}
+
+// TransformProduct converts a BigCommerce product to our canonical format
+func (t *Transformer) TransformProduct(product *Product) *models.Product {
+	price := product.CalculatedPrice
+	if price == 0 {
+		price = product.Price
+	}
+
+	var compareAtPrice *float64
+	if product.SalePrice > 0 && product.Price > product.SalePrice {
+		compareAtPrice = &product.Price
+	} else if product.RetailPrice > price {
+		compareAtPrice = &product.RetailPrice
+	}
+
+	images := make([]Image, len(product.Images))
+	copy(images, product.Images)
+	sort.SliceStable(images, func(i, j int) bool {
+		// The thumbnail is the main image on the storefront
+		if images[i].IsThumbnail != images[j].IsThumbnail {
+			return images[i].IsThumbnail
+		}
+		return images[i].SortOrder < images[j].SortOrder
+	})
+	imageURLs := make([]string, 0, len(images))
+	for _, img := range images {
+		if url := img.URLZoom; url != "" {
+			imageURLs = append(imageURLs, url)
+		} else if img.URLStandard != "" {
+			imageURLs = append(imageURLs, img.URLStandard)
+		}
+	}
+
+	categories := make([]string, 0, len(product.Categories))
+	for _, id := range product.Categories {
+		if cat, ok := t.categories[id]; ok {
+			categories = append(categories, cat.Name)
+		}
+	}
+	var category *string
+	if len(categories) > 0 {
+		category = &categories[0]
+	}
+
+	var brand *string
+	if name, ok := t.brands[product.BrandID]; ok && name != "" {
+		brand = &name
+	}
+
+	var gtin *string
+	for _, code := range []string{product.GTIN, product.UPC} {
+		if code != "" {
+			gtin = &code
+			break
+		}
+	}
+
+	var mpn *string
+	if product.MPN != "" {
+		mpn = &product.MPN
+	}
+
+	sku := product.SKU
+	if sku == "" {
+		sku = fmt.Sprintf("bigcommerce-%d", product.ID)
+	}
+
+	status := "ACTIVE"
+	if !product.IsVisible || product.Availability == "disabled" {
+		status = "DRAFT"
+	}
+
+	customLabels := make([]string, 0)
+	metadata := map[string]interface{}{
+		"bigcommerce_id":     product.ID,
+		"handle":             strings.Trim(product.CustomURL.URL, "/"),
+		"product_type":       product.Type,
+		"status":             status,
+		"condition":          strings.ToLower(product.Condition),
+		"inventory_level":    product.InventoryLevel,
+		"inventory_tracking": product.InventoryTracking,
+		"categories":         categories,
+		"created_at":         product.DateCreated,
+		"updated_at":         product.DateModified,
+	}
+	if t.storeURL != "" && product.CustomURL.URL != "" {
+		metadata["link"] = t.storeURL + product.CustomURL.URL
+	}
+	if product.SalePrice > 0 {
+		metadata["sale_price"] = product.SalePrice
+	}
+	for _, field := range product.CustomFields {
+		key := fieldKey(field.Name)
+		metadata["custom_"+key] = field.Value
+		// Custom fields named custom_label_N feed straight into the feed custom labels
+		if strings.HasPrefix(key, "custom_label") {
+			customLabels = append(customLabels, field.Value)
+		}
+	}
+
+	canonical := &models.Product{
+		ExternalID:     strconv.FormatInt(product.ID, 10),
+		SKU:            sku,
+		Title:          product.Name,
+		Description:    &product.Description,
+		Brand:          brand,
+		GTIN:           gtin,
+		MPN:            mpn,
+		Category:       category,
+		Price:          price,
+		CompareAtPrice: compareAtPrice,
+		Currency:       t.currency,
+		Availability:   availability(product.Availability, product.InventoryTracking, product.InventoryLevel, false),
+		Images:         imageURLs,
+		Variants:       t.TransformVariants(product),
+		Shipping:       shipping(product),
+		CustomLabels:   customLabels,
+		Metadata:       metadata,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	// Products tracked per variant are in stock when any variant is
+	if product.InventoryTracking == "variant" && len(canonical.Variants) > 0 {
+		canonical.Availability = string(models.AvailabilityOutOfStock)
+		for _, v := range canonical.Variants {
+			if v.Attributes["availability"] == string(models.AvailabilityInStock) {
+				canonical.Availability = string(models.AvailabilityInStock)
+				break
+			}
+		}
+	}
+
+	return canonical
+}
+
+// TransformVariants converts the variants of a product to our variant format.
+// BigCommerce returns a single base variant for products without options, which is skipped.
+// Option values are stored under their lower-cased option name (e.g. "color", "size").
+func (t *Transformer) TransformVariants(product *Product) []models.ProductVariant {
+	variants := make([]models.ProductVariant, 0, len(product.Variants))
+
+	for _, v := range product.Variants {
+		if len(v.OptionValues) == 0 {
+			continue
+		}
+
+		price := v.CalculatedPrice
+		if v.Price != nil && price == 0 {
+			price = *v.Price
+		}
+		if price == 0 {
+			price = product.Price
+		}
+
+		attributes := map[string]interface{}{
+			"availability":       availability(product.Availability, product.InventoryTracking, v.InventoryLevel, v.PurchasingDisabled),
+			"inventory_quantity": v.InventoryLevel,
+		}
+		if v.SalePrice != nil {
+			attributes["sale_price"] = *v.SalePrice
+		}
+		if v.RetailPrice != nil {
+			attributes["retail_price"] = *v.RetailPrice
+		}
+		if v.Weight != nil {
+			attributes["weight"] = *v.Weight
+		}
+		if v.ImageURL != "" {
+			attributes["image"] = v.ImageURL
+		}
+		if v.GTIN != "" {
+			attributes["gtin"] = v.GTIN
+		} else if v.UPC != "" {
+			attributes["gtin"] = v.UPC
+		}
+		if v.MPN != "" {
+			attributes["mpn"] = v.MPN
+		}
+
+		titleParts := make([]string, 0, len(v.OptionValues))
+		for _, option := range v.OptionValues {
+			attributes[fieldKey(option.OptionDisplayName)] = option.Label
+			titleParts = append(titleParts, option.Label)
+		}
+		attributes["title"] = strings.Join(titleParts, " / ")
+
+		variants = append(variants, models.ProductVariant{
+			ID:         strconv.FormatInt(v.ID, 10),
+			SKU:        v.SKU,
+			Price:      price,
+			Attributes: attributes,
+		})
+	}
+
+	return variants
+}
+
+// availability maps the BigCommerce availability setting and inventory level to our values.
+// Inventory is only meaningful when the product tracks it.
+func availability(productAvailability, tracking string, level int, purchasingDisabled bool) string {
+	if productAvailability == "disabled" || purchasingDisabled {
+		return string(models.AvailabilityOutOfStock)
+	}
+	if productAvailability == "preorder" {
+		return string(models.AvailabilityPreorder)
+	}
+	if tracking != "none" && tracking != "" && level <= 0 {
+		return string(models.AvailabilityOutOfStock)
+	}
+	return string(models.AvailabilityInStock)
+}
+
+func shipping(product *Product) *models.ShippingInfo {
+	info := &models.ShippingInfo{}
+	if product.Weight > 0 {
+		weight := product.Weight
+		unit := "lb" // BigCommerce default, like the dimension unit below
+		info.Weight = &weight
+		info.WeightUnit = &unit
+	}
+	if product.Depth > 0 || product.Width > 0 || product.Height > 0 {
+		info.Dimensions = &models.Dimensions{
+			Length: product.Depth,
+			Width:  product.Width,
+			Height: product.Height,
+			Unit:   "in", // BigCommerce default, the store unit is not exposed on the product
+		}
+	}
+
+	if info.Weight == nil && info.Dimensions == nil {
+		return nil
+	}
+	return info
+}
+
+// fieldKey normalises option and custom field names such as "Color" or "Custom Label 0"
+func fieldKey(name string) string {
+	key := strings.ToLower(strings.TrimSpace(name))
+	return strings.ReplaceAll(key, " ", "_")
+}
@@ -0,0 +1,199 @@
+package csvimport
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Config describes where a product file lives and how to read it, stored in
+// Connector.Config. Secrets live in Connector.Credentials (see Credentials).
+type Config struct {
+	Source          SourceConfig      `json:"source"`
+	Format          string            `json:"format"`     // csv, tsv or xlsx; detected from the file extension when empty
+	Delimiter       string            `json:"delimiter"`  // single character, defaults to "," (tab for tsv)
+	Encoding        string            `json:"encoding"`   // utf-8 (default), utf-16, iso-8859-1 or windows-1252
+	Sheet           string            `json:"sheet"`      // xlsx worksheet, defaults to the first one
+	HeaderRow       int               `json:"header_row"` // 1-based row holding the column names, defaults to 1
+	Mapping         map[string]string `json:"mapping"`    // canonical field -> column header, e.g. {"price": "Retail Price"}
+	DefaultCurrency string            `json:"default_currency"`
+	IntervalMinutes int               `json:"interval_minutes"` // schedule, 0 disables scheduled syncs
+	ArchiveMissing  *bool             `json:"archive_missing"`  // archive products no longer in the file, defaults to true
+}
+
+// SourceConfig selects the transport used to fetch the file
+type SourceConfig struct {
+	Type    string            `json:"type"` // url or sftp
+	URL     string            `json:"url"`  // url: http(s) address of the file
+	Headers map[string]string `json:"headers"`
+	Host    string            `json:"host"`     // sftp
+	Port    int               `json:"port"`     // sftp, defaults to 22
+	Path    string            `json:"path"`     // sftp: remote file path
+	HostKey string            `json:"host_key"` // sftp: required host key in authorized_keys format, e.g. from ssh-keyscan
+}
+
+// Credentials are the secrets used to fetch the file, stored in Connector.Credentials
+type Credentials struct {
+	Username   string `json:"username"` // sftp user or HTTP basic auth user
+	Password   string `json:"password"`
+	PrivateKey string `json:"private_key"` // sftp: PEM encoded key
+	Token      string `json:"token"`       // url: bearer token
+}
+
+// Fields are the canonical fields a column can be mapped to
+var Fields = []string{
+	"sku", "id", "title", "description", "price", "compare_at_price", "currency", "brand",
+	"gtin", "mpn", "category", "availability", "quantity", "image_url", "additional_image_urls",
+	"link", "status", "weight", "tax_class", "custom_labels",
+}
+
+// defaultHeaders lets files using the /csv/upload template columns work without a mapping
+var defaultHeaders = map[string][]string{
+	"image_url":        {"image_url", "image", "image_link"},
+	"compare_at_price": {"compare_at_price", "regular_price"},
+	"link":             {"link", "url"},
+	"quantity":         {"quantity", "stock", "inventory"},
+}
+
+// ParseConfig decodes and validates a connector configuration
+func ParseConfig(raw map[string]interface{}) (*Config, error) {
+	var cfg Config
+	if err := decode(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid connector config: %w", err)
+	}
+
+	cfg.applyDefaults()
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// ParseCredentials decodes connector credentials
+func ParseCredentials(raw map[string]interface{}) (*Credentials, error) {
+	var creds Credentials
+	if err := decode(raw, &creds); err != nil {
+		return nil, fmt.Errorf("invalid connector credentials: %w", err)
+	}
+	return &creds, nil
+}
+
+func (c *Config) applyDefaults() {
+	if c.Source.Type == "" {
+		c.Source.Type = "url"
+	}
+	if c.Source.Type == "sftp" && c.Source.Port == 0 {
+		c.Source.Port = 22
+	}
+
+	if c.Format == "" {
+		c.Format = formatFromPath(c.Source.URL + c.Source.Path)
+	}
+	if c.Delimiter == "" {
+		c.Delimiter = ","
+		if c.Format == "tsv" {
+			c.Delimiter = "\t"
+		}
+	}
+	if c.Encoding == "" {
+		c.Encoding = "utf-8"
+	}
+	if c.HeaderRow == 0 {
+		c.HeaderRow = 1
+	}
+	if c.ArchiveMissing == nil {
+		archive := true
+		c.ArchiveMissing = &archive
+	}
+}
+
+// Validate checks the configuration is complete
+func (c *Config) Validate() error {
+	switch c.Source.Type {
+	case "url":
+		u, err := url.Parse(c.Source.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("source.url must be an http(s) URL")
+		}
+	case "sftp":
+		if c.Source.Host == "" || c.Source.Path == "" {
+			return fmt.Errorf("sftp source requires host and path")
+		}
+		// The server's identity is always verified; get it with ssh-keyscan
+		if strings.TrimSpace(c.Source.HostKey) == "" {
+			return fmt.Errorf("sftp source requires host_key")
+		}
+		if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(c.Source.HostKey)); err != nil {
+			return fmt.Errorf("invalid source.host_key: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported source type %q", c.Source.Type)
+	}
+
+	switch c.Format {
+	case "csv", "tsv", "xlsx":
+	default:
+		return fmt.Errorf("unsupported format %q", c.Format)
+	}
+
+	if len([]rune(c.Delimiter)) != 1 {
+		return fmt.Errorf("delimiter must be a single character")
+	}
+
+	if _, err := decoder(c.Encoding); err != nil {
+		return err
+	}
+
+	for field := range c.Mapping {
+		if !isField(field) {
+			return fmt.Errorf("unknown mapping field %q", field)
+		}
+	}
+	return nil
+}
+
+// Due reports whether a scheduled sync should run given the previous sync time
+func (c *Config) Due(lastSync *time.Time, now time.Time) bool {
+	if c.IntervalMinutes <= 0 {
+		return false
+	}
+	return lastSync == nil || !now.Before(lastSync.Add(time.Duration(c.IntervalMinutes)*time.Minute))
+}
+
+func formatFromPath(p string) string {
+	if u, err := url.Parse(p); err == nil && u.Path != "" {
+		p = u.Path
+	}
+	switch strings.ToLower(path.Ext(p)) {
+	case ".tsv", ".tab":
+		return "tsv"
+	case ".xlsx":
+		return "xlsx"
+	default:
+		return "csv"
+	}
+}
+
+func isField(name string) bool {
+	for _, f := range Fields {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// decode converts a JSONB map into a typed struct
+func decode(raw map[string]interface{}, out interface{}) error {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
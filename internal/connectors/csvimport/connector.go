@@ -0,0 +1,91 @@
+package csvimport
+
+import (
+	"fmt"
+	"strings"
+
+	"lister/internal/config"
+	"lister/internal/logger"
+	"lister/internal/models"
+)
+
+type CSVConnector struct {
+	config *config.Config
+	logger *logger.Logger
+}
+
+func New(cfg *config.Config, logger *logger.Logger) *CSVConnector {
+	return &CSVConnector{
+		config: cfg,
+		logger: logger,
+	}
+}
+
+// RowError records a row that could not be imported
+type RowError struct {
+	Line  int    `json:"line"`
+	SKU   string `json:"sku,omitempty"` // the row's SKU, when it has one
+	Error string `json:"error"`
+}
+
+// Result is the outcome of reading a product file
+type Result struct {
+	Products []*models.Product
+	Errors   []RowError
+	// ArchiveMissing tells the caller to archive the connector's products whose SKU is not
+	// in Products nor in Errors, since a row that fails to import is still in the file. It
+	// is false when the file could not be read completely, so a broken download never
+	// archives the whole catalog.
+	ArchiveMissing bool
+}
+
+// SyncProducts fetches and parses the configured file. Duplicate SKUs keep the last row.
+func (cc *CSVConnector) SyncProducts(rawConfig, rawCredentials map[string]interface{}) (*Result, error) {
+	cfg, err := ParseConfig(rawConfig)
+	if err != nil {
+		return nil, err
+	}
+	creds, err := ParseCredentials(rawCredentials)
+	if err != nil {
+		return nil, err
+	}
+
+	cc.logger.Info("Fetching %s product file via %s", cfg.Format, cfg.Source.Type)
+
+	data, err := Fetch(cfg, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	headers, rows, err := Parse(data, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	transformer := NewTransformer(cfg, headers)
+	if missing := transformer.MissingColumns(); len(missing) > 0 {
+		return nil, fmt.Errorf("missing required columns: %s", strings.Join(missing, ", "))
+	}
+
+	result := &Result{Errors: []RowError{}}
+	bySKU := make(map[string]int)
+	for _, row := range rows {
+		product, err := transformer.TransformRow(row)
+		if err != nil {
+			result.Errors = append(result.Errors, RowError{Line: row.Line, SKU: transformer.value(row, "sku"), Error: err.Error()})
+			continue
+		}
+		if i, ok := bySKU[product.SKU]; ok {
+			result.Products[i] = product
+			continue
+		}
+		bySKU[product.SKU] = len(result.Products)
+		result.Products = append(result.Products, product)
+	}
+
+	result.ArchiveMissing = *cfg.ArchiveMissing && len(result.Products) > 0
+
+	cc.logger.Debug("CSV import parsed %d products from %d rows (%d errors)", len(result.Products), len(rows), len(result.Errors))
+
+	return result, nil
+}
@@ -0,0 +1,143 @@
+package csvimport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// Row is a data row keyed by normalised column header
+type Row struct {
+	Line   int // 1-based line (or sheet row) number in the file
+	Values map[string]string
+}
+
+// Parse reads the rows of a CSV, TSV or XLSX file. Rows above the header row are ignored
+// and blank rows are skipped.
+func Parse(data []byte, cfg *Config) ([]string, []Row, error) {
+	var records [][]string
+	var err error
+
+	if cfg.Format == "xlsx" {
+		records, err = readXLSX(data, cfg.Sheet)
+	} else {
+		records, err = readDelimited(data, cfg.Encoding, []rune(cfg.Delimiter)[0])
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(records) < cfg.HeaderRow {
+		return nil, nil, fmt.Errorf("file has no header row")
+	}
+
+	headers := make([]string, len(records[cfg.HeaderRow-1]))
+	for i, header := range records[cfg.HeaderRow-1] {
+		headers[i] = normalizeHeader(header)
+	}
+
+	var rows []Row
+	for i, record := range records[cfg.HeaderRow:] {
+		values := make(map[string]string, len(headers))
+		blank := true
+		for col, header := range headers {
+			if header == "" || col >= len(record) {
+				continue
+			}
+			value := strings.TrimSpace(record[col])
+			values[header] = value
+			if value != "" {
+				blank = false
+			}
+		}
+		if blank {
+			continue
+		}
+		rows = append(rows, Row{Line: cfg.HeaderRow + i + 1, Values: values})
+	}
+
+	return headers, rows, nil
+}
+
+func readDelimited(data []byte, encodingName string, delimiter rune) ([][]string, error) {
+	dec, err := decoder(encodingName)
+	if err != nil {
+		return nil, err
+	}
+
+	var r io.Reader = bytes.NewReader(data)
+	if dec != nil {
+		r = transform.NewReader(r, dec.NewDecoder())
+	}
+	// Drop a UTF-8 byte order mark, spreadsheet exports commonly start with one
+	utf8, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode file: %w", err)
+	}
+	utf8 = bytes.TrimPrefix(utf8, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(utf8))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file: %w", err)
+	}
+	return records, nil
+}
+
+func readXLSX(data []byte, sheet string) ([][]string, error) {
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open xlsx file: %w", err)
+	}
+	defer f.Close()
+
+	if sheet == "" {
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, fmt.Errorf("xlsx file has no worksheets")
+		}
+		sheet = sheets[0]
+	}
+
+	rows, err := f.GetRows(sheet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read worksheet %q: %w", sheet, err)
+	}
+	return rows, nil
+}
+
+// decoder returns the text decoder for an encoding name, nil for UTF-8
+func decoder(name string) (encoding.Encoding, error) {
+	switch strings.ToLower(strings.ReplaceAll(name, "_", "-")) {
+	case "", "utf-8", "utf8":
+		return nil, nil
+	case "utf-16", "utf16":
+		return unicode.UTF16(unicode.LittleEndian, unicode.UseBOM), nil
+	case "iso-8859-1", "latin1", "latin-1":
+		return charmap.ISO8859_1, nil
+	case "windows-1252", "cp1252":
+		return charmap.Windows1252, nil
+	case "iso-8859-15", "latin9":
+		return charmap.ISO8859_15, nil
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q", name)
+	}
+}
+
+// normalizeHeader makes header matching case and whitespace insensitive
+func normalizeHeader(header string) string {
+	header = strings.TrimPrefix(header, "\ufeff")
+	return strings.Join(strings.Fields(strings.ToLower(header)), " ")
+}
@@ -0,0 +1,118 @@
+package csvimport
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// maxFileSize guards against runaway downloads
+const maxFileSize = 200 << 20 // 200MB
+
+// Fetch downloads the configured file
+func Fetch(cfg *Config, creds *Credentials) ([]byte, error) {
+	switch cfg.Source.Type {
+	case "sftp":
+		return fetchSFTP(cfg.Source, creds)
+	default:
+		return fetchURL(cfg.Source, creds)
+	}
+}
+
+func fetchURL(source SourceConfig, creds *Credentials) ([]byte, error) {
+	req, err := http.NewRequest("GET", source.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for k, v := range source.Headers {
+		req.Header.Set(k, v)
+	}
+	switch {
+	case creds.Token != "":
+		req.Header.Set("Authorization", "Bearer "+creds.Token)
+	case creds.Username != "":
+		req.SetBasicAuth(creds.Username, creds.Password)
+	}
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download file: HTTP %d", resp.StatusCode)
+	}
+
+	return readLimited(resp.Body)
+}
+
+func fetchSFTP(source SourceConfig, creds *Credentials) ([]byte, error) {
+	var auth []ssh.AuthMethod
+	if creds.PrivateKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(creds.PrivateKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key: %w", err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if creds.Password != "" {
+		auth = append(auth, ssh.Password(creds.Password))
+	}
+	if len(auth) == 0 {
+		return nil, fmt.Errorf("sftp source requires a password or private key")
+	}
+
+	if source.HostKey == "" {
+		return nil, fmt.Errorf("sftp source requires host_key")
+	}
+	hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(source.HostKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid host key: %w", err)
+	}
+
+	address := net.JoinHostPort(source.Host, strconv.Itoa(source.Port))
+	conn, err := ssh.Dial("tcp", address, &ssh.ClientConfig{
+		User:            creds.Username,
+		Auth:            auth,
+		HostKeyCallback: ssh.FixedHostKey(hostKey),
+		Timeout:         30 * time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	defer conn.Close()
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start sftp session: %w", err)
+	}
+	defer client.Close()
+
+	file, err := client.Open(source.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", source.Path, err)
+	}
+	defer file.Close()
+
+	return readLimited(file)
+}
+
+func readLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if len(data) > maxFileSize {
+		return nil, fmt.Errorf("file exceeds %dMB limit", maxFileSize>>20)
+	}
+	return data, nil
+}
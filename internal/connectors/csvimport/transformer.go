@@ -0,0 +1,216 @@
+package csvimport
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"lister/internal/models"
+)
+
+type Transformer struct {
+	columns         map[string]string // canonical field -> normalised header
+	extra           []string          // headers not mapped to a field, kept as metadata
+	defaultCurrency string
+}
+
+// NewTransformer resolves the column of every canonical field. Explicit mappings win,
+// otherwise a header named like the field (or one of its aliases) is used.
+func NewTransformer(cfg *Config, headers []string) *Transformer {
+	present := make(map[string]bool, len(headers))
+	for _, h := range headers {
+		present[h] = true
+	}
+
+	columns := make(map[string]string)
+	for _, field := range Fields {
+		if header, ok := cfg.Mapping[field]; ok {
+			if header = normalizeHeader(header); present[header] {
+				columns[field] = header
+			}
+			continue
+		}
+		candidates := append([]string{field, strings.ReplaceAll(field, "_", " ")}, defaultHeaders[field]...)
+		for _, candidate := range candidates {
+			if present[candidate] {
+				columns[field] = candidate
+				break
+			}
+		}
+	}
+
+	mapped := make(map[string]bool, len(columns))
+	for _, header := range columns {
+		mapped[header] = true
+	}
+	var extra []string
+	for _, h := range headers {
+		if h != "" && !mapped[h] {
+			extra = append(extra, h)
+		}
+	}
+
+	currency := cfg.DefaultCurrency
+	if currency == "" {
+		currency = "USD"
+	}
+
+	return &Transformer{columns: columns, extra: extra, defaultCurrency: currency}
+}
+
+// MissingColumns returns the required fields that have no column in the file
+func (t *Transformer) MissingColumns() []string {
+	var missing []string
+	for _, field := range []string{"sku", "title", "price"} {
+		if _, ok := t.columns[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	return missing
+}
+
+// TransformRow converts a row to a canonical product. The SKU doubles as external ID so
+// products are upserted by SKU; an id column is kept in metadata.
+func (t *Transformer) TransformRow(row Row) (*models.Product, error) {
+	sku := t.value(row, "sku")
+	if sku == "" {
+		return nil, fmt.Errorf("missing sku")
+	}
+	title := t.value(row, "title")
+	if title == "" {
+		return nil, fmt.Errorf("missing title")
+	}
+	price, err := parseNumber(t.value(row, "price"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid price %q", t.value(row, "price"))
+	}
+
+	var compareAtPrice *float64
+	if compareAt, err := parseNumber(t.value(row, "compare_at_price")); err == nil && compareAt > price {
+		compareAtPrice = &compareAt
+	}
+
+	currency := strings.ToUpper(t.value(row, "currency"))
+	if currency == "" {
+		currency = t.defaultCurrency
+	}
+
+	status := strings.ToUpper(t.value(row, "status"))
+	if status == "" {
+		status = "ACTIVE"
+	}
+
+	images := []string{}
+	if image := t.value(row, "image_url"); image != "" {
+		images = append(images, image)
+	}
+	images = append(images, splitList(t.value(row, "additional_image_urls"))...)
+
+	metadata := map[string]interface{}{
+		"status":      status,
+		"source_line": row.Line,
+	}
+	if id := t.value(row, "id"); id != "" {
+		metadata["source_id"] = id
+	}
+	if link := t.value(row, "link"); link != "" {
+		metadata["link"] = link
+	}
+	for _, header := range t.extra {
+		if value := row.Values[header]; value != "" {
+			metadata[strings.ReplaceAll(header, " ", "_")] = value
+		}
+	}
+
+	var shipping *models.ShippingInfo
+	if weight, err := parseNumber(t.value(row, "weight")); err == nil {
+		shipping = &models.ShippingInfo{Weight: &weight}
+	}
+
+	return &models.Product{
+		ExternalID:     sku,
+		SKU:            sku,
+		Title:          title,
+		Description:    t.optional(row, "description"),
+		Brand:          t.optional(row, "brand"),
+		GTIN:           t.optional(row, "gtin"),
+		MPN:            t.optional(row, "mpn"),
+		Category:       t.optional(row, "category"),
+		Price:          price,
+		CompareAtPrice: compareAtPrice,
+		Currency:       currency,
+		Availability:   t.availability(row),
+		Images:         images,
+		Shipping:       shipping,
+		TaxClass:       t.optional(row, "tax_class"),
+		CustomLabels:   splitList(t.value(row, "custom_labels")),
+		Metadata:       metadata,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}, nil
+}
+
+func (t *Transformer) availability(row Row) string {
+	value := strings.ToLower(strings.NewReplacer(" ", "", "_", "", "-", "").Replace(t.value(row, "availability")))
+	switch value {
+	case "outofstock", "soldout", "no", "false", "0":
+		return string(models.AvailabilityOutOfStock)
+	case "preorder":
+		return string(models.AvailabilityPreorder)
+	case "backorder", "onbackorder":
+		return string(models.AvailabilityBackorder)
+	case "":
+		if quantity, err := parseNumber(t.value(row, "quantity")); err == nil && quantity <= 0 {
+			return string(models.AvailabilityOutOfStock)
+		}
+	}
+	return string(models.AvailabilityInStock)
+}
+
+func (t *Transformer) value(row Row, field string) string {
+	header, ok := t.columns[field]
+	if !ok {
+		return ""
+	}
+	return row.Values[header]
+}
+
+func (t *Transformer) optional(row Row, field string) *string {
+	if value := t.value(row, field); value != "" {
+		return &value
+	}
+	return nil
+}
+
+// parseNumber accepts prices such as "19.99", "$1,299.00" or "19.99 USD"
+func parseNumber(value string) (float64, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '.' || r == '-' {
+			return r
+		}
+		return -1
+	}, value)
+	if cleaned == "" {
+		return 0, fmt.Errorf("empty number")
+	}
+	return strconv.ParseFloat(cleaned, 64)
+}
+
+// splitList splits multi-value cells on "|" or ","
+func splitList(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	sep := ","
+	if strings.Contains(value, "|") {
+		sep = "|"
+	}
+	var items []string
+	for _, item := range strings.Split(value, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,227 @@
+package magento
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"lister/internal/logger"
+)
+
+const (
+	// pageSize keeps product pages small enough for stores with large custom attribute sets
+	pageSize = 100
+	// timeLayout is the format Magento uses for created_at/updated_at
+	timeLayout = "2006-01-02 15:04:05"
+)
+
+type Client struct {
+	storeURL    string
+	accessToken string
+	httpClient  *http.Client
+	logger      *logger.Logger
+}
+
+// NewClient creates a client for the REST API of a Magento 2 / Adobe Commerce store.
+// The access token is an integration token sent as a bearer token.
+func NewClient(storeURL, accessToken string, logger *logger.Logger) *Client {
+	return &Client{
+		storeURL:    strings.TrimSuffix(storeURL, "/"),
+		accessToken: accessToken,
+		httpClient: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
+// Filter is a single searchCriteria filter
+type Filter struct {
+	Field     string
+	Value     string
+	Condition string // eq, gt, gteq, in, ...
+}
+
+// searchCriteria builds the query string for a paged list endpoint. Filters are ANDed
+// by placing each one in its own filter group.
+func searchCriteria(page, size int, filters ...Filter) url.Values {
+	q := url.Values{}
+	q.Set("searchCriteria[currentPage]", strconv.Itoa(page))
+	q.Set("searchCriteria[pageSize]", strconv.Itoa(size))
+	for i, f := range filters {
+		prefix := fmt.Sprintf("searchCriteria[filterGroups][%d][filters][0]", i)
+		q.Set(prefix+"[field]", f.Field)
+		q.Set(prefix+"[value]", f.Value)
+		q.Set(prefix+"[conditionType]", f.Condition)
+	}
+	return q
+}
+
+// GetProducts fetches a single page of products and returns the total number of matches.
+// When updatedSince is set only products changed after it are returned.
+func (c *Client) GetProducts(page, size int, updatedSince *time.Time) ([]Product, int, error) {
+	var filters []Filter
+	if updatedSince != nil {
+		filters = append(filters, Filter{
+			Field:     "updated_at",
+			Value:     updatedSince.UTC().Format(timeLayout),
+			Condition: "gt",
+		})
+	}
+
+	var resp struct {
+		Items      []Product `json:"items"`
+		TotalCount int       `json:"total_count"`
+	}
+	if err := c.get("/products", searchCriteria(page, size, filters...), &resp); err != nil {
+		return nil, 0, err
+	}
+	return resp.Items, resp.TotalCount, nil
+}
+
+// GetAllProducts walks every page of the products endpoint. Magento keeps returning
+// the last page for out of range page numbers, so paging stops on total_count.
+func (c *Client) GetAllProducts(updatedSince *time.Time) ([]Product, error) {
+	var all []Product
+	for page := 1; ; page++ {
+		products, total, err := c.GetProducts(page, pageSize, updatedSince)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch products page %d: %w", page, err)
+		}
+
+		c.logger.Debug("Fetched Magento products page %d (%d/%d products)", page, len(all)+len(products), total)
+		all = append(all, products...)
+
+		if len(all) >= total || len(products) == 0 {
+			break
+		}
+	}
+	return all, nil
+}
+
+// GetProduct fetches a single product by SKU
+func (c *Client) GetProduct(sku string) (*Product, error) {
+	var product Product
+	if err := c.get("/products/"+url.PathEscape(sku), nil, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// GetChildren returns the simple products linked to a configurable product
+func (c *Client) GetChildren(sku string) ([]Product, error) {
+	var children []Product
+	if err := c.get("/configurable-products/"+url.PathEscape(sku)+"/children", nil, &children); err != nil {
+		return nil, fmt.Errorf("failed to fetch children of %s: %w", sku, err)
+	}
+	return children, nil
+}
+
+// GetAttribute fetches an EAV attribute and its options by code or ID
+func (c *Client) GetAttribute(codeOrID string) (*Attribute, error) {
+	var attribute Attribute
+	if err := c.get("/products/attributes/"+url.PathEscape(codeOrID), nil, &attribute); err != nil {
+		return nil, err
+	}
+	return &attribute, nil
+}
+
+// GetSourceItems fetches the MSI source items of the given SKUs
+func (c *Client) GetSourceItems(skus []string) ([]SourceItem, error) {
+	var all []SourceItem
+	for start := 0; start < len(skus); start += pageSize {
+		end := start + pageSize
+		if end > len(skus) {
+			end = len(skus)
+		}
+
+		var resp struct {
+			Items []SourceItem `json:"items"`
+		}
+		// A SKU has one item per source, so size the page for a few sources per SKU
+		query := searchCriteria(1, (end-start)*10, Filter{
+			Field:     "sku",
+			Value:     strings.Join(skus[start:end], ","),
+			Condition: "in",
+		})
+		if err := c.get("/inventory/source-items", query, &resp); err != nil {
+			return nil, fmt.Errorf("failed to fetch source items: %w", err)
+		}
+		all = append(all, resp.Items...)
+	}
+	return all, nil
+}
+
+// GetCategories returns every category keyed by ID
+func (c *Client) GetCategories() (map[string]Category, error) {
+	categories := make(map[string]Category)
+	for page := 1; ; page++ {
+		var resp struct {
+			Items      []Category `json:"items"`
+			TotalCount int        `json:"total_count"`
+		}
+		if err := c.get("/categories/list", searchCriteria(page, 500), &resp); err != nil {
+			return nil, fmt.Errorf("failed to fetch categories: %w", err)
+		}
+		for _, cat := range resp.Items {
+			categories[strconv.FormatInt(cat.ID, 10)] = cat
+		}
+		if len(categories) >= resp.TotalCount || len(resp.Items) == 0 {
+			break
+		}
+	}
+	return categories, nil
+}
+
+// GetCurrency fetches the store currency configuration
+func (c *Client) GetCurrency() (*Currency, error) {
+	var currency Currency
+	if err := c.get("/directory/currency", nil, &currency); err != nil {
+		return nil, err
+	}
+	return &currency, nil
+}
+
+// Ping verifies the credentials by requesting a single product
+func (c *Client) Ping() error {
+	_, _, err := c.GetProducts(1, 1, nil)
+	return err
+}
+
+// get performs an authenticated GET against the V1 REST API and decodes the body into out
+func (c *Client) get(path string, query url.Values, out interface{}) error {
+	endpoint := fmt.Sprintf("%s/rest/V1%s", c.storeURL, path)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API request failed: %d - %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package magento
+
+import (
+	"strconv"
+	"time"
+
+	"lister/internal/config"
+	"lister/internal/logger"
+	"lister/internal/models"
+)
+
+// mappedAttributes are the EAV attributes resolved to canonical fields and feed metadata
+var mappedAttributes = []string{"color", "size", "manufacturer", "brand", "material", "gender"}
+
+type MagentoConnector struct {
+	config *config.Config
+	logger *logger.Logger
+}
+
+func New(cfg *config.Config, logger *logger.Logger) *MagentoConnector {
+	return &MagentoConnector{
+		config: cfg,
+		logger: logger,
+	}
+}
+
+// SyncProducts fetches the catalog from a Magento 2 store and returns it in canonical format.
+// Configurable products are returned with their children as variants; children that are not
+// visible individually are not returned on their own. When updatedSince is set only products
+// changed after it are fetched. A child whose parent did not change is picked up on the next
+// full sync.
+func (mc *MagentoConnector) SyncProducts(storeURL, accessToken string, updatedSince *time.Time) ([]*models.Product, error) {
+	if updatedSince != nil {
+		mc.logger.Info("Syncing products changed since %s from Magento store: %s", updatedSince.Format(time.RFC3339), storeURL)
+	} else {
+		mc.logger.Info("Syncing products from Magento store: %s", storeURL)
+	}
+
+	client := NewClient(storeURL, accessToken, mc.logger)
+
+	products, err := client.GetAllProducts(updatedSince)
+	if err != nil {
+		return nil, err
+	}
+
+	currency := ""
+	if c, err := client.GetCurrency(); err != nil {
+		mc.logger.Error("Failed to fetch Magento store currency: %v", err)
+	} else {
+		currency = c.BaseCurrencyCode
+	}
+
+	categories, err := client.GetCategories()
+	if err != nil {
+		// Categories are optional for the feed, keep syncing without them
+		mc.logger.Error("Failed to fetch Magento categories: %v", err)
+	}
+
+	attributeCodes := append([]string(nil), mappedAttributes...)
+	children := make(map[string][]Product)
+	skus := make([]string, 0, len(products))
+
+	parents := make([]*Product, 0, len(products))
+	for i := range products {
+		product := &products[i]
+		if product.Visibility == 1 {
+			continue
+		}
+		parents = append(parents, product)
+		skus = append(skus, product.SKU)
+
+		if product.TypeID != "configurable" {
+			continue
+		}
+
+		for _, option := range product.ExtensionAttributes.ConfigurableProductOptions {
+			attributeCodes = append(attributeCodes, option.AttributeID)
+		}
+
+		productChildren, err := client.GetChildren(product.SKU)
+		if err != nil {
+			// Keep the parent product so a single broken configurable doesn't drop it
+			mc.logger.Error("Failed to fetch children for Magento product %s: %v", product.SKU, err)
+			continue
+		}
+		children[product.SKU] = productChildren
+		for _, child := range productChildren {
+			skus = append(skus, child.SKU)
+		}
+	}
+
+	transformer := NewTransformer(currency, storeURL, mc.loadAttributes(client, attributeCodes), categories, mc.loadStock(client, skus))
+
+	canonical := make([]*models.Product, 0, len(parents))
+	for _, product := range parents {
+		canonical = append(canonical, transformer.TransformProduct(product, children[product.SKU]))
+	}
+
+	mc.logger.Debug("Magento sync completed: %d products", len(canonical))
+
+	return canonical, nil
+}
+
+// loadAttributes fetches the attribute definitions used to resolve option IDs to labels.
+// Attributes that don't exist in the store are skipped.
+func (mc *MagentoConnector) loadAttributes(client *Client, codes []string) map[string]*Attribute {
+	attributes := make(map[string]*Attribute)
+	for _, code := range codes {
+		if _, ok := attributes[code]; ok {
+			continue
+		}
+		attribute, err := client.GetAttribute(code)
+		if err != nil {
+			mc.logger.Debug("Magento attribute %s not available: %v", code, err)
+			continue
+		}
+		attributes[attribute.AttributeCode] = attribute
+		attributes[strconv.FormatInt(attribute.AttributeID, 10)] = attribute
+	}
+	return attributes
+}
+
+// loadStock sums MSI source item quantities per SKU. It returns nil when the store doesn't
+// run MSI so the transformer falls back to the legacy stock item.
+func (mc *MagentoConnector) loadStock(client *Client, skus []string) map[string]StockLevel {
+	if len(skus) == 0 {
+		return nil
+	}
+
+	items, err := client.GetSourceItems(skus)
+	if err != nil {
+		mc.logger.Debug("Magento MSI source items not available, using stock items: %v", err)
+		return nil
+	}
+
+	stock := make(map[string]StockLevel)
+	for _, item := range items {
+		level := stock[item.SKU]
+		if item.Status == 1 {
+			level.Quantity += item.Quantity
+			if item.Quantity > 0 {
+				level.InStock = true
+			}
+		}
+		stock[item.SKU] = level
+	}
+	return stock
+}
@@ -0,0 +1,104 @@
+package magento
+
+// Product represents a Magento 2 catalog product from the REST API
+type Product struct {
+	ID                  int64               `json:"id"`
+	SKU                 string              `json:"sku"`
+	Name                string              `json:"name"`
+	AttributeSetID      int64               `json:"attribute_set_id"`
+	Price               float64             `json:"price"`
+	Status              int                 `json:"status"`     // 1 enabled, 2 disabled
+	Visibility          int                 `json:"visibility"` // 1 not visible individually, 2 catalog, 3 search, 4 both
+	TypeID              string              `json:"type_id"`
+	Weight              float64             `json:"weight"`
+	CreatedAt           string              `json:"created_at"`
+	UpdatedAt           string              `json:"updated_at"`
+	ExtensionAttributes ExtensionAttributes `json:"extension_attributes"`
+	MediaGalleryEntries []MediaEntry        `json:"media_gallery_entries"`
+	CustomAttributes    []CustomAttribute   `json:"custom_attributes"`
+}
+
+// ExtensionAttributes holds the module-provided data of a product
+type ExtensionAttributes struct {
+	WebsiteIDs                 []int64              `json:"website_ids"`
+	CategoryLinks              []CategoryLink       `json:"category_links"`
+	StockItem                  *StockItem           `json:"stock_item"`
+	ConfigurableProductOptions []ConfigurableOption `json:"configurable_product_options"`
+	ConfigurableProductLinks   []int64              `json:"configurable_product_links"`
+}
+
+// CategoryLink assigns a product to a category
+type CategoryLink struct {
+	Position   int    `json:"position"`
+	CategoryID string `json:"category_id"`
+}
+
+// StockItem is the legacy CatalogInventory stock of a product
+type StockItem struct {
+	Qty       float64 `json:"qty"`
+	IsInStock bool    `json:"is_in_stock"`
+}
+
+// ConfigurableOption is a super attribute of a configurable product (e.g. color)
+type ConfigurableOption struct {
+	ID          int64  `json:"id"`
+	AttributeID string `json:"attribute_id"`
+	Label       string `json:"label"`
+	Position    int    `json:"position"`
+}
+
+// MediaEntry is an image or video of the product gallery
+type MediaEntry struct {
+	ID        int64    `json:"id"`
+	MediaType string   `json:"media_type"`
+	Label     string   `json:"label"`
+	Position  int      `json:"position"`
+	Disabled  bool     `json:"disabled"`
+	Types     []string `json:"types"`
+	File      string   `json:"file"`
+}
+
+// CustomAttribute is an EAV attribute value. Value is a string for most attributes and
+// a list of strings for multiselects and category_ids.
+type CustomAttribute struct {
+	AttributeCode string      `json:"attribute_code"`
+	Value         interface{} `json:"value"`
+}
+
+// Attribute is an EAV attribute definition with its select options
+type Attribute struct {
+	AttributeID   int64             `json:"attribute_id"`
+	AttributeCode string            `json:"attribute_code"`
+	FrontendInput string            `json:"frontend_input"`
+	DefaultLabel  string            `json:"default_frontend_label"`
+	Options       []AttributeOption `json:"options"`
+}
+
+// AttributeOption maps a stored option ID to its admin label
+type AttributeOption struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// SourceItem is the MSI stock of a SKU at one inventory source
+type SourceItem struct {
+	SKU        string  `json:"sku"`
+	SourceCode string  `json:"source_code"`
+	Quantity   float64 `json:"quantity"`
+	Status     int     `json:"status"` // 1 in stock, 0 out of stock
+}
+
+// Category is a catalog category
+type Category struct {
+	ID       int64  `json:"id"`
+	ParentID int64  `json:"parent_id"`
+	Name     string `json:"name"`
+	IsActive bool   `json:"is_active"`
+	Level    int    `json:"level"`
+}
+
+// Currency is the store currency configuration
+type Currency struct {
+	BaseCurrencyCode           string `json:"base_currency_code"`
+	DefaultDisplayCurrencyCode string `json:"default_display_currency_code"`
+}
@@ -0,0 +1,357 @@
+package magento
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"lister/internal/models"
+)
+
+// StockLevel is the salable stock of a SKU summed over its inventory sources
+type StockLevel struct {
+	Quantity float64
+	InStock  bool
+}
+
+type Transformer struct {
+	currency   string
+	storeURL   string
+	attributes map[string]*Attribute // keyed by attribute code and by attribute ID
+	categories map[string]Category
+	stock      map[string]StockLevel // MSI stock keyed by SKU, nil when MSI is unavailable
+}
+
+// NewTransformer creates a transformer. The lookup tables resolve EAV option IDs, category IDs
+// and MSI stock; any of them may be nil.
+func NewTransformer(currency, storeURL string, attributes map[string]*Attribute, categories map[string]Category, stock map[string]StockLevel) *Transformer {
+	if currency == "" {
+		currency = "USD"
+	}
+	return &Transformer{
+		currency:   currency,
+		storeURL:   strings.TrimSuffix(storeURL, "/"),
+		attributes: attributes,
+		categories: categories,
+		stock:      stock,
+	}
+}
+
+// TransformProduct converts a Magento product to our canonical format. Children are the
+// simple products of a configurable product and become its variants.
+func (t *Transformer) TransformProduct(product *Product, children []Product) *models.Product {
+	price, compareAtPrice := t.price(product)
+
+	// Configurable products have no price of their own, use the cheapest child
+	if product.TypeID == "configurable" && len(children) > 0 {
+		price, compareAtPrice = 0, nil
+		for i := range children {
+			childPrice, childCompareAt := t.price(&children[i])
+			if childPrice > 0 && (price == 0 || childPrice < price) {
+				price, compareAtPrice = childPrice, childCompareAt
+			}
+		}
+	}
+
+	categories := make([]string, 0, len(product.ExtensionAttributes.CategoryLinks))
+	for _, link := range product.ExtensionAttributes.CategoryLinks {
+		if cat, ok := t.categories[link.CategoryID]; ok {
+			categories = append(categories, cat.Name)
+		}
+	}
+	var category *string
+	if len(categories) > 0 {
+		category = &categories[0]
+	}
+
+	description := t.attribute(product, "description")
+	if description == nil {
+		description = t.attribute(product, "short_description")
+	}
+
+	brand := t.attribute(product, "manufacturer")
+	if brand == nil {
+		brand = t.attribute(product, "brand")
+	}
+
+	var gtin *string
+	for _, code := range []string{"gtin", "ean", "upc", "barcode"} {
+		if gtin = t.attribute(product, code); gtin != nil {
+			break
+		}
+	}
+
+	status := "ACTIVE"
+	if product.Status != 1 {
+		status = "DRAFT"
+	}
+
+	metadata := map[string]interface{}{
+		"magento_id":   product.ID,
+		"product_type": product.TypeID,
+		"status":       status,
+		"visibility":   product.Visibility,
+		"categories":   categories,
+		"created_at":   product.CreatedAt,
+		"updated_at":   product.UpdatedAt,
+	}
+	if urlKey := t.attribute(product, "url_key"); urlKey != nil {
+		metadata["handle"] = *urlKey
+		if t.storeURL != "" {
+			metadata["link"] = t.storeURL + "/" + *urlKey + ".html"
+		}
+	}
+	for _, code := range []string{"color", "size", "material", "gender", "meta_title", "meta_description"} {
+		if value := t.attribute(product, code); value != nil {
+			metadata[code] = *value
+		}
+	}
+	if level, ok := t.stockLevel(product); ok {
+		metadata["stock_quantity"] = level.Quantity
+	}
+
+	canonical := &models.Product{
+		ExternalID:     strconv.FormatInt(product.ID, 10),
+		SKU:            product.SKU,
+		Title:          product.Name,
+		Description:    description,
+		Brand:          brand,
+		GTIN:           gtin,
+		MPN:            t.attribute(product, "mpn"),
+		Category:       category,
+		Price:          price,
+		CompareAtPrice: compareAtPrice,
+		Currency:       t.currency,
+		Availability:   t.availability(product),
+		Images:         t.images(product),
+		Variants:       t.TransformVariants(product, children),
+		Shipping:       shipping(product.Weight),
+		Metadata:       metadata,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	// A configurable product is in stock when any of its children is
+	if product.TypeID == "configurable" && len(canonical.Variants) > 0 {
+		canonical.Availability = string(models.AvailabilityOutOfStock)
+		for _, v := range canonical.Variants {
+			if v.Attributes["availability"] == string(models.AvailabilityInStock) {
+				canonical.Availability = string(models.AvailabilityInStock)
+				break
+			}
+		}
+	}
+
+	return canonical
+}
+
+// TransformVariants converts the children of a configurable product to our variant format.
+// The configurable (super) attributes are stored under their attribute code with the option
+// label as value, e.g. "color": "Red".
+func (t *Transformer) TransformVariants(parent *Product, children []Product) []models.ProductVariant {
+	superAttributes := make([]string, 0, len(parent.ExtensionAttributes.ConfigurableProductOptions))
+	options := append([]ConfigurableOption(nil), parent.ExtensionAttributes.ConfigurableProductOptions...)
+	sort.SliceStable(options, func(i, j int) bool { return options[i].Position < options[j].Position })
+	for _, option := range options {
+		if attr, ok := t.attributes[option.AttributeID]; ok {
+			superAttributes = append(superAttributes, attr.AttributeCode)
+		} else {
+			superAttributes = append(superAttributes, fieldKey(option.Label))
+		}
+	}
+
+	variants := make([]models.ProductVariant, 0, len(children))
+	for i := range children {
+		child := &children[i]
+		price, compareAtPrice := t.price(child)
+
+		attributes := map[string]interface{}{
+			"availability": t.availability(child),
+			"status":       child.Status,
+			"weight":       child.Weight,
+		}
+		if compareAtPrice != nil {
+			attributes["compare_at_price"] = *compareAtPrice
+		}
+		if level, ok := t.stockLevel(child); ok {
+			attributes["inventory_quantity"] = level.Quantity
+		}
+		if images := t.images(child); len(images) > 0 {
+			attributes["image"] = images[0]
+		}
+		for _, code := range []string{"gtin", "ean", "upc", "barcode"} {
+			if gtin := t.attribute(child, code); gtin != nil {
+				attributes["gtin"] = *gtin
+				break
+			}
+		}
+
+		titleParts := make([]string, 0, len(superAttributes))
+		for _, code := range superAttributes {
+			if value := t.attribute(child, code); value != nil {
+				attributes[code] = *value
+				titleParts = append(titleParts, *value)
+			}
+		}
+		attributes["title"] = strings.Join(titleParts, " / ")
+
+		variants = append(variants, models.ProductVariant{
+			ID:         strconv.FormatInt(child.ID, 10),
+			SKU:        child.SKU,
+			Price:      price,
+			Attributes: attributes,
+		})
+	}
+
+	return variants
+}
+
+// price returns the current price and, while a special price is active, the regular price
+func (t *Transformer) price(product *Product) (float64, *float64) {
+	special := t.attribute(product, "special_price")
+	if special == nil {
+		return product.Price, nil
+	}
+
+	specialPrice, err := strconv.ParseFloat(*special, 64)
+	if err != nil || specialPrice <= 0 || specialPrice >= product.Price {
+		return product.Price, nil
+	}
+
+	now := time.Now()
+	if from := t.attribute(product, "special_from_date"); from != nil {
+		if start, err := time.Parse(timeLayout, *from); err == nil && now.Before(start) {
+			return product.Price, nil
+		}
+	}
+	if to := t.attribute(product, "special_to_date"); to != nil {
+		// The end date is inclusive
+		if end, err := time.Parse(timeLayout, *to); err == nil && now.After(end.Add(24*time.Hour)) {
+			return product.Price, nil
+		}
+	}
+
+	regular := product.Price
+	return specialPrice, &regular
+}
+
+// availability prefers MSI source items and falls back to the legacy stock item
+func (t *Transformer) availability(product *Product) string {
+	if product.Status != 1 {
+		return string(models.AvailabilityOutOfStock)
+	}
+	if level, ok := t.stockLevel(product); ok {
+		if level.InStock {
+			return string(models.AvailabilityInStock)
+		}
+		return string(models.AvailabilityOutOfStock)
+	}
+	if item := product.ExtensionAttributes.StockItem; item != nil && !item.IsInStock {
+		return string(models.AvailabilityOutOfStock)
+	}
+	return string(models.AvailabilityInStock)
+}
+
+func (t *Transformer) stockLevel(product *Product) (StockLevel, bool) {
+	if t.stock != nil {
+		if level, ok := t.stock[product.SKU]; ok {
+			return level, true
+		}
+	}
+	if item := product.ExtensionAttributes.StockItem; item != nil {
+		return StockLevel{Quantity: item.Qty, InStock: item.IsInStock}, true
+	}
+	return StockLevel{}, false
+}
+
+// images returns the enabled gallery images with the base image first
+func (t *Transformer) images(product *Product) []string {
+	entries := make([]MediaEntry, 0, len(product.MediaGalleryEntries))
+	for _, entry := range product.MediaGalleryEntries {
+		if entry.MediaType == "image" && !entry.Disabled && entry.File != "" {
+			entries = append(entries, entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if bi, bj := hasType(entries[i], "image"), hasType(entries[j], "image"); bi != bj {
+			return bi
+		}
+		return entries[i].Position < entries[j].Position
+	})
+
+	images := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		images = append(images, t.storeURL+"/media/catalog/product"+entry.File)
+	}
+	return images
+}
+
+// attribute returns the value of a custom attribute, resolving select and multiselect
+// option IDs to their labels when the attribute definition is known
+func (t *Transformer) attribute(product *Product, code string) *string {
+	for _, custom := range product.CustomAttributes {
+		if custom.AttributeCode != code {
+			continue
+		}
+
+		var values []string
+		switch v := custom.Value.(type) {
+		case string:
+			if v != "" {
+				values = strings.Split(v, ",")
+			}
+		case []interface{}:
+			for _, item := range v {
+				values = append(values, fmt.Sprint(item))
+			}
+		}
+		if len(values) == 0 {
+			return nil
+		}
+
+		attr, ok := t.attributes[code]
+		if !ok || (attr.FrontendInput != "select" && attr.FrontendInput != "multiselect") {
+			value := strings.Join(values, ",")
+			return &value
+		}
+
+		labels := make([]string, 0, len(values))
+		for _, value := range values {
+			label := value
+			for _, option := range attr.Options {
+				if option.Value == strings.TrimSpace(value) {
+					label = option.Label
+					break
+				}
+			}
+			labels = append(labels, label)
+		}
+		value := strings.Join(labels, ", ")
+		return &value
+	}
+	return nil
+}
+
+func hasType(entry MediaEntry, mediaType string) bool {
+	for _, t := range entry.Types {
+		if t == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+func shipping(weight float64) *models.ShippingInfo {
+	if weight <= 0 {
+		return nil
+	}
+	return &models.ShippingInfo{Weight: &weight}
+}
+
+// fieldKey normalises an option label such as "Shoe Size" to "shoe_size"
+func fieldKey(name string) string {
+	key := strings.ToLower(strings.TrimSpace(name))
+	return strings.ReplaceAll(key, " ", "_")
+}
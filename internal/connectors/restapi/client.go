@@ -0,0 +1,224 @@
+package restapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"lister/internal/logger"
+)
+
+type Client struct {
+	config      *Config
+	credentials *Credentials
+	itemsPath   Path
+	cursorPath  Path
+	httpClient  *http.Client
+	logger      *logger.Logger
+}
+
+// NewClient creates a client for a validated configuration
+func NewClient(cfg *Config, creds *Credentials, logger *logger.Logger) (*Client, error) {
+	if creds == nil {
+		creds = &Credentials{}
+	}
+
+	itemsPath, err := CompilePath(cfg.ItemsPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid items_path: %w", err)
+	}
+	var cursorPath Path
+	if cfg.Pagination.CursorPath != "" {
+		if cursorPath, err = CompilePath(cfg.Pagination.CursorPath); err != nil {
+			return nil, fmt.Errorf("invalid cursor_path: %w", err)
+		}
+	}
+
+	return &Client{
+		config:      cfg,
+		credentials: creds,
+		itemsPath:   itemsPath,
+		cursorPath:  cursorPath,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		logger: logger,
+	}, nil
+}
+
+// FetchItems walks the pages of the source and returns the raw items. When limit is
+// positive paging stops as soon as that many items have been collected.
+func (c *Client) FetchItems(limit int) ([]interface{}, error) {
+	p := c.config.Pagination
+
+	var all []interface{}
+	nextURL := ""
+	cursor := ""
+
+	for page := 0; page < p.MaxPages; page++ {
+		reqURL, err := c.pageURL(page, len(all), cursor, nextURL)
+		if err != nil {
+			return nil, err
+		}
+
+		body, header, err := c.do(reqURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch page %d: %w", page+1, err)
+		}
+
+		items := c.extractItems(body)
+		c.logger.Debug("Fetched REST connector page %d (%d items)", page+1, len(items))
+		all = append(all, items...)
+
+		if limit > 0 && len(all) >= limit {
+			return all[:limit], nil
+		}
+		if len(items) == 0 {
+			break
+		}
+
+		switch p.Type {
+		case "none":
+			return all, nil
+		case "page", "offset":
+			// A short page is the last one
+			if len(items) < p.PageSize {
+				return all, nil
+			}
+		case "cursor":
+			value, ok := c.cursorPath.First(body)
+			cursor = toString(value)
+			if !ok || cursor == "" {
+				return all, nil
+			}
+		case "link_header":
+			nextURL = nextLink(header.Get("Link"))
+			if nextURL == "" {
+				return all, nil
+			}
+		}
+	}
+
+	return all, nil
+}
+
+// pageURL builds the request URL for the given zero-based page
+func (c *Client) pageURL(page, offset int, cursor, nextURL string) (string, error) {
+	if nextURL != "" {
+		return nextURL, nil
+	}
+
+	u, err := url.Parse(c.config.Endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint: %w", err)
+	}
+
+	q := u.Query()
+	for k, v := range c.config.Query {
+		q.Set(k, v)
+	}
+
+	p := c.config.Pagination
+	if p.SizeParam != "" && p.Type != "none" {
+		q.Set(p.SizeParam, strconv.Itoa(p.PageSize))
+	}
+	switch p.Type {
+	case "page":
+		q.Set(p.PageParam, strconv.Itoa(p.StartPage+page))
+	case "offset":
+		q.Set(p.OffsetParam, strconv.Itoa(offset))
+	case "cursor":
+		if cursor != "" {
+			q.Set(p.CursorParam, cursor)
+		}
+	}
+
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// do performs an authenticated request and decodes the JSON body
+func (c *Client) do(reqURL string) (interface{}, http.Header, error) {
+	var reqBody io.Reader
+	if c.config.Method == "POST" && len(c.config.Body) > 0 {
+		reqBody = bytes.NewReader(c.config.Body)
+	}
+
+	req, err := http.NewRequest(c.config.Method, reqURL, reqBody)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range c.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	switch c.config.Auth.Type {
+	case "bearer":
+		req.Header.Set("Authorization", "Bearer "+c.credentials.Token)
+	case "basic":
+		req.SetBasicAuth(c.credentials.Username, c.credentials.Password)
+	case "api_key":
+		req.Header.Set(c.config.Auth.Header, c.credentials.APIKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, nil, fmt.Errorf("API request failed: %d - %s", resp.StatusCode, string(body))
+	}
+
+	// Numbers are kept as json.Number so large IDs aren't rendered in exponent form
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber()
+
+	var body interface{}
+	if err := decoder.Decode(&body); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return body, resp.Header, nil
+}
+
+// extractItems returns the product array at items_path. A single object is treated as one item.
+func (c *Client) extractItems(body interface{}) []interface{} {
+	values := c.itemsPath.Get(body)
+	if len(values) == 1 {
+		if items, ok := values[0].([]interface{}); ok {
+			return items
+		}
+	}
+	return values
+}
+
+// nextLink returns the rel="next" URL of an RFC 8288 Link header
+func nextLink(header string) string {
+	for _, part := range strings.Split(header, ",") {
+		sections := strings.Split(part, ";")
+		if len(sections) < 2 {
+			continue
+		}
+		for _, param := range sections[1:] {
+			param = strings.ReplaceAll(strings.TrimSpace(param), " ", "")
+			if param == `rel="next"` || param == "rel=next" {
+				return strings.Trim(strings.TrimSpace(sections[0]), "<>")
+			}
+		}
+	}
+	return ""
+}
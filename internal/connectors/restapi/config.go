@@ -0,0 +1,266 @@
+package restapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Config is the declarative description of a REST/JSON product source, stored in
+// Connector.Config. Secrets live in Connector.Credentials (see Credentials).
+type Config struct {
+	Endpoint        string            `json:"endpoint"`
+	Method          string            `json:"method"`     // GET (default) or POST
+	Headers         map[string]string `json:"headers"`    // extra request headers
+	Query           map[string]string `json:"query"`      // extra query parameters
+	Body            json.RawMessage   `json:"body"`       // request body for POST sources
+	ItemsPath       string            `json:"items_path"` // path to the product array, "$" when the body is the array
+	DefaultCurrency string            `json:"default_currency"`
+	Auth            AuthConfig        `json:"auth"`
+	Pagination      PaginationConfig  `json:"pagination"`
+	Mapping         FieldMapping      `json:"mapping"`
+}
+
+// AuthConfig selects how requests are authenticated
+type AuthConfig struct {
+	Type   string `json:"type"`   // none, bearer, basic or api_key
+	Header string `json:"header"` // header carrying the key for api_key auth, defaults to X-API-Key
+}
+
+// Credentials are the secrets used by AuthConfig, stored in Connector.Credentials
+type Credentials struct {
+	Token    string `json:"token"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	APIKey   string `json:"api_key"`
+}
+
+// PaginationConfig describes how the source pages through its results
+type PaginationConfig struct {
+	Type        string `json:"type"`         // none, page, offset, cursor or link_header
+	PageParam   string `json:"page_param"`   // page: page number parameter, defaults to "page"
+	StartPage   int    `json:"start_page"`   // page: first page number, defaults to 1
+	SizeParam   string `json:"size_param"`   // page/offset/cursor: page size parameter, e.g. "per_page" or "limit"
+	PageSize    int    `json:"page_size"`    // defaults to 100
+	OffsetParam string `json:"offset_param"` // offset: offset parameter, defaults to "offset"
+	CursorParam string `json:"cursor_param"` // cursor: cursor parameter, defaults to "cursor"
+	CursorPath  string `json:"cursor_path"`  // cursor: path to the next cursor in the response body
+	MaxPages    int    `json:"max_pages"`    // safety limit, defaults to 1000
+}
+
+// FieldMapping maps paths in a source item to canonical product fields. Paths are
+// evaluated relative to the item, e.g. "$.pricing.amount" or "images[*].url".
+type FieldMapping struct {
+	ExternalID     string            `json:"external_id"` // defaults to the SKU
+	SKU            string            `json:"sku"`
+	Title          string            `json:"title"`
+	Description    string            `json:"description"`
+	Brand          string            `json:"brand"`
+	GTIN           string            `json:"gtin"`
+	MPN            string            `json:"mpn"`
+	Category       string            `json:"category"`
+	Price          string            `json:"price"`
+	CompareAtPrice string            `json:"compare_at_price"`
+	Currency       string            `json:"currency"`
+	Availability   string            `json:"availability"`
+	Quantity       string            `json:"quantity"` // used for availability when no availability path is set
+	Images         string            `json:"images"`
+	Weight         string            `json:"weight"`
+	TaxClass       string            `json:"tax_class"`
+	CustomLabels   string            `json:"custom_labels"`
+	Status         string            `json:"status"`
+	Link           string            `json:"link"`
+	Metadata       map[string]string `json:"metadata"`
+	Variants       string            `json:"variants"` // path to the variant array of an item
+	Variant        VariantMapping    `json:"variant"`
+}
+
+// VariantMapping maps paths in a source variant to variant fields
+type VariantMapping struct {
+	ID           string            `json:"id"`
+	SKU          string            `json:"sku"`
+	Price        string            `json:"price"`
+	Availability string            `json:"availability"`
+	Quantity     string            `json:"quantity"`
+	Image        string            `json:"image"`
+	GTIN         string            `json:"gtin"`
+	Attributes   map[string]string `json:"attributes"` // e.g. {"color": "options.colour"}
+}
+
+// ParseConfig decodes and validates a connector configuration
+func ParseConfig(raw map[string]interface{}) (*Config, error) {
+	var cfg Config
+	if err := decode(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid connector config: %w", err)
+	}
+
+	cfg.applyDefaults()
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// ParseCredentials decodes connector credentials
+func ParseCredentials(raw map[string]interface{}) (*Credentials, error) {
+	var creds Credentials
+	if err := decode(raw, &creds); err != nil {
+		return nil, fmt.Errorf("invalid connector credentials: %w", err)
+	}
+	return &creds, nil
+}
+
+func (c *Config) applyDefaults() {
+	if c.Method == "" {
+		c.Method = "GET"
+	}
+	if c.ItemsPath == "" {
+		c.ItemsPath = "$"
+	}
+	if c.Auth.Type == "" {
+		c.Auth.Type = "none"
+	}
+	if c.Auth.Type == "api_key" && c.Auth.Header == "" {
+		c.Auth.Header = "X-API-Key"
+	}
+
+	p := &c.Pagination
+	if p.Type == "" {
+		p.Type = "none"
+	}
+	if p.PageParam == "" {
+		p.PageParam = "page"
+	}
+	if p.StartPage == 0 {
+		p.StartPage = 1
+	}
+	if p.PageSize == 0 {
+		p.PageSize = 100
+	}
+	if p.OffsetParam == "" {
+		p.OffsetParam = "offset"
+	}
+	if p.CursorParam == "" {
+		p.CursorParam = "cursor"
+	}
+	if p.MaxPages == 0 {
+		p.MaxPages = 1000
+	}
+
+	if c.Mapping.ExternalID == "" {
+		c.Mapping.ExternalID = c.Mapping.SKU
+	}
+}
+
+// Validate checks the configuration is complete and every mapping path compiles
+func (c *Config) Validate() error {
+	if c.Endpoint == "" {
+		return fmt.Errorf("endpoint is required")
+	}
+	if u, err := url.Parse(c.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("endpoint must be an http(s) URL")
+	}
+	if c.Method != "GET" && c.Method != "POST" {
+		return fmt.Errorf("unsupported method %q", c.Method)
+	}
+
+	switch c.Auth.Type {
+	case "none", "bearer", "basic", "api_key":
+	default:
+		return fmt.Errorf("unsupported auth type %q", c.Auth.Type)
+	}
+
+	switch c.Pagination.Type {
+	case "none", "page", "offset", "link_header":
+	case "cursor":
+		if c.Pagination.CursorPath == "" {
+			return fmt.Errorf("cursor pagination requires cursor_path")
+		}
+	default:
+		return fmt.Errorf("unsupported pagination type %q", c.Pagination.Type)
+	}
+
+	if c.Mapping.SKU == "" || c.Mapping.Title == "" || c.Mapping.Price == "" {
+		return fmt.Errorf("mapping for sku, title and price is required")
+	}
+
+	for key, expr := range c.Mapping.Metadata {
+		if strings.TrimSpace(expr) == "" {
+			return fmt.Errorf("mapping for metadata %q has no path", key)
+		}
+	}
+	for key, expr := range c.Mapping.Variant.Attributes {
+		if strings.TrimSpace(expr) == "" {
+			return fmt.Errorf("mapping for variant attribute %q has no path", key)
+		}
+	}
+
+	for name, expr := range c.paths() {
+		if expr == "" {
+			continue
+		}
+		path, err := CompilePath(expr)
+		if err != nil {
+			return fmt.Errorf("invalid path for %s: %w", name, err)
+		}
+		// "$" selects the whole item, which no field mapping means
+		if len(path) == 0 && strings.HasPrefix(name, "mapping.") {
+			return fmt.Errorf("path for %s must select a field, not the whole item", name)
+		}
+	}
+	return nil
+}
+
+// paths returns every path expression in the configuration keyed by its setting name
+func (c *Config) paths() map[string]string {
+	m := c.Mapping
+	paths := map[string]string{
+		"items_path":                   c.ItemsPath,
+		"pagination.cursor_path":       c.Pagination.CursorPath,
+		"mapping.external_id":          m.ExternalID,
+		"mapping.sku":                  m.SKU,
+		"mapping.title":                m.Title,
+		"mapping.description":          m.Description,
+		"mapping.brand":                m.Brand,
+		"mapping.gtin":                 m.GTIN,
+		"mapping.mpn":                  m.MPN,
+		"mapping.category":             m.Category,
+		"mapping.price":                m.Price,
+		"mapping.compare_at_price":     m.CompareAtPrice,
+		"mapping.currency":             m.Currency,
+		"mapping.availability":         m.Availability,
+		"mapping.quantity":             m.Quantity,
+		"mapping.images":               m.Images,
+		"mapping.weight":               m.Weight,
+		"mapping.tax_class":            m.TaxClass,
+		"mapping.custom_labels":        m.CustomLabels,
+		"mapping.status":               m.Status,
+		"mapping.link":                 m.Link,
+		"mapping.variants":             m.Variants,
+		"mapping.variant.id":           m.Variant.ID,
+		"mapping.variant.sku":          m.Variant.SKU,
+		"mapping.variant.price":        m.Variant.Price,
+		"mapping.variant.availability": m.Variant.Availability,
+		"mapping.variant.quantity":     m.Variant.Quantity,
+		"mapping.variant.image":        m.Variant.Image,
+		"mapping.variant.gtin":         m.Variant.GTIN,
+	}
+	for key, expr := range m.Metadata {
+		paths["mapping.metadata."+key] = expr
+	}
+	for key, expr := range m.Variant.Attributes {
+		paths["mapping.variant.attributes."+key] = expr
+	}
+	return paths
+}
+
+// decode converts a JSONB map into a typed struct
+func decode(raw map[string]interface{}, out interface{}) error {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,100 @@
+package restapi
+
+import (
+	"lister/internal/config"
+	"lister/internal/logger"
+	"lister/internal/models"
+)
+
+type RESTConnector struct {
+	config *config.Config
+	logger *logger.Logger
+}
+
+func New(cfg *config.Config, logger *logger.Logger) *RESTConnector {
+	return &RESTConnector{
+		config: cfg,
+		logger: logger,
+	}
+}
+
+// MappingError records a source item that could not be mapped
+type MappingError struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+// Preview is the result of a dry run
+type Preview struct {
+	Products  []*models.Product `json:"products"`
+	Errors    []MappingError    `json:"errors"`
+	RawSample interface{}       `json:"raw_sample,omitempty"` // first source item, to help write mappings
+}
+
+// SyncProducts fetches every page of the source and maps it to canonical products.
+// Items that fail to map are skipped and returned as errors.
+func (rc *RESTConnector) SyncProducts(raw, rawCredentials map[string]interface{}) ([]*models.Product, []MappingError, error) {
+	preview, err := rc.run(raw, rawCredentials, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rc.logger.Debug("REST connector sync completed: %d products, %d mapping errors", len(preview.Products), len(preview.Errors))
+
+	return preview.Products, preview.Errors, nil
+}
+
+// DryRun fetches only as many pages as needed for the first limit items and maps them
+// without saving anything
+func (rc *RESTConnector) DryRun(raw, rawCredentials map[string]interface{}, limit int) (*Preview, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	return rc.run(raw, rawCredentials, limit)
+}
+
+func (rc *RESTConnector) run(raw, rawCredentials map[string]interface{}, limit int) (*Preview, error) {
+	cfg, err := ParseConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	creds, err := ParseCredentials(rawCredentials)
+	if err != nil {
+		return nil, err
+	}
+
+	rc.logger.Info("Fetching products from REST source: %s", cfg.Endpoint)
+
+	client, err := NewClient(cfg, creds, rc.logger)
+	if err != nil {
+		return nil, err
+	}
+	mapper, err := NewMapper(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := client.FetchItems(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &Preview{
+		Products: make([]*models.Product, 0, len(items)),
+		Errors:   []MappingError{},
+	}
+	if len(items) > 0 {
+		preview.RawSample = items[0]
+	}
+
+	for i, item := range items {
+		product, err := mapper.Map(item)
+		if err != nil {
+			preview.Errors = append(preview.Errors, MappingError{Index: i, Error: err.Error()})
+			continue
+		}
+		preview.Products = append(preview.Products, product)
+	}
+
+	return preview, nil
+}
@@ -0,0 +1,125 @@
+package restapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Path is a compiled JSONPath-style expression. The supported subset covers what field
+// mappings need: "$" (the current item), ".field", "['field']", "[index]" and "[*]" / ".*".
+// The leading "$" is optional, so "price.amount" and "$.price.amount" are equivalent.
+type Path []segment
+
+type segment struct {
+	field    string
+	index    int
+	wildcard bool
+	isIndex  bool
+}
+
+// CompilePath parses a path expression
+func CompilePath(expr string) (Path, error) {
+	expr = strings.TrimSpace(expr)
+	expr = strings.TrimPrefix(expr, "$")
+
+	var path Path
+	for i := 0; i < len(expr); {
+		switch expr[i] {
+		case '.':
+			i++
+			start := i
+			for i < len(expr) && expr[i] != '.' && expr[i] != '[' {
+				i++
+			}
+			name := expr[start:i]
+			if name == "" {
+				return nil, fmt.Errorf("empty field name at position %d in %q", start, expr)
+			}
+			if name == "*" {
+				path = append(path, segment{wildcard: true})
+			} else {
+				path = append(path, segment{field: name})
+			}
+
+		case '[':
+			end := strings.IndexByte(expr[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated [ in %q", expr)
+			}
+			inner := strings.TrimSpace(expr[i+1 : i+end])
+			i += end + 1
+
+			switch {
+			case inner == "*":
+				path = append(path, segment{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				path = append(path, segment{field: inner[1 : len(inner)-1]})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid index %q in %q", inner, expr)
+				}
+				path = append(path, segment{index: index, isIndex: true})
+			}
+
+		default:
+			// A bare leading field name, e.g. "sku" or "price.amount"
+			if i != 0 {
+				return nil, fmt.Errorf("unexpected %q at position %d in %q", expr[i], i, expr)
+			}
+			expr = "." + expr
+		}
+	}
+
+	return path, nil
+}
+
+// Get returns every value the path matches. Paths without wildcards match at most one value.
+func (p Path) Get(data interface{}) []interface{} {
+	current := []interface{}{data}
+	for _, seg := range p {
+		var next []interface{}
+		for _, value := range current {
+			switch v := value.(type) {
+			case map[string]interface{}:
+				if seg.wildcard {
+					for _, child := range v {
+						next = append(next, child)
+					}
+				} else if !seg.isIndex {
+					if child, ok := v[seg.field]; ok && child != nil {
+						next = append(next, child)
+					}
+				}
+			case []interface{}:
+				switch {
+				case seg.wildcard:
+					next = append(next, v...)
+				case seg.isIndex:
+					index := seg.index
+					if index < 0 {
+						index += len(v)
+					}
+					if index >= 0 && index < len(v) {
+						next = append(next, v[index])
+					}
+				}
+			}
+		}
+		current = next
+		if len(current) == 0 {
+			return nil
+		}
+	}
+	return current
+}
+
+// First returns the first value the path matches
+func (p Path) First(data interface{}) (interface{}, bool) {
+	values := p.Get(data)
+	if len(values) == 0 {
+		return nil, false
+	}
+	return values[0], true
+}
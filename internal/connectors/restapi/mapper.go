@@ -0,0 +1,324 @@
+package restapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"lister/internal/models"
+)
+
+// Mapper converts raw source items to canonical products using a FieldMapping
+type Mapper struct {
+	mapping         FieldMapping
+	defaultCurrency string
+	paths           map[string]Path
+}
+
+// NewMapper compiles the paths of a configuration's field mapping
+func NewMapper(cfg *Config) (*Mapper, error) {
+	m := &Mapper{
+		mapping:         cfg.Mapping,
+		defaultCurrency: cfg.DefaultCurrency,
+		paths:           make(map[string]Path),
+	}
+	if m.defaultCurrency == "" {
+		m.defaultCurrency = "USD"
+	}
+
+	for name, expr := range cfg.paths() {
+		if expr == "" || !strings.HasPrefix(name, "mapping.") {
+			continue
+		}
+		path, err := CompilePath(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path for %s: %w", name, err)
+		}
+		if len(path) == 0 {
+			return nil, fmt.Errorf("path for %s must select a field, not the whole item", name)
+		}
+		m.paths[strings.TrimPrefix(name, "mapping.")] = path
+	}
+	return m, nil
+}
+
+// Map converts a single source item. Items without a SKU, title or valid price are rejected.
+func (m *Mapper) Map(item interface{}) (*models.Product, error) {
+	sku := m.string(item, "sku")
+	if sku == "" {
+		return nil, fmt.Errorf("missing sku")
+	}
+	title := m.string(item, "title")
+	if title == "" {
+		return nil, fmt.Errorf("missing title for %s", sku)
+	}
+	price, ok := m.float(item, "price")
+	if !ok {
+		return nil, fmt.Errorf("missing or invalid price for %s", sku)
+	}
+
+	externalID := m.string(item, "external_id")
+	if externalID == "" {
+		externalID = sku
+	}
+
+	currency := strings.ToUpper(m.string(item, "currency"))
+	if currency == "" {
+		currency = m.defaultCurrency
+	}
+
+	var compareAtPrice *float64
+	if compareAt, ok := m.float(item, "compare_at_price"); ok && compareAt > price {
+		compareAtPrice = &compareAt
+	}
+
+	status := strings.ToUpper(m.string(item, "status"))
+	if status == "" {
+		status = "ACTIVE"
+	}
+
+	metadata := map[string]interface{}{
+		"status": status,
+	}
+	if link := m.string(item, "link"); link != "" {
+		metadata["link"] = link
+	}
+	for key := range m.mapping.Metadata {
+		path, ok := m.paths["metadata."+key]
+		if !ok {
+			continue
+		}
+		if value, ok := path.First(item); ok {
+			metadata[key] = plain(value)
+		}
+	}
+
+	var shipping *models.ShippingInfo
+	if weight, ok := m.float(item, "weight"); ok {
+		shipping = &models.ShippingInfo{Weight: &weight}
+	}
+
+	product := &models.Product{
+		ExternalID:     externalID,
+		SKU:            sku,
+		Title:          title,
+		Description:    m.optional(item, "description"),
+		Brand:          m.optional(item, "brand"),
+		GTIN:           m.optional(item, "gtin"),
+		MPN:            m.optional(item, "mpn"),
+		Category:       m.optional(item, "category"),
+		Price:          price,
+		CompareAtPrice: compareAtPrice,
+		Currency:       currency,
+		Availability:   m.availability(item, "availability", "quantity"),
+		Images:         m.strings(item, "images"),
+		Variants:       m.variants(item),
+		Shipping:       shipping,
+		TaxClass:       m.optional(item, "tax_class"),
+		CustomLabels:   m.strings(item, "custom_labels"),
+		Metadata:       metadata,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	return product, nil
+}
+
+func (m *Mapper) variants(item interface{}) []models.ProductVariant {
+	path, ok := m.paths["variants"]
+	if !ok {
+		return nil
+	}
+
+	var sources []interface{}
+	for _, value := range path.Get(item) {
+		if list, ok := value.([]interface{}); ok {
+			sources = append(sources, list...)
+		} else {
+			sources = append(sources, value)
+		}
+	}
+
+	// Sorted so variant titles are stable between syncs
+	attributeKeys := make([]string, 0, len(m.mapping.Variant.Attributes))
+	for key := range m.mapping.Variant.Attributes {
+		attributeKeys = append(attributeKeys, key)
+	}
+	sort.Strings(attributeKeys)
+
+	variants := make([]models.ProductVariant, 0, len(sources))
+	for i, source := range sources {
+		id := m.string(source, "variant.id")
+		if id == "" {
+			id = strconv.Itoa(i + 1)
+		}
+		price, _ := m.float(source, "variant.price")
+
+		attributes := map[string]interface{}{
+			"availability": m.availability(source, "variant.availability", "variant.quantity"),
+		}
+		if quantity, ok := m.float(source, "variant.quantity"); ok {
+			attributes["inventory_quantity"] = quantity
+		}
+		if image := m.string(source, "variant.image"); image != "" {
+			attributes["image"] = image
+		}
+		if gtin := m.string(source, "variant.gtin"); gtin != "" {
+			attributes["gtin"] = gtin
+		}
+
+		titleParts := make([]string, 0, len(attributeKeys))
+		for _, key := range attributeKeys {
+			if value := m.string(source, "variant.attributes."+key); value != "" {
+				attributes[key] = value
+				titleParts = append(titleParts, value)
+			}
+		}
+		attributes["title"] = strings.Join(titleParts, " / ")
+
+		variants = append(variants, models.ProductVariant{
+			ID:         id,
+			SKU:        m.string(source, "variant.sku"),
+			Price:      price,
+			Attributes: attributes,
+		})
+	}
+	return variants
+}
+
+// availability normalises common stock representations; without an availability
+// value the quantity decides
+func (m *Mapper) availability(item interface{}, field, quantityField string) string {
+	var value interface{}
+	path, ok := m.paths[field]
+	if ok {
+		value, ok = path.First(item)
+	}
+	if !ok {
+		if quantity, ok := m.float(item, quantityField); ok && quantity <= 0 {
+			return string(models.AvailabilityOutOfStock)
+		}
+		return string(models.AvailabilityInStock)
+	}
+
+	if b, ok := value.(bool); ok {
+		if b {
+			return string(models.AvailabilityInStock)
+		}
+		return string(models.AvailabilityOutOfStock)
+	}
+
+	normalized := strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(toString(value)))
+	switch normalized {
+	case "outofstock", "soldout", "unavailable", "false", "0", "no":
+		return string(models.AvailabilityOutOfStock)
+	case "preorder":
+		return string(models.AvailabilityPreorder)
+	case "backorder", "onbackorder", "backordered":
+		return string(models.AvailabilityBackorder)
+	default:
+		return string(models.AvailabilityInStock)
+	}
+}
+
+func (m *Mapper) string(item interface{}, field string) string {
+	path, ok := m.paths[field]
+	if !ok {
+		return ""
+	}
+	value, _ := path.First(item)
+	return strings.TrimSpace(toString(value))
+}
+
+func (m *Mapper) optional(item interface{}, field string) *string {
+	if value := m.string(item, field); value != "" {
+		return &value
+	}
+	return nil
+}
+
+func (m *Mapper) float(item interface{}, field string) (float64, bool) {
+	path, ok := m.paths[field]
+	if !ok {
+		return 0, false
+	}
+	value, ok := path.First(item)
+	if !ok {
+		return 0, false
+	}
+
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case float64:
+		return v, true
+	case string:
+		// Tolerate currency symbols and thousands separators, e.g. "$1,299.00"
+		cleaned := strings.Map(func(r rune) rune {
+			if (r >= '0' && r <= '9') || r == '.' || r == '-' {
+				return r
+			}
+			return -1
+		}, v)
+		f, err := strconv.ParseFloat(cleaned, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// strings collects every matched value, flattening arrays
+func (m *Mapper) strings(item interface{}, field string) []string {
+	path, ok := m.paths[field]
+	if !ok {
+		return []string{}
+	}
+
+	values := []string{}
+	for _, value := range path.Get(item) {
+		if list, ok := value.([]interface{}); ok {
+			for _, v := range list {
+				if s := toString(v); s != "" {
+					values = append(values, s)
+				}
+			}
+		} else if s := toString(value); s != "" {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// plain converts json.Number values so metadata serialises as regular JSON numbers
+func plain(value interface{}) interface{} {
+	if n, ok := value.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return i
+		}
+		if f, err := n.Float64(); err == nil {
+			return f
+		}
+	}
+	return value
+}
@@ -0,0 +1,144 @@
+package woocommerce
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"lister/internal/logger"
+)
+
+// maxPerPage is the largest page size the WooCommerce REST API accepts
+const maxPerPage = 100
+
+type Client struct {
+	storeURL       string
+	consumerKey    string
+	consumerSecret string
+	httpClient     *http.Client
+	logger         *logger.Logger
+}
+
+func NewClient(storeURL, consumerKey, consumerSecret string, logger *logger.Logger) *Client {
+	return &Client{
+		storeURL:       strings.TrimSuffix(storeURL, "/"),
+		consumerKey:    consumerKey,
+		consumerSecret: consumerSecret,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
+// GetProducts fetches a single page of products and returns the total page count
+// reported by the X-WP-TotalPages header
+func (c *Client) GetProducts(page, perPage int) ([]Product, int, error) {
+	var products []Product
+	totalPages, err := c.get("/products", page, perPage, &products)
+	if err != nil {
+		return nil, 0, err
+	}
+	return products, totalPages, nil
+}
+
+// GetAllProducts walks every page of the products endpoint
+func (c *Client) GetAllProducts() ([]Product, error) {
+	var all []Product
+	for page := 1; ; page++ {
+		products, totalPages, err := c.GetProducts(page, maxPerPage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch products page %d: %w", page, err)
+		}
+
+		c.logger.Debug("Fetched WooCommerce products page %d/%d (%d products)", page, totalPages, len(products))
+		all = append(all, products...)
+
+		if page >= totalPages || len(products) == 0 {
+			break
+		}
+	}
+	return all, nil
+}
+
+// GetProduct fetches a single product by ID
+func (c *Client) GetProduct(productID int64) (*Product, error) {
+	var product Product
+	if _, err := c.get(fmt.Sprintf("/products/%d", productID), 0, 0, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// GetVariations fetches every variation of a variable product
+func (c *Client) GetVariations(productID int64) ([]Variation, error) {
+	var all []Variation
+	for page := 1; ; page++ {
+		var variations []Variation
+		totalPages, err := c.get(fmt.Sprintf("/products/%d/variations", productID), page, maxPerPage, &variations)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch variations for product %d: %w", productID, err)
+		}
+
+		all = append(all, variations...)
+
+		if page >= totalPages || len(variations) == 0 {
+			break
+		}
+	}
+	return all, nil
+}
+
+// Ping verifies the credentials by requesting a single product
+func (c *Client) Ping() error {
+	var products []Product
+	_, err := c.get("/products", 1, 1, &products)
+	return err
+}
+
+// get performs an authenticated GET against the wc/v3 API and decodes the body into out.
+// When page is non-zero the request is paginated and the X-WP-TotalPages header is returned.
+func (c *Client) get(path string, page, perPage int, out interface{}) (int, error) {
+	url := fmt.Sprintf("%s/wp-json/wc/v3%s", c.storeURL, path)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.SetBasicAuth(c.consumerKey, c.consumerSecret)
+	req.Header.Set("Content-Type", "application/json")
+
+	if page > 0 {
+		q := req.URL.Query()
+		q.Set("page", strconv.Itoa(page))
+		q.Set("per_page", strconv.Itoa(perPage))
+		req.URL.RawQuery = q.Encode()
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("API request failed: %d - %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	totalPages, _ := strconv.Atoi(resp.Header.Get("X-WP-TotalPages"))
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	return totalPages, nil
+}
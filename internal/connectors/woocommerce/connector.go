@@ -1,10 +1,23 @@
 package woocommerce
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
 	"lister/internal/config"
 	"lister/internal/logger"
+	"lister/internal/models"
 )
 
+// ErrUnsupportedTopic is returned for webhook topics that don't affect products
+var ErrUnsupportedTopic = errors.New("unsupported webhook topic")
+
 type WooCommerceConnector struct {
 	config *config.Config
 	logger *logger.Logger
@@ -17,31 +30,127 @@ func New(cfg *config.Config, logger *logger.Logger) *WooCommerceConnector {
 	}
 }
 
-func (wc *WooCommerceConnector) SyncProducts(storeURL, consumerKey, consumerSecret string) error {
-	// TODO: Implement WooCommerce product sync
-	// This would:
-	// - Fetch products from WooCommerce REST API
-	// - Transform to canonical format
-	// - Publish events to Kafka
-	// - Handle pagination and rate limiting
-
+// SyncProducts fetches every page of products from the store, resolves the variations
+// of variable products and returns them in canonical format
+func (wc *WooCommerceConnector) SyncProducts(storeURL, consumerKey, consumerSecret string) ([]*models.Product, error) {
 	wc.logger.Info("Syncing products from WooCommerce store: %s", storeURL)
 
-	// For now, just log the sync request
-	wc.logger.Debug("WooCommerce sync completed")
+	client := NewClient(storeURL, consumerKey, consumerSecret, wc.logger)
+	transformer := NewTransformer("")
+
+	products, err := client.GetAllProducts()
+	if err != nil {
+		return nil, err
+	}
+
+	canonical := make([]*models.Product, 0, len(products))
+	for i := range products {
+		product := &products[i]
+
+		variations, err := wc.fetchVariations(client, product)
+		if err != nil {
+			// Keep the parent product so a single broken variation endpoint doesn't drop it
+			wc.logger.Error("Failed to fetch variations for WooCommerce product %d: %v", product.ID, err)
+		}
+
+		canonical = append(canonical, transformer.TransformProduct(product, variations))
+	}
+
+	wc.logger.Debug("WooCommerce sync completed: %d products", len(canonical))
+
+	return canonical, nil
+}
+
+// WebhookEvent describes a product change received from a WooCommerce webhook
+type WebhookEvent struct {
+	Topic      string
+	Action     string // "created", "updated", "deleted" or "ping"
+	ExternalID string
+	Product    *models.Product // nil for deletes and pings
+}
+
+// HandleWebhook parses a product.created, product.updated or product.deleted payload.
+// The client is used to resolve the variations of variable products and may be nil,
+// in which case the product is returned without variants.
+func (wc *WooCommerceConnector) HandleWebhook(topic string, payload []byte, client *Client) (*WebhookEvent, error) {
+	if IsPing(topic, payload) {
+		return &WebhookEvent{Action: "ping"}, nil
+	}
+
+	resource, action, _ := strings.Cut(topic, ".")
+	if resource != "product" {
+		return nil, ErrUnsupportedTopic
+	}
+
+	wc.logger.Debug("Received WooCommerce webhook: %s", topic)
+
+	switch action {
+	case "deleted":
+		var deleted struct {
+			ID int64 `json:"id"`
+		}
+		if err := json.Unmarshal(payload, &deleted); err != nil {
+			return nil, fmt.Errorf("failed to parse webhook payload: %w", err)
+		}
+		return &WebhookEvent{
+			Topic:      topic,
+			Action:     action,
+			ExternalID: strconv.FormatInt(deleted.ID, 10),
+		}, nil
+
+	case "created", "updated", "restored":
+		var product Product
+		if err := json.Unmarshal(payload, &product); err != nil {
+			return nil, fmt.Errorf("failed to parse webhook payload: %w", err)
+		}
+
+		var variations []Variation
+		if client != nil {
+			var err error
+			if variations, err = wc.fetchVariations(client, &product); err != nil {
+				return nil, err
+			}
+		}
+
+		canonical := NewTransformer("").TransformProduct(&product, variations)
+		return &WebhookEvent{
+			Topic:      topic,
+			Action:     action,
+			ExternalID: canonical.ExternalID,
+			Product:    canonical,
+		}, nil
+	}
+
+	return nil, ErrUnsupportedTopic
+}
 
-	return nil
+// IsPing reports whether a delivery is the ping WooCommerce sends to a new webhook's
+// delivery URL: a form encoded body with no topic, source or signature headers
+func IsPing(topic string, payload []byte) bool {
+	return topic == "" && strings.HasPrefix(string(payload), "webhook_id=")
 }
 
-func (wc *WooCommerceConnector) HandleWebhook(payload []byte) error {
-	// TODO: Implement WooCommerce webhook handling
-	// This would:
-	// - Parse webhook payload
-	// - Determine event type (product created/updated/deleted)
-	// - Transform to canonical format
-	// - Publish event to Kafka
+// VerifyWebhook checks the X-WC-Webhook-Signature header, a base64 encoded
+// HMAC-SHA256 of the raw request body keyed with the webhook secret
+func VerifyWebhook(payload []byte, signature, secret string) bool {
+	if signature == "" || secret == "" {
+		return false
+	}
+
+	expected, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
 
-	wc.logger.Debug("Received WooCommerce webhook")
+	return hmac.Equal(expected, mac.Sum(nil))
+}
 
-	return nil
+func (wc *WooCommerceConnector) fetchVariations(client *Client, product *Product) ([]Variation, error) {
+	if product.Type != "variable" || len(product.Variations) == 0 {
+		return nil, nil
+	}
+	return client.GetVariations(product.ID)
 }
@@ -0,0 +1,97 @@
+package woocommerce
+
+// Product represents a WooCommerce product from the wc/v3 REST API
+type Product struct {
+	ID               int64       `json:"id"`
+	Name             string      `json:"name"`
+	Slug             string      `json:"slug"`
+	Permalink        string      `json:"permalink"`
+	Type             string      `json:"type"`
+	Status           string      `json:"status"`
+	Description      string      `json:"description"`
+	ShortDescription string      `json:"short_description"`
+	SKU              string      `json:"sku"`
+	Price            string      `json:"price"`
+	RegularPrice     string      `json:"regular_price"`
+	SalePrice        string      `json:"sale_price"`
+	DateOnSaleFrom   *string     `json:"date_on_sale_from_gmt"`
+	DateOnSaleTo     *string     `json:"date_on_sale_to_gmt"`
+	ManageStock      bool        `json:"manage_stock"`
+	StockQuantity    *int        `json:"stock_quantity"`
+	StockStatus      string      `json:"stock_status"`
+	Weight           string      `json:"weight"`
+	Dimensions       Dimensions  `json:"dimensions"`
+	TaxClass         string      `json:"tax_class"`
+	Categories       []Term      `json:"categories"`
+	Tags             []Term      `json:"tags"`
+	Images           []Image     `json:"images"`
+	Attributes       []Attribute `json:"attributes"`
+	Variations       []int64     `json:"variations"`
+	MetaData         []MetaData  `json:"meta_data"`
+	DateCreatedGMT   string      `json:"date_created_gmt"`
+	DateModifiedGMT  string      `json:"date_modified_gmt"`
+}
+
+// Variation represents a single variation of a variable product
+type Variation struct {
+	ID              int64                `json:"id"`
+	SKU             string               `json:"sku"`
+	Status          string               `json:"status"`
+	Price           string               `json:"price"`
+	RegularPrice    string               `json:"regular_price"`
+	SalePrice       string               `json:"sale_price"`
+	ManageStock     interface{}          `json:"manage_stock"` // bool, or "parent" when inherited
+	StockQuantity   *int                 `json:"stock_quantity"`
+	StockStatus     string               `json:"stock_status"`
+	Weight          string               `json:"weight"`
+	Dimensions      Dimensions           `json:"dimensions"`
+	Image           *Image               `json:"image"`
+	Attributes      []VariationAttribute `json:"attributes"`
+	MetaData        []MetaData           `json:"meta_data"`
+	DateModifiedGMT string               `json:"date_modified_gmt"`
+}
+
+// Dimensions are returned as strings in the store's configured unit
+type Dimensions struct {
+	Length string `json:"length"`
+	Width  string `json:"width"`
+	Height string `json:"height"`
+}
+
+// Term is a category or tag reference
+type Term struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// Image represents a product or variation image
+type Image struct {
+	ID  int64  `json:"id"`
+	Src string `json:"src"`
+	Alt string `json:"alt"`
+}
+
+// Attribute is a product level attribute and its possible options
+type Attribute struct {
+	ID        int64    `json:"id"`
+	Name      string   `json:"name"`
+	Position  int      `json:"position"`
+	Visible   bool     `json:"visible"`
+	Variation bool     `json:"variation"`
+	Options   []string `json:"options"`
+}
+
+// VariationAttribute is the option a variation selects for an attribute
+type VariationAttribute struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Option string `json:"option"`
+}
+
+// MetaData is a custom field attached to a product or variation
+type MetaData struct {
+	ID    int64       `json:"id"`
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
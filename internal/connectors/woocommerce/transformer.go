@@ -0,0 +1,253 @@
+package woocommerce
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"lister/internal/models"
+)
+
+type Transformer struct {
+	currency string
+}
+
+func NewTransformer(currency string) *Transformer {
+	if currency == "" {
+		currency = "USD"
+	}
+	return &Transformer{currency: currency}
+}
+
+// TransformProduct converts a WooCommerce product and its variations to our canonical format.
+// Variations are only expected for products of type "variable".
+func (t *Transformer) TransformProduct(product *Product, variations []Variation) *models.Product {
+	price := parsePrice(product.Price)
+	var compareAtPrice *float64
+	if regular := parsePrice(product.RegularPrice); product.SalePrice != "" && regular > price {
+		compareAtPrice = &regular
+	}
+
+	// Variable products carry their price range on the variations, use the cheapest one
+	if len(variations) > 0 && price == 0 {
+		for _, v := range variations {
+			if vp := parsePrice(v.Price); vp > 0 && (price == 0 || vp < price) {
+				price = vp
+			}
+		}
+	}
+
+	images := make([]string, 0, len(product.Images))
+	for _, img := range product.Images {
+		images = append(images, img.Src)
+	}
+
+	var category *string
+	if len(product.Categories) > 0 {
+		category = &product.Categories[0].Name
+	}
+
+	customLabels := make([]string, 0, len(product.Tags))
+	for _, tag := range product.Tags {
+		customLabels = append(customLabels, tag.Name)
+	}
+
+	categories := make([]string, 0, len(product.Categories))
+	for _, cat := range product.Categories {
+		categories = append(categories, cat.Name)
+	}
+
+	sku := product.SKU
+	if sku == "" {
+		sku = fmt.Sprintf("woocommerce-%d", product.ID)
+	}
+
+	metadata := map[string]interface{}{
+		"woocommerce_id":    product.ID,
+		"handle":            product.Slug,
+		"link":              product.Permalink,
+		"product_type":      product.Type,
+		"status":            productStatus(product.Status),
+		"short_description": product.ShortDescription,
+		"stock_status":      product.StockStatus,
+		"tags":              customLabels,
+		"categories":        categories,
+		"created_at":        product.DateCreatedGMT,
+		"updated_at":        product.DateModifiedGMT,
+	}
+	if product.StockQuantity != nil {
+		metadata["stock_quantity"] = *product.StockQuantity
+	}
+	if product.DateOnSaleFrom != nil {
+		metadata["sale_from"] = *product.DateOnSaleFrom
+	}
+	if product.DateOnSaleTo != nil {
+		metadata["sale_to"] = *product.DateOnSaleTo
+	}
+	for _, meta := range product.MetaData {
+		// Common GTIN/brand plugins store their values as meta data
+		if s, ok := meta.Value.(string); ok && s != "" && !strings.HasPrefix(meta.Key, "_") {
+			metadata["meta_"+meta.Key] = s
+		}
+	}
+
+	var taxClass *string
+	if product.TaxClass != "" {
+		taxClass = &product.TaxClass
+	}
+
+	canonical := &models.Product{
+		ExternalID:     strconv.FormatInt(product.ID, 10),
+		SKU:            sku,
+		Title:          product.Name,
+		Description:    &product.Description,
+		Brand:          metaString(product.MetaData, "_brand", "brand"),
+		GTIN:           metaString(product.MetaData, "_gtin", "gtin", "_wpm_gtin_code", "ean"),
+		Category:       category,
+		Price:          price,
+		CompareAtPrice: compareAtPrice,
+		Currency:       t.currency,
+		Availability:   availability(product.StockStatus, product.StockQuantity),
+		Images:         images,
+		Variants:       t.TransformVariations(variations),
+		Shipping:       shipping(product.Weight, product.Dimensions),
+		TaxClass:       taxClass,
+		CustomLabels:   customLabels,
+		Metadata:       metadata,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	// A variable product is in stock when any of its variations is
+	if len(variations) > 0 {
+		canonical.Availability = string(models.AvailabilityOutOfStock)
+		for _, v := range canonical.Variants {
+			if v.Attributes["availability"] == string(models.AvailabilityInStock) {
+				canonical.Availability = string(models.AvailabilityInStock)
+				break
+			}
+		}
+	}
+
+	return canonical
+}
+
+// TransformVariations converts WooCommerce variations to our variant format.
+// Variation options are stored under their lower-cased attribute name (e.g. "color", "size").
+func (t *Transformer) TransformVariations(variations []Variation) []models.ProductVariant {
+	variants := make([]models.ProductVariant, 0, len(variations))
+
+	for _, v := range variations {
+		attributes := map[string]interface{}{
+			"regular_price": v.RegularPrice,
+			"sale_price":    v.SalePrice,
+			"stock_status":  v.StockStatus,
+			"availability":  availability(v.StockStatus, v.StockQuantity),
+			"weight":        v.Weight,
+		}
+		if v.StockQuantity != nil {
+			attributes["inventory_quantity"] = *v.StockQuantity
+		}
+		if v.Image != nil && v.Image.Src != "" {
+			attributes["image"] = v.Image.Src
+		}
+		if gtin := metaString(v.MetaData, "_gtin", "gtin", "_wpm_gtin_code", "ean"); gtin != nil {
+			attributes["gtin"] = *gtin
+		}
+
+		titleParts := make([]string, 0, len(v.Attributes))
+		for _, attr := range v.Attributes {
+			attributes[attributeKey(attr.Name)] = attr.Option
+			titleParts = append(titleParts, attr.Option)
+		}
+		attributes["title"] = strings.Join(titleParts, " / ")
+
+		variants = append(variants, models.ProductVariant{
+			ID:         strconv.FormatInt(v.ID, 10),
+			SKU:        v.SKU,
+			Price:      parsePrice(v.Price),
+			Attributes: attributes,
+		})
+	}
+
+	return variants
+}
+
+// productStatus maps WordPress post statuses to the product statuses used by feeds
+func productStatus(status string) string {
+	switch status {
+	case "publish":
+		return "ACTIVE"
+	case "draft", "pending", "private":
+		return "DRAFT"
+	case "trash":
+		return "ARCHIVED"
+	default:
+		return strings.ToUpper(status)
+	}
+}
+
+func availability(stockStatus string, quantity *int) string {
+	switch stockStatus {
+	case "outofstock":
+		return string(models.AvailabilityOutOfStock)
+	case "onbackorder":
+		return string(models.AvailabilityBackorder)
+	}
+	if quantity != nil && *quantity <= 0 {
+		return string(models.AvailabilityOutOfStock)
+	}
+	return string(models.AvailabilityInStock)
+}
+
+func shipping(weight string, dimensions Dimensions) *models.ShippingInfo {
+	info := &models.ShippingInfo{}
+	if w, err := strconv.ParseFloat(weight, 64); err == nil {
+		unit := "kg" // WooCommerce default, like the dimension unit below
+		info.Weight = &w
+		info.WeightUnit = &unit
+	}
+
+	length, _ := strconv.ParseFloat(dimensions.Length, 64)
+	width, _ := strconv.ParseFloat(dimensions.Width, 64)
+	height, _ := strconv.ParseFloat(dimensions.Height, 64)
+	if length > 0 || width > 0 || height > 0 {
+		info.Dimensions = &models.Dimensions{
+			Length: length,
+			Width:  width,
+			Height: height,
+			Unit:   "cm", // WooCommerce default, the store unit is not exposed on the product
+		}
+	}
+
+	if info.Weight == nil && info.Dimensions == nil {
+		return nil
+	}
+	return info
+}
+
+// attributeKey normalises attribute names such as "pa_color" or "Size" to "color"/"size"
+func attributeKey(name string) string {
+	key := strings.ToLower(strings.TrimSpace(name))
+	key = strings.TrimPrefix(key, "pa_")
+	return strings.ReplaceAll(key, " ", "_")
+}
+
+func metaString(meta []MetaData, keys ...string) *string {
+	for _, key := range keys {
+		for _, m := range meta {
+			if strings.EqualFold(m.Key, key) {
+				if s, ok := m.Value.(string); ok && s != "" {
+					return &s
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func parsePrice(value string) float64 {
+	price, _ := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	return price
+}
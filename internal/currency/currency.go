@@ -0,0 +1,143 @@
+// Package currency converts feed prices between currencies from a table of exchange
+// rates. Rates come from a pluggable Source, such as the European Central Bank's daily
+// reference rates, and manual overrides replace a source's rate for the same pair.
+package currency
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SourceManual is the source of manual override rates
+const SourceManual = "manual"
+
+// Rate is the price of one unit of Base in Quote: EUR→USD 1.08 means 1 EUR = 1.08 USD
+type Rate struct {
+	Base      string    `json:"base"`
+	Quote     string    `json:"quote"`
+	Rate      float64   `json:"rate"`
+	Source    string    `json:"source"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Override reports whether the rate is a manual override
+func (r Rate) Override() bool {
+	return r.Source == SourceManual
+}
+
+// Table looks up conversion rates between any two currencies it can connect, directly,
+// through the inverse of a rate, or through a third currency both have a rate with
+type Table struct {
+	rates map[string]map[string]float64
+}
+
+// NewTable builds a table from rates. Manual overrides win over source rates for the
+// same pair, and invalid rates are ignored.
+func NewTable(rates []Rate) *Table {
+	t := &Table{rates: map[string]map[string]float64{}}
+	for _, override := range []bool{false, true} {
+		for _, r := range rates {
+			if r.Override() != override {
+				continue
+			}
+			base, quote := Normalize(r.Base), Normalize(r.Quote)
+			if r.Rate <= 0 || !Valid(base) || !Valid(quote) || base == quote {
+				continue
+			}
+			t.set(base, quote, r.Rate)
+			if override {
+				// An override also replaces the inverse a source may have for the pair
+				t.set(quote, base, 1/r.Rate)
+			}
+		}
+	}
+	return t
+}
+
+func (t *Table) set(base, quote string, rate float64) {
+	if t.rates[base] == nil {
+		t.rates[base] = map[string]float64{}
+	}
+	t.rates[base][quote] = rate
+}
+
+// Rate returns how many units of to one unit of from is worth
+func (t *Table) Rate(from, to string) (float64, bool) {
+	from, to = Normalize(from), Normalize(to)
+	if from == to {
+		return 1, true
+	}
+	if rate, ok := t.direct(from, to); ok {
+		return rate, true
+	}
+	for _, pivot := range t.Currencies() {
+		if pivot == from || pivot == to {
+			continue
+		}
+		first, ok := t.direct(from, pivot)
+		if !ok {
+			continue
+		}
+		if second, ok := t.direct(pivot, to); ok {
+			return first * second, true
+		}
+	}
+	return 0, false
+}
+
+// Currencies returns the currencies the table has rates for, sorted
+func (t *Table) Currencies() []string {
+	seen := map[string]bool{}
+	for base, quotes := range t.rates {
+		seen[base] = true
+		for quote := range quotes {
+			seen[quote] = true
+		}
+	}
+	codes := make([]string, 0, len(seen))
+	for code := range seen {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+func (t *Table) direct(from, to string) (float64, bool) {
+	if rate, ok := t.rates[from][to]; ok {
+		return rate, true
+	}
+	if rate, ok := t.rates[to][from]; ok {
+		return 1 / rate, true
+	}
+	return 0, false
+}
+
+// Convert converts an amount, rounded to cents
+func (t *Table) Convert(amount float64, from, to string) (float64, error) {
+	rate, ok := t.Rate(from, to)
+	if !ok {
+		return 0, fmt.Errorf("no exchange rate from %s to %s", Normalize(from), Normalize(to))
+	}
+	return math.Round(amount*rate*100) / 100, nil
+}
+
+// Normalize returns a currency code in upper case without surrounding space
+func Normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Valid reports whether code looks like an ISO 4217 code
+func Valid(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,134 @@
+package currency
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Source fetches current exchange rates
+type Source interface {
+	// Name identifies the source in stored rates
+	Name() string
+	// Fetch returns the source's current rates
+	Fetch(ctx context.Context) ([]Rate, error)
+}
+
+var (
+	sourcesMu sync.RWMutex
+	sources   = map[string]func() Source{
+		"ecb": func() Source { return NewECB(nil) },
+	}
+)
+
+// Register makes a source available to NewSource under name, replacing any source
+// registered with that name
+func Register(name string, factory func() Source) {
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+	sources[strings.ToLower(name)] = factory
+}
+
+// NewSource returns the registered source called name. "none" and "" return nil:
+// rates are then only the manual overrides.
+func NewSource(name string) (Source, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == "none" {
+		return nil, nil
+	}
+	sourcesMu.RLock()
+	factory, ok := sources[name]
+	sourcesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown exchange rate source %q; use one of %s or none", name, strings.Join(SourceNames(), ", "))
+	}
+	return factory(), nil
+}
+
+// SourceNames returns the registered sources, sorted
+func SourceNames() []string {
+	sourcesMu.RLock()
+	defer sourcesMu.RUnlock()
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ECBDailyURL publishes the European Central Bank's euro reference rates each working day
+const ECBDailyURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// ECB is the European Central Bank's daily euro reference rates: EUR to about 30
+// currencies, which the Table crosses for other pairs
+type ECB struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewECB returns the ECB source, fetching with client or a client with a 15 second timeout
+func NewECB(client *http.Client) *ECB {
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	return &ECB{URL: ECBDailyURL, Client: client}
+}
+
+// Name implements Source
+func (s *ECB) Name() string {
+	return "ecb"
+}
+
+// Fetch implements Source
+func (s *ECB) Fetch(ctx context.Context) ([]Rate, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ECB rates: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch ECB rates: status %d", resp.StatusCode)
+	}
+
+	var envelope struct {
+		Days []struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string `xml:"currency,attr"`
+				Rate     string `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube>Cube"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("invalid ECB rates: %w", err)
+	}
+	if len(envelope.Days) == 0 {
+		return nil, fmt.Errorf("ECB rates response has no rates")
+	}
+
+	day := envelope.Days[0]
+	updated, err := time.Parse("2006-01-02", day.Time)
+	if err != nil {
+		updated = time.Now().UTC()
+	}
+	rates := make([]Rate, 0, len(day.Rates))
+	for _, r := range day.Rates {
+		value, err := strconv.ParseFloat(r.Rate, 64)
+		if err != nil || value <= 0 {
+			continue
+		}
+		rates = append(rates, Rate{Base: "EUR", Quote: Normalize(r.Currency), Rate: value, Source: s.Name(), UpdatedAt: updated})
+	}
+	return rates, nil
+}
@@ -181,6 +181,15 @@ func New(databaseURL string) (*Database, error) {
 		created_at TIMESTAMPTZ DEFAULT NOW(),
 		updated_at TIMESTAMPTZ DEFAULT NOW()
 	);
+
+	-- Connector products are matched and archived per connector
+	ALTER TABLE products ADD COLUMN IF NOT EXISTS connector_id TEXT;
+	ALTER TABLE products ADD COLUMN IF NOT EXISTS status TEXT DEFAULT 'ACTIVE';
+
+	-- Products belong to an organization: bulk optimization jobs only touch the organization's
+	-- own, and a connector only updates products of its organization
+	ALTER TABLE products ADD COLUMN IF NOT EXISTS organization_id UUID DEFAULT '00000000-0000-0000-0000-000000000000'::uuid;
+	ALTER TABLE connectors ADD COLUMN IF NOT EXISTS organization_id UUID DEFAULT '00000000-0000-0000-0000-000000000000'::uuid;
 	`
 
 	err = db.Exec(createTablesSQL).Error
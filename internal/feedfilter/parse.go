@@ -0,0 +1,374 @@
+// Package feedfilter compiles feed filter expressions to parameterized SQL conditions on
+// the products table:
+//
+//	price > 20 AND (brand IN ("Acme", "Foo") OR tags CONTAINS "sale") AND NOT availability = "OUT_OF_STOCK"
+//
+// Comparisons are field op value with =, !=, <>, >, >=, < and <=; field IN (values);
+// field CONTAINS value; and field IS [NOT] EMPTY. They combine with AND, OR, NOT and
+// parentheses. Keywords are case-insensitive and text comparisons ignore case. Fields are
+// the product columns in Fields, tags and collections from the metadata, and any other
+// metadata key as metadata.key. Values are always passed as query arguments.
+package feedfilter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expr is a parsed filter expression
+type Expr struct {
+	source string
+	root   node
+}
+
+// node is a boolean expression: a comparison or a combination of nodes
+type node interface{}
+
+type andNode struct{ left, right node }
+type orNode struct{ left, right node }
+type notNode struct{ operand node }
+
+// comparison tests a field against values
+type comparison struct {
+	field    field
+	operator string
+	values   []value
+}
+
+// value is a literal: a string, or a number when number is set
+type value struct {
+	text     string
+	number   float64
+	isNumber bool
+}
+
+// Comparison operators, besides those written as symbols
+const (
+	opIn       = "IN"
+	opContains = "CONTAINS"
+	opEmpty    = "EMPTY"
+	opNotEmpty = "NOT EMPTY"
+)
+
+// Parse compiles a filter expression
+func Parse(source string) (*Expr, error) {
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, fmt.Errorf("filter expression is empty")
+	}
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s at position %d", t, t.pos+1)
+	}
+	return &Expr{source: source, root: root}, nil
+}
+
+// String returns the expression's source
+func (e *Expr) String() string {
+	return e.source
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// keyword reports whether t is the keyword word, in any case
+func (t token) keyword(word string) bool {
+	return t.kind == tokenIdent && strings.EqualFold(t.text, word)
+}
+
+func lex(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokenLParen, "(", i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokenRParen, ")", i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{tokenComma, ",", i})
+			i++
+		case r == '"' || r == '\'':
+			start := i
+			var text strings.Builder
+			for i++; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				text.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", start+1)
+			}
+			tokens = append(tokens, token{tokenString, text.String(), start})
+			i++
+		case strings.ContainsRune("=!<>", r):
+			start := i
+			op := string(r)
+			if i+1 < len(runes) && (runes[i+1] == '=' || (r == '<' && runes[i+1] == '>')) {
+				op += string(runes[i+1])
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("unexpected ! at position %d", start+1)
+			}
+			i += len(op)
+			tokens = append(tokens, token{tokenOperator, op, start})
+		case unicode.IsDigit(r) || (r == '-' || r == '.') && i+1 < len(runes) && unicode.IsDigit(runes[i+1]):
+			start := i
+			for i++; i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.'); i++ {
+			}
+			tokens = append(tokens, token{tokenNumber, string(runes[start:i]), start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i++; i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.'); i++ {
+			}
+			tokens = append(tokens, token{tokenIdent, string(runes[start:i]), start})
+		default:
+			return nil, fmt.Errorf("unexpected %q at position %d", r, i+1)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) or() (node, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().keyword("OR") {
+		p.next()
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().keyword("AND") {
+		p.next()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) unary() (node, error) {
+	if p.peek().keyword("NOT") {
+		p.next()
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	}
+	if p.peek().kind == tokenLParen {
+		p.next()
+		inner, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokenRParen {
+			return nil, fmt.Errorf("expected ) at position %d, found %s", t.pos+1, t)
+		}
+		return inner, nil
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (node, error) {
+	t := p.next()
+	if t.kind != tokenIdent || isKeyword(t.text) {
+		return nil, fmt.Errorf("expected a field at position %d, found %s", t.pos+1, t)
+	}
+	f, err := lookupField(t.text)
+	if err != nil {
+		return nil, err
+	}
+
+	c := comparison{field: f}
+	op := p.next()
+	switch {
+	case op.kind == tokenOperator:
+		c.operator = op.text
+		if c.operator == "<>" {
+			c.operator = "!="
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		c.values = []value{v}
+	case op.keyword("CONTAINS"):
+		c.operator = opContains
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		c.values = []value{v}
+	case op.keyword("NOT") && p.peek().keyword("IN"):
+		p.next()
+		c.values, err = p.list()
+		if err != nil {
+			return nil, err
+		}
+		c.operator = opIn
+		return notNode{c}, nil
+	case op.keyword("IN"):
+		c.operator = opIn
+		if c.values, err = p.list(); err != nil {
+			return nil, err
+		}
+	case op.keyword("IS"):
+		c.operator = opEmpty
+		if p.peek().keyword("NOT") {
+			p.next()
+			c.operator = opNotEmpty
+		}
+		if t := p.next(); !t.keyword("EMPTY") {
+			return nil, fmt.Errorf("expected EMPTY at position %d, found %s", t.pos+1, t)
+		}
+	default:
+		return nil, fmt.Errorf("expected an operator after %s at position %d, found %s", t.text, op.pos+1, op)
+	}
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (p *parser) value() (value, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return value{text: t.text}, nil
+	case tokenNumber:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return value{}, fmt.Errorf("invalid number %s at position %d", t.text, t.pos+1)
+		}
+		return value{text: t.text, number: n, isNumber: true}, nil
+	}
+	return value{}, fmt.Errorf("expected a quoted string or number at position %d, found %s", t.pos+1, t)
+}
+
+func (p *parser) list() ([]value, error) {
+	if t := p.next(); t.kind != tokenLParen {
+		return nil, fmt.Errorf("expected ( after IN at position %d, found %s", t.pos+1, t)
+	}
+	var values []value
+	for {
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		t := p.next()
+		if t.kind == tokenRParen {
+			return values, nil
+		}
+		if t.kind != tokenComma {
+			return nil, fmt.Errorf("expected , or ) at position %d, found %s", t.pos+1, t)
+		}
+	}
+}
+
+// check rejects comparisons the field's kind doesn't support
+func (c comparison) check() error {
+	switch c.operator {
+	case ">", ">=", "<", "<=":
+		if c.field.kind == kindList {
+			return fmt.Errorf("%s can't be compared with %s", c.field.name, c.operator)
+		}
+		if !c.values[0].isNumber {
+			return fmt.Errorf("%s %s needs a number", c.field.name, c.operator)
+		}
+	case "=", "!=":
+		if c.field.kind == kindList {
+			return fmt.Errorf("use CONTAINS or IN with %s", c.field.name)
+		}
+	}
+	if c.field.kind == kindNumber && c.operator != opEmpty && c.operator != opNotEmpty {
+		for _, v := range c.values {
+			if !v.isNumber {
+				return fmt.Errorf("%s is compared with numbers, not %q", c.field.name, v.text)
+			}
+		}
+		if c.operator == opContains {
+			return fmt.Errorf("CONTAINS doesn't apply to %s", c.field.name)
+		}
+	}
+	return nil
+}
+
+var keywords = map[string]bool{"AND": true, "OR": true, "NOT": true, "IN": true, "CONTAINS": true, "IS": true, "EMPTY": true}
+
+func isKeyword(s string) bool {
+	return keywords[strings.ToUpper(s)]
+}
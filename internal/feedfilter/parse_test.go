@@ -0,0 +1,101 @@
+package feedfilter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLex(t *testing.T) {
+	tokens, err := lex(`price>=20 AND tags CONTAINS "a \"b\"" OR (sku <> 'x', -1.5)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []token{
+		{tokenIdent, "price", 0},
+		{tokenOperator, ">=", 5},
+		{tokenNumber, "20", 7},
+		{tokenIdent, "AND", 10},
+		{tokenIdent, "tags", 14},
+		{tokenIdent, "CONTAINS", 19},
+		{tokenString, `a "b"`, 28},
+		{tokenIdent, "OR", 38},
+		{tokenLParen, "(", 41},
+		{tokenIdent, "sku", 42},
+		{tokenOperator, "<>", 46},
+		{tokenString, "x", 49},
+		{tokenComma, ",", 52},
+		{tokenNumber, "-1.5", 54},
+		{tokenRParen, ")", 58},
+		{tokenEOF, "", 59},
+	}
+	if !reflect.DeepEqual(tokens, want) {
+		t.Errorf("lex =\n%v\nwant\n%v", tokens, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	valid := []string{
+		`price > 20`,
+		`price >= 20.5 and price <= 100`,
+		`brand IN ("Acme", 'Foo') OR tags CONTAINS "sale"`,
+		`NOT availability = "OUT_OF_STOCK"`,
+		`not not (title contains "shirt")`,
+		`sku NOT IN ("a", "b")`,
+		`description IS EMPTY OR gtin is not empty`,
+		`metadata.color.primary != "red"`,
+		`compare_at_price IN (10, 20)`,
+		`title <> "x"`,
+		`collections IS NOT EMPTY`,
+	}
+	for _, source := range valid {
+		expr, err := Parse(source)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", source, err)
+			continue
+		}
+		if expr.String() != source {
+			t.Errorf("Parse(%q).String() = %q", source, expr.String())
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		err    string
+	}{
+		{``, "filter expression is empty"},
+		{`   `, "filter expression is empty"},
+		{`price >`, "expected a quoted string or number at position 8"},
+		{`price ! 3`, "unexpected ! at position 7"},
+		{`title = "abc`, "unterminated string at position 9"},
+		{`title = "a" ; brand = "b"`, `unexpected ';' at position 13`},
+		{`(price > 1`, "expected ) at position 11"},
+		{`price > 1 brand = "a"`, `unexpected "brand" at position 11`},
+		{`price > 1 AND`, "expected a field at position 14"},
+		{`AND price > 1`, "expected a field at position 1"},
+		{`colour = "red"`, "unknown field colour"},
+		{`metadata..x = "a"`, "invalid field metadata..x"},
+		{`title "a"`, "expected an operator after title"},
+		{`title IN "a"`, "expected ( after IN"},
+		{`title IN ("a" "b")`, "expected , or )"},
+		{`title IS NULL`, "expected EMPTY"},
+		{`price > "cheap"`, "price > needs a number"},
+		{`price = "cheap"`, `price is compared with numbers, not "cheap"`},
+		{`price CONTAINS 1`, "CONTAINS doesn't apply to price"},
+		{`tags = "sale"`, "use CONTAINS or IN with tags"},
+		{`tags > 1`, "tags can't be compared with >"},
+		{`title >= "a"`, "title >= needs a number"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.source)
+		if err == nil {
+			t.Errorf("Parse(%q) succeeded, want error %q", tt.source, tt.err)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("Parse(%q) error = %q, want it to contain %q", tt.source, err, tt.err)
+		}
+	}
+}
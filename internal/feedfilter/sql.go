@@ -0,0 +1,218 @@
+package feedfilter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type fieldKind int
+
+const (
+	kindText fieldKind = iota
+	kindNumber
+	kindList
+)
+
+// field is something an expression can test: a products column, a metadata list or a
+// metadata key
+type field struct {
+	name   string
+	kind   fieldKind
+	column string
+	path   []string
+}
+
+// Fields are the products columns expressions can name. availability reads the product
+// status (ACTIVE, OUT_OF_STOCK, ...) and product_type the store category.
+var Fields = map[string]string{
+	"title":            "title",
+	"description":      "description",
+	"brand":            "brand",
+	"category":         "category",
+	"product_type":     "category",
+	"sku":              "sku",
+	"gtin":             "gtin",
+	"external_id":      "external_id",
+	"currency":         "currency",
+	"status":           "status",
+	"availability":     "status",
+	"connector_id":     "connector_id",
+	"price":            "price",
+	"compare_at_price": "compare_at_price",
+	"sale_price":       "sale_price",
+	"cost":             "cost",
+}
+
+// numericFields are the Fields compared as numbers
+var numericFields = map[string]bool{"price": true, "compare_at_price": true, "sale_price": true, "cost": true}
+
+// ListFields are metadata keys holding lists, stored as JSON arrays or comma-separated
+// text, which CONTAINS and IN test element by element
+var ListFields = []string{"tags", "collections"}
+
+// FieldNames returns the names expressions accept, besides metadata.key
+func FieldNames() []string {
+	names := append([]string{}, ListFields...)
+	for name := range Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func lookupField(name string) (field, error) {
+	lower := strings.ToLower(name)
+	if column, ok := Fields[lower]; ok {
+		kind := kindText
+		if numericFields[lower] {
+			kind = kindNumber
+		}
+		return field{name: lower, kind: kind, column: column}, nil
+	}
+	for _, list := range ListFields {
+		if lower == list {
+			return field{name: lower, kind: kindList, path: []string{lower}}, nil
+		}
+	}
+	if strings.HasPrefix(lower, "metadata.") {
+		path := strings.Split(name[len("metadata."):], ".")
+		for _, key := range path {
+			if key == "" {
+				return field{}, fmt.Errorf("invalid field %s", name)
+			}
+		}
+		return field{name: name, kind: kindText, path: path}, nil
+	}
+	return field{}, fmt.Errorf("unknown field %s; use one of %s or metadata.key", name, strings.Join(FieldNames(), ", "))
+}
+
+// SQL returns the expression as a condition on the products table with its arguments,
+// numbered from $firstArg
+func (e *Expr) SQL(firstArg int) (string, []interface{}) {
+	b := &builder{next: firstArg}
+	return b.node(e.root), b.args
+}
+
+type builder struct {
+	args []interface{}
+	next int
+}
+
+// arg adds a query argument and returns its placeholder
+func (b *builder) arg(v interface{}) string {
+	b.args = append(b.args, v)
+	placeholder := fmt.Sprintf("$%d", b.next)
+	b.next++
+	return placeholder
+}
+
+func (b *builder) node(n node) string {
+	switch n := n.(type) {
+	case andNode:
+		return "(" + b.node(n.left) + " AND " + b.node(n.right) + ")"
+	case orNode:
+		return "(" + b.node(n.left) + " OR " + b.node(n.right) + ")"
+	case notNode:
+		return "NOT " + b.node(n.operand)
+	case comparison:
+		return b.comparison(n)
+	}
+	return "FALSE"
+}
+
+func (b *builder) comparison(c comparison) string {
+	switch c.field.kind {
+	case kindList:
+		return b.list(c)
+	case kindNumber:
+		return b.number(c, c.field.column)
+	}
+
+	text := "COALESCE(" + c.field.column + "::text, '')"
+	if c.field.column == "" {
+		text = "COALESCE(" + b.metadataPath(c.field.path) + ", '')"
+	}
+	switch c.operator {
+	case "=":
+		return fmt.Sprintf("LOWER(%s) = LOWER(%s)", text, b.arg(c.values[0].text))
+	case "!=":
+		return fmt.Sprintf("LOWER(%s) <> LOWER(%s)", text, b.arg(c.values[0].text))
+	case ">", ">=", "<", "<=":
+		// Text is compared as a number only when it holds one
+		return b.number(c, fmt.Sprintf(`(CASE WHEN %s ~ '^\s*-?[0-9]+(\.[0-9]+)?\s*$' THEN %s::numeric END)`, text, text))
+	case opContains:
+		return fmt.Sprintf("%s ILIKE %s", text, b.arg("%"+escapeLike(c.values[0].text)+"%"))
+	case opIn:
+		return fmt.Sprintf("LOWER(%s) IN (%s)", text, b.lowerList(c.values))
+	case opEmpty:
+		return fmt.Sprintf("TRIM(%s) = ''", text)
+	case opNotEmpty:
+		return fmt.Sprintf("TRIM(%s) <> ''", text)
+	}
+	return "FALSE"
+}
+
+func (b *builder) number(c comparison, column string) string {
+	switch c.operator {
+	case opEmpty:
+		return column + " IS NULL"
+	case opNotEmpty:
+		return column + " IS NOT NULL"
+	case opIn:
+		placeholders := make([]string, len(c.values))
+		for i, v := range c.values {
+			placeholders[i] = b.arg(v.number)
+		}
+		return fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholders, ", "))
+	case "!=":
+		return fmt.Sprintf("%s IS DISTINCT FROM %s", column, b.arg(c.values[0].number))
+	}
+	return fmt.Sprintf("%s %s %s", column, c.operator, b.arg(c.values[0].number))
+}
+
+// list tests the elements of a metadata list, which may be a JSON array or comma-separated text
+func (b *builder) list(c comparison) string {
+	key := c.field.path[0]
+	elements := fmt.Sprintf(`jsonb_array_elements_text(CASE WHEN jsonb_typeof(metadata->'%[1]s') = 'array' THEN metadata->'%[1]s' ELSE to_jsonb(string_to_array(COALESCE(metadata->>'%[1]s', ''), ',')) END)`, key)
+	element := "LOWER(TRIM(element))"
+
+	var test string
+	switch c.operator {
+	case opContains:
+		test = fmt.Sprintf("%s = LOWER(%s)", element, b.arg(c.values[0].text))
+	case opIn:
+		test = fmt.Sprintf("%s IN (%s)", element, b.lowerList(c.values))
+	case opEmpty, opNotEmpty:
+		test = "TRIM(element) <> ''"
+	default:
+		return "FALSE"
+	}
+	exists := fmt.Sprintf("EXISTS (SELECT 1 FROM %s AS elements(element) WHERE %s)", elements, test)
+	if c.operator == opEmpty {
+		return "NOT " + exists
+	}
+	return exists
+}
+
+// metadataPath reads a metadata key, descending through nested objects
+func (b *builder) metadataPath(path []string) string {
+	keys := make([]string, len(path))
+	for i, key := range path {
+		keys[i] = b.arg(key) + "::text"
+	}
+	return "(metadata #>> ARRAY[" + strings.Join(keys, ", ") + "])"
+}
+
+func (b *builder) lowerList(values []value) string {
+	placeholders := make([]string, len(values))
+	for i, v := range values {
+		placeholders[i] = "LOWER(" + b.arg(v.text) + ")"
+	}
+	return strings.Join(placeholders, ", ")
+}
+
+// escapeLike escapes LIKE wildcards so CONTAINS matches them literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,79 @@
+package feedfilter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSQL(t *testing.T) {
+	tagElements := `jsonb_array_elements_text(CASE WHEN jsonb_typeof(metadata->'tags') = 'array' THEN metadata->'tags' ELSE to_jsonb(string_to_array(COALESCE(metadata->>'tags', ''), ',')) END)`
+
+	tests := []struct {
+		source   string
+		firstArg int
+		sql      string
+		args     []interface{}
+	}{
+		{`price > 20`, 1, `price > $1`, []interface{}{20.0}},
+		{`cost <= 4.5`, 4, `cost <= $4`, []interface{}{4.5}},
+		{`price != 5`, 1, `price IS DISTINCT FROM $1`, []interface{}{5.0}},
+		{`price IN (10, 20.5)`, 2, `price IN ($2, $3)`, []interface{}{10.0, 20.5}},
+		{`price IS EMPTY`, 1, `price IS NULL`, nil},
+		{`sale_price is not empty`, 1, `sale_price IS NOT NULL`, nil},
+		{`title = "Shoe"`, 3, `LOWER(COALESCE(title::text, '')) = LOWER($3)`, []interface{}{"Shoe"}},
+		{`availability <> "ACTIVE"`, 1, `LOWER(COALESCE(status::text, '')) <> LOWER($1)`, []interface{}{"ACTIVE"}},
+		{`brand IN ("Acme", 'Foo')`, 1, `LOWER(COALESCE(brand::text, '')) IN (LOWER($1), LOWER($2))`, []interface{}{"Acme", "Foo"}},
+		{`sku NOT IN ("a")`, 1, `NOT LOWER(COALESCE(sku::text, '')) IN (LOWER($1))`, []interface{}{"a"}},
+		{`title CONTAINS "50%_off"`, 1, `COALESCE(title::text, '') ILIKE $1`, []interface{}{`%50\%\_off%`}},
+		{`description IS EMPTY`, 1, `TRIM(COALESCE(description::text, '')) = ''`, nil},
+		{
+			`metadata.color.primary = "red"`, 1,
+			`LOWER(COALESCE((metadata #>> ARRAY[$1::text, $2::text]), '')) = LOWER($3)`,
+			[]interface{}{"color", "primary", "red"},
+		},
+		{
+			`metadata.size > 10`, 1,
+			`(CASE WHEN COALESCE((metadata #>> ARRAY[$1::text]), '') ~ '^\s*-?[0-9]+(\.[0-9]+)?\s*$' THEN COALESCE((metadata #>> ARRAY[$1::text]), '')::numeric END) > $2`,
+			[]interface{}{"size", 10.0},
+		},
+		{
+			`tags CONTAINS "Sale"`, 1,
+			`EXISTS (SELECT 1 FROM ` + tagElements + ` AS elements(element) WHERE LOWER(TRIM(element)) = LOWER($1))`,
+			[]interface{}{"Sale"},
+		},
+		{
+			`tags IN ("a", "b")`, 1,
+			`EXISTS (SELECT 1 FROM ` + tagElements + ` AS elements(element) WHERE LOWER(TRIM(element)) IN (LOWER($1), LOWER($2)))`,
+			[]interface{}{"a", "b"},
+		},
+		{
+			`tags IS EMPTY`, 1,
+			`NOT EXISTS (SELECT 1 FROM ` + tagElements + ` AS elements(element) WHERE TRIM(element) <> '')`,
+			nil,
+		},
+		{
+			`price > 1 OR price < 0 AND cost = 2`, 1,
+			`(price > $1 OR (price < $2 AND cost = $3))`,
+			[]interface{}{1.0, 0.0, 2.0},
+		},
+		{
+			`price > 1 AND NOT (brand = "a" OR brand = "b")`, 5,
+			`(price > $5 AND NOT (LOWER(COALESCE(brand::text, '')) = LOWER($6) OR LOWER(COALESCE(brand::text, '')) = LOWER($7)))`,
+			[]interface{}{1.0, "a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		expr, err := Parse(tt.source)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.source, err)
+			continue
+		}
+		sql, args := expr.SQL(tt.firstArg)
+		if sql != tt.sql {
+			t.Errorf("%q SQL =\n%s\nwant\n%s", tt.source, sql, tt.sql)
+		}
+		if !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("%q args = %#v, want %#v", tt.source, args, tt.args)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+// Package feedmap interprets feed templates: a field mapping from feed columns to path
+// expressions evaluated against a canonical product, and transformations applied to the
+// results. Expressions look like
+//
+//	product.images[0]
+//	product.metadata.seo_title || product.title
+//	product.metadata["Care Instructions"] || 'See label'
+//
+// A path starts at the product (the "product." prefix is optional) and descends with
+// .key, ["key"], [index] and [from:] slices. JSON strings, such as a product's metadata,
+// are decoded when a path descends into them. Alternatives separated by || are tried in
+// order and the first non-empty value wins; quoted strings and numbers are literals.
+package feedmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Aliases are shorthand paths accepted for compatibility with older templates
+var Aliases = map[string]string{
+	"main_image":  "images[0]",
+	"product_url": "link",
+}
+
+// Expr is a compiled mapping expression
+type Expr struct {
+	source       string
+	alternatives []term
+}
+
+// term is a literal or a path
+type term struct {
+	literal  *string
+	segments []segment
+}
+
+// segment is one step of a path: a key, an index or a slice from an index
+type segment struct {
+	key   string
+	index int
+	kind  segmentKind
+}
+
+type segmentKind int
+
+const (
+	keySegment segmentKind = iota
+	indexSegment
+	sliceSegment
+)
+
+// Compile parses an expression
+func Compile(source string) (*Expr, error) {
+	e := &Expr{source: source}
+	for _, alternative := range splitAlternatives(source) {
+		alternative = strings.TrimSpace(alternative)
+		if alternative == "" {
+			return nil, fmt.Errorf("%q: empty alternative", source)
+		}
+		t, err := parseTerm(alternative)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", source, err)
+		}
+		e.alternatives = append(e.alternatives, t)
+	}
+	if len(e.alternatives) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+	return e, nil
+}
+
+// String returns the expression's source
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Eval returns the first non-empty alternative's values. Single values come back as one
+// string; lists, such as product.images[1:], as one string per element.
+func (e *Expr) Eval(product map[string]interface{}) []string {
+	for _, t := range e.alternatives {
+		if t.literal != nil {
+			return []string{*t.literal}
+		}
+		if values := flatten(resolve(product, t.segments)); len(values) > 0 {
+			return values
+		}
+	}
+	return nil
+}
+
+// splitAlternatives splits on || outside quotes
+func splitAlternatives(source string) []string {
+	var parts []string
+	var quote rune
+	start := 0
+	for i, r := range source {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '|' && strings.HasPrefix(source[i:], "||"):
+			parts = append(parts, source[start:i])
+			start = i + 2
+		}
+	}
+	return append(parts, source[start:])
+}
+
+func parseTerm(s string) (term, error) {
+	if s[0] == '\'' || s[0] == '"' {
+		if len(s) < 2 || s[len(s)-1] != s[0] {
+			return term{}, fmt.Errorf("unterminated string %s", s)
+		}
+		literal := s[1 : len(s)-1]
+		return term{literal: &literal}, nil
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return term{literal: &s}, nil
+	}
+
+	s = strings.TrimPrefix(s, "product.")
+	if alias, ok := Aliases[s]; ok {
+		s = alias
+	}
+	segments, err := parsePath(s)
+	if err != nil {
+		return term{}, err
+	}
+	return term{segments: segments}, nil
+}
+
+func parsePath(s string) ([]segment, error) {
+	var segments []segment
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '.':
+			i++
+		case '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("missing ] in %s", s)
+			}
+			inner := strings.TrimSpace(s[i+1 : i+end])
+			i += end + 1
+			switch {
+			case len(inner) >= 2 && (inner[0] == '"' || inner[0] == '\'') && inner[len(inner)-1] == inner[0]:
+				segments = append(segments, segment{key: inner[1 : len(inner)-1]})
+			case strings.HasSuffix(inner, ":"):
+				from, err := strconv.Atoi(strings.TrimSuffix(inner, ":"))
+				if err != nil || from < 0 {
+					return nil, fmt.Errorf("invalid slice [%s]", inner)
+				}
+				segments = append(segments, segment{index: from, kind: sliceSegment})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("invalid index [%s]", inner)
+				}
+				segments = append(segments, segment{index: index, kind: indexSegment})
+			}
+		default:
+			end := strings.IndexAny(s[i:], ".[")
+			if end < 0 {
+				end = len(s) - i
+			}
+			key := strings.TrimSpace(s[i : i+end])
+			if key == "" {
+				return nil, fmt.Errorf("empty key in %s", s)
+			}
+			segments = append(segments, segment{key: key})
+			i += end
+		}
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("empty path")
+	}
+	return segments, nil
+}
+
+// resolve walks a path from value, returning nil when a step is missing
+func resolve(value interface{}, segments []segment) interface{} {
+	for _, seg := range segments {
+		value = decode(value)
+		switch seg.kind {
+		case keySegment:
+			switch v := value.(type) {
+			case map[string]interface{}:
+				value = v[seg.key]
+			case map[string]string:
+				value = v[seg.key]
+			default:
+				return nil
+			}
+		case indexSegment, sliceSegment:
+			list := toList(value)
+			if seg.index >= len(list) {
+				return nil
+			}
+			if seg.kind == indexSegment {
+				value = list[seg.index]
+			} else {
+				value = list[seg.index:]
+			}
+		}
+		if value == nil {
+			return nil
+		}
+	}
+	return value
+}
+
+// decode parses JSON objects and arrays stored as strings, and PostgreSQL array literals
+// such as {a,b}
+func decode(value interface{}) interface{} {
+	s, ok := value.(string)
+	if !ok {
+		return value
+	}
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[") {
+		var decoded interface{}
+		if json.Unmarshal([]byte(s), &decoded) == nil {
+			return decoded
+		}
+	}
+	if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+		var list []interface{}
+		for _, item := range strings.Split(s[1:len(s)-1], ",") {
+			if item = strings.Trim(strings.TrimSpace(item), `"`); item != "" {
+				list = append(list, item)
+			}
+		}
+		return list
+	}
+	return value
+}
+
+func toList(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return v
+	case []string:
+		list := make([]interface{}, len(v))
+		for i, s := range v {
+			list[i] = s
+		}
+		return list
+	}
+	return nil
+}
+
+// flatten turns a resolved value into strings, dropping empty ones
+func flatten(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	case []interface{}, []string:
+		var values []string
+		for _, item := range toList(v) {
+			values = append(values, flatten(item)...)
+		}
+		return values
+	case map[string]interface{}:
+		encoded, _ := json.Marshal(v)
+		return []string{string(encoded)}
+	}
+	s := fmt.Sprintf("%v", value)
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
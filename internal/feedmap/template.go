@@ -0,0 +1,327 @@
+package feedmap
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Field is a feed column and the expression producing its value
+type Field struct {
+	Name string
+	Expr *Expr
+}
+
+// Value is a feed column's value for one product. Repeated fields, such as
+// additional_image_link, have several values.
+type Value struct {
+	Name   string
+	Values []string
+}
+
+// String joins a value's values with commas
+func (v Value) String() string {
+	return strings.Join(v.Values, ",")
+}
+
+// Template is a compiled feed template
+type Template struct {
+	Fields          []Field
+	Transformations Transformations
+}
+
+// New compiles a template's field_mapping and transformations JSON
+func New(fieldMapping, transformations string) (*Template, error) {
+	fields, err := ParseMapping(fieldMapping)
+	if err != nil {
+		return nil, err
+	}
+	t, err := ParseTransformations(transformations)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{Fields: fields, Transformations: t}, nil
+}
+
+// Names returns the template's column names in order
+func (t *Template) Names() []string {
+	names := make([]string, len(t.Fields))
+	for i, field := range t.Fields {
+		names[i] = field.Name
+	}
+	return names
+}
+
+// Row evaluates the template's fields for a product and transforms the results. Fields
+// without a value are included with no values, so CSV columns stay aligned.
+func (t *Template) Row(product map[string]interface{}) []Value {
+	row := make([]Value, 0, len(t.Fields))
+	for _, field := range t.Fields {
+		values := field.Expr.Eval(product)
+		for i := range values {
+			values[i] = t.Transformations.Apply(field.Name, values[i], product)
+		}
+		row = append(row, Value{Name: field.Name, Values: nonEmpty(values)})
+	}
+	return row
+}
+
+// ParseMapping reads a field mapping: a JSON object of column → expression, in the order
+// written, or an array of {"field": ..., "value": ...} objects. JSONB columns reorder
+// object keys, so templates stored there that need a column order use the array form.
+func ParseMapping(raw string) ([]Field, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "null" {
+		return nil, fmt.Errorf("field mapping is empty")
+	}
+
+	var fields []Field
+	add := func(name, source string) error {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return fmt.Errorf("field mapping has a column without a name")
+		}
+		expr, err := Compile(source)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		fields = append(fields, Field{Name: name, Expr: expr})
+		return nil
+	}
+
+	if strings.HasPrefix(raw, "[") {
+		var entries []struct {
+			Field string `json:"field"`
+			Value string `json:"value"`
+		}
+		if err := json.Unmarshal([]byte(raw), &entries); err != nil {
+			return nil, fmt.Errorf("invalid field mapping: %w", err)
+		}
+		for _, entry := range entries {
+			if err := add(entry.Field, entry.Value); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		// Decode token by token to keep the object's key order
+		dec := json.NewDecoder(bytes.NewReader([]byte(raw)))
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+			return nil, fmt.Errorf("invalid field mapping: expected an object or array")
+		}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, fmt.Errorf("invalid field mapping: %w", err)
+			}
+			var source string
+			if err := dec.Decode(&source); err != nil {
+				return nil, fmt.Errorf("invalid field mapping: %s must be a string expression", key)
+			}
+			if err := add(key.(string), source); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("field mapping is empty")
+	}
+	return fields, nil
+}
+
+// Transformations post-process mapped values:
+//
+//	{"price_format": "decimal", "description_max_length": 5000, "title": ["strip_html", "truncate:150"]}
+//
+// price_format applies to price and sale_price: "decimal" (the default) gives "12.50 USD"
+// with the product's currency, "number" gives "12.50". <column>_max_length truncates a
+// column. A column's own entry is an operation or list of operations run in order:
+// trim, strip_html, lowercase, uppercase, capitalize, truncate:N, prefix:TEXT,
+// suffix:TEXT and replace:OLD=>NEW.
+type Transformations struct {
+	PriceFormat string
+	MaxLength   map[string]int
+	Operations  map[string][]Operation
+}
+
+// Operation is one transformation step
+type Operation struct {
+	Name string
+	Arg  string
+}
+
+// Price formats
+const (
+	PriceDecimal = "decimal"
+	PriceNumber  = "number"
+)
+
+// priceFields are the columns price_format applies to
+var priceFields = map[string]bool{"price": true, "sale_price": true}
+
+var operations = map[string]bool{
+	"trim": true, "strip_html": true, "lowercase": true, "uppercase": true, "capitalize": true,
+	"truncate": true, "prefix": true, "suffix": true, "replace": true,
+}
+
+// ParseTransformations reads a template's transformations JSON
+func ParseTransformations(raw string) (Transformations, error) {
+	t := Transformations{
+		PriceFormat: PriceDecimal,
+		MaxLength:   make(map[string]int),
+		Operations:  make(map[string][]Operation),
+	}
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "null" {
+		return t, nil
+	}
+	var entries map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &entries); err != nil {
+		return t, fmt.Errorf("invalid transformations: %w", err)
+	}
+
+	for key, value := range entries {
+		switch {
+		case key == "price_format":
+			format, _ := value.(string)
+			if format != PriceDecimal && format != PriceNumber {
+				return t, fmt.Errorf("price_format must be %s or %s", PriceDecimal, PriceNumber)
+			}
+			t.PriceFormat = format
+		case strings.HasSuffix(key, "_max_length"):
+			length, ok := value.(float64)
+			if !ok || length <= 0 {
+				return t, fmt.Errorf("%s must be a positive number", key)
+			}
+			t.MaxLength[strings.TrimSuffix(key, "_max_length")] = int(length)
+		default:
+			var steps []string
+			switch v := value.(type) {
+			case string:
+				steps = []string{v}
+			case []interface{}:
+				for _, step := range v {
+					s, ok := step.(string)
+					if !ok {
+						return t, fmt.Errorf("%s: operations must be strings", key)
+					}
+					steps = append(steps, s)
+				}
+			default:
+				return t, fmt.Errorf("%s: expected an operation or a list of operations", key)
+			}
+			for _, step := range steps {
+				op, err := parseOperation(step)
+				if err != nil {
+					return t, fmt.Errorf("%s: %w", key, err)
+				}
+				t.Operations[key] = append(t.Operations[key], op)
+			}
+		}
+	}
+	return t, nil
+}
+
+func parseOperation(step string) (Operation, error) {
+	name, arg, _ := strings.Cut(step, ":")
+	op := Operation{Name: strings.TrimSpace(name), Arg: arg}
+	if !operations[op.Name] {
+		return op, fmt.Errorf("unknown operation %q", op.Name)
+	}
+	switch op.Name {
+	case "truncate":
+		if n, err := strconv.Atoi(arg); err != nil || n <= 0 {
+			return op, fmt.Errorf("truncate needs a positive length")
+		}
+	case "replace":
+		if !strings.Contains(arg, "=>") {
+			return op, fmt.Errorf("replace needs OLD=>NEW")
+		}
+	}
+	return op, nil
+}
+
+// Apply transforms one value of a column
+func (t Transformations) Apply(name, value string, product map[string]interface{}) string {
+	for _, op := range t.Operations[name] {
+		value = op.apply(value)
+	}
+	if priceFields[name] {
+		value = t.formatPrice(value, product)
+	}
+	if max := t.MaxLength[name]; max > 0 {
+		value = truncate(value, max)
+	}
+	return value
+}
+
+// formatPrice formats numeric prices; values that aren't numbers, such as "12.50 EUR"
+// from a literal, are kept
+func (t Transformations) formatPrice(value string, product map[string]interface{}) string {
+	amount, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return value
+	}
+	formatted := strconv.FormatFloat(amount, 'f', 2, 64)
+	if t.PriceFormat == PriceNumber {
+		return formatted
+	}
+	if currency, ok := product["currency"].(string); ok && currency != "" {
+		return formatted + " " + currency
+	}
+	return formatted
+}
+
+var htmlTags = regexp.MustCompile(`<[^>]*>`)
+
+func (op Operation) apply(value string) string {
+	switch op.Name {
+	case "trim":
+		return strings.TrimSpace(value)
+	case "strip_html":
+		return strings.Join(strings.Fields(html.UnescapeString(htmlTags.ReplaceAllString(value, " "))), " ")
+	case "lowercase":
+		return strings.ToLower(value)
+	case "uppercase":
+		return strings.ToUpper(value)
+	case "capitalize":
+		runes := []rune(value)
+		if len(runes) == 0 {
+			return value
+		}
+		return strings.ToUpper(string(runes[0])) + string(runes[1:])
+	case "truncate":
+		n, _ := strconv.Atoi(op.Arg)
+		return truncate(value, n)
+	case "prefix":
+		return op.Arg + value
+	case "suffix":
+		return value + op.Arg
+	case "replace":
+		old, replacement, _ := strings.Cut(op.Arg, "=>")
+		return strings.ReplaceAll(value, old, replacement)
+	}
+	return value
+}
+
+// truncate shortens value to at most max characters, keeping whole runes
+func truncate(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
+	}
+	return string(runes[:max])
+}
+
+func nonEmpty(values []string) []string {
+	kept := values[:0]
+	for _, v := range values {
+		if v != "" {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
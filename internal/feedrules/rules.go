@@ -0,0 +1,524 @@
+// Package feedrules runs a feed's ordered rules against its products before the feed is
+// generated. A rule's conditions test product fields; when they match, its actions
+// rewrite fields or exclude the product:
+//
+//	{"name": "Brand in title",
+//	 "conditions": [{"field": "title", "operator": "not_contains", "value": "{{brand}}"}],
+//	 "actions": [{"type": "append", "field": "title", "value": " - {{brand}}"}]}
+//
+// Condition fields are feedmap expressions, so they can read metadata ("metadata.tags")
+// and list elements. Values may reference other fields as {{path}}.
+package feedrules
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"lister/internal/feedmap"
+)
+
+// Match modes: a rule applies when all or any of its conditions hold
+const (
+	MatchAll = "all"
+	MatchAny = "any"
+)
+
+// Condition operators
+const (
+	OpEquals      = "equals"
+	OpNotEquals   = "not_equals"
+	OpContains    = "contains"
+	OpNotContains = "not_contains"
+	OpRegex       = "regex"
+	OpGreater     = "gt"
+	OpGreaterOrEq = "gte"
+	OpLess        = "lt"
+	OpLessOrEq    = "lte"
+	OpIn          = "in"
+	OpNotIn       = "not_in"
+	OpEmpty       = "empty"
+	OpNotEmpty    = "not_empty"
+)
+
+// Action types
+const (
+	ActionSet           = "set"
+	ActionAppend        = "append"
+	ActionPrepend       = "prepend"
+	ActionReplaceRegex  = "replace_regex"
+	ActionLowercase     = "lowercase"
+	ActionTitlecase     = "titlecase"
+	ActionTruncateWords = "truncate_words"
+	ActionExclude       = "exclude"
+)
+
+// Operators and Actions list the supported condition operators and action types
+var (
+	Operators = []string{OpEquals, OpNotEquals, OpContains, OpNotContains, OpRegex, OpGreater, OpGreaterOrEq, OpLess, OpLessOrEq, OpIn, OpNotIn, OpEmpty, OpNotEmpty}
+	Actions   = []string{ActionSet, ActionAppend, ActionPrepend, ActionReplaceRegex, ActionLowercase, ActionTitlecase, ActionTruncateWords, ActionExclude}
+)
+
+// Rule is a feed rule as stored and sent by clients
+type Rule struct {
+	ID         string      `json:"id,omitempty"`
+	Name       string      `json:"name"`
+	Enabled    bool        `json:"enabled"`
+	Match      string      `json:"match"`
+	Conditions []Condition `json:"conditions"`
+	Actions    []Action    `json:"actions"`
+}
+
+// Condition tests a field. Value is a string, number or, for in and not_in, a list;
+// ValueField compares against another field instead, as in compare_at_price gt price.
+type Condition struct {
+	Field         string      `json:"field"`
+	Operator      string      `json:"operator"`
+	Value         interface{} `json:"value,omitempty"`
+	ValueField    string      `json:"value_field,omitempty"`
+	CaseSensitive bool        `json:"case_sensitive,omitempty"`
+}
+
+// Action rewrites a field or excludes the product. Pattern is the regular expression of
+// replace_regex, whose Value may use $1 groups; Length is the limit of truncate_words.
+type Action struct {
+	Type    string `json:"type"`
+	Field   string `json:"field,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Pattern string `json:"pattern,omitempty"`
+	Length  int    `json:"length,omitempty"`
+}
+
+// Engine is a compiled, ordered rule set
+type Engine struct {
+	rules []*compiledRule
+}
+
+type compiledRule struct {
+	Rule
+	conditions []*compiledCondition
+	actions    []*compiledAction
+}
+
+type compiledCondition struct {
+	Condition
+	field      *feedmap.Expr
+	valueField *feedmap.Expr
+	pattern    *regexp.Regexp
+	list       []string
+}
+
+type compiledAction struct {
+	Action
+	pattern *regexp.Regexp
+}
+
+// Change is a field an action rewrote
+type Change struct {
+	Action string `json:"action"`
+	Field  string `json:"field,omitempty"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// Step is one rule's effect on a product
+type Step struct {
+	RuleID   string   `json:"rule_id,omitempty"`
+	Name     string   `json:"name"`
+	Enabled  bool     `json:"enabled"`
+	Matched  bool     `json:"matched"`
+	Changes  []Change `json:"changes"`
+	Excluded bool     `json:"excluded"`
+}
+
+// Compile validates rules and prepares them to run in order. Disabled rules are kept so
+// traces can show them, but never apply.
+func Compile(rules []Rule) (*Engine, error) {
+	e := &Engine{}
+	for i, rule := range rules {
+		compiled, err := compileRule(rule)
+		if err != nil {
+			name := rule.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			return nil, fmt.Errorf("rule %s: %w", name, err)
+		}
+		e.rules = append(e.rules, compiled)
+	}
+	return e, nil
+}
+
+// Validate reports whether a single rule compiles
+func Validate(rule Rule) error {
+	_, err := compileRule(rule)
+	return err
+}
+
+func compileRule(rule Rule) (*compiledRule, error) {
+	if rule.Match == "" {
+		rule.Match = MatchAll
+	}
+	if rule.Match != MatchAll && rule.Match != MatchAny {
+		return nil, fmt.Errorf("match must be %s or %s", MatchAll, MatchAny)
+	}
+	if len(rule.Actions) == 0 {
+		return nil, fmt.Errorf("at least one action is required")
+	}
+
+	compiled := &compiledRule{Rule: rule}
+	for _, condition := range rule.Conditions {
+		c, err := compileCondition(condition)
+		if err != nil {
+			return nil, err
+		}
+		compiled.conditions = append(compiled.conditions, c)
+	}
+	for _, action := range rule.Actions {
+		a, err := compileAction(action)
+		if err != nil {
+			return nil, err
+		}
+		compiled.actions = append(compiled.actions, a)
+	}
+	return compiled, nil
+}
+
+func compileCondition(condition Condition) (*compiledCondition, error) {
+	field, err := feedmap.Compile(condition.Field)
+	if err != nil {
+		return nil, fmt.Errorf("condition field: %w", err)
+	}
+	c := &compiledCondition{Condition: condition, field: field}
+	if condition.ValueField != "" {
+		if c.valueField, err = feedmap.Compile(condition.ValueField); err != nil {
+			return nil, fmt.Errorf("condition value_field: %w", err)
+		}
+	}
+
+	switch condition.Operator {
+	case OpEquals, OpNotEquals, OpContains, OpNotContains, OpEmpty, OpNotEmpty:
+	case OpGreater, OpGreaterOrEq, OpLess, OpLessOrEq:
+		if c.valueField == nil {
+			if _, ok := toNumber(valueString(condition.Value)); !ok {
+				return nil, fmt.Errorf("%s %s needs a numeric value", condition.Field, condition.Operator)
+			}
+		}
+	case OpRegex:
+		pattern := valueString(condition.Value)
+		if !condition.CaseSensitive {
+			pattern = "(?i)" + pattern
+		}
+		if c.pattern, err = regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("%s regex: %w", condition.Field, err)
+		}
+	case OpIn, OpNotIn:
+		switch v := condition.Value.(type) {
+		case []interface{}:
+			for _, item := range v {
+				c.list = append(c.list, valueString(item))
+			}
+		case string:
+			for _, item := range strings.Split(v, ",") {
+				c.list = append(c.list, strings.TrimSpace(item))
+			}
+		default:
+			return nil, fmt.Errorf("%s %s needs a list", condition.Field, condition.Operator)
+		}
+	default:
+		return nil, fmt.Errorf("unknown operator %q", condition.Operator)
+	}
+	return c, nil
+}
+
+func compileAction(action Action) (*compiledAction, error) {
+	a := &compiledAction{Action: action}
+	switch action.Type {
+	case ActionExclude:
+		return a, nil
+	case ActionSet, ActionAppend, ActionPrepend, ActionLowercase, ActionTitlecase:
+	case ActionReplaceRegex:
+		var err error
+		if a.pattern, err = regexp.Compile(action.Pattern); err != nil {
+			return nil, fmt.Errorf("replace_regex pattern: %w", err)
+		}
+	case ActionTruncateWords:
+		if action.Length <= 0 {
+			return nil, fmt.Errorf("truncate_words needs a positive length")
+		}
+	default:
+		return nil, fmt.Errorf("unknown action %q", action.Type)
+	}
+	if action.Field == "" || strings.ContainsAny(action.Field, ".[") {
+		return nil, fmt.Errorf("%s needs a top-level field to write", action.Type)
+	}
+	return a, nil
+}
+
+// Apply runs the rules against product, rewriting its fields, and reports whether a rule
+// excluded it. Rules after an exclusion don't run.
+func (e *Engine) Apply(product map[string]interface{}) bool {
+	for _, rule := range e.rules {
+		if step := rule.run(product); step.Excluded {
+			return true
+		}
+	}
+	return false
+}
+
+// Trace runs the rules like Apply and returns each rule's effect
+func (e *Engine) Trace(product map[string]interface{}) []Step {
+	steps := make([]Step, 0, len(e.rules))
+	excluded := false
+	for _, rule := range e.rules {
+		if excluded {
+			steps = append(steps, Step{RuleID: rule.ID, Name: rule.Name, Enabled: rule.Enabled, Changes: []Change{}})
+			continue
+		}
+		step := rule.run(product)
+		excluded = step.Excluded
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// Len returns the number of rules
+func (e *Engine) Len() int {
+	return len(e.rules)
+}
+
+func (r *compiledRule) run(product map[string]interface{}) Step {
+	step := Step{RuleID: r.ID, Name: r.Name, Enabled: r.Enabled, Changes: []Change{}}
+	if !r.Enabled || !r.matches(product) {
+		return step
+	}
+	step.Matched = true
+	for _, action := range r.actions {
+		if action.Type == ActionExclude {
+			step.Excluded = true
+			step.Changes = append(step.Changes, Change{Action: ActionExclude})
+			return step
+		}
+		before := fieldString(product, action.Field)
+		after := action.apply(before, product)
+		if after != before {
+			product[action.Field] = after
+			step.Changes = append(step.Changes, Change{Action: action.Type, Field: action.Field, Before: before, After: after})
+		}
+	}
+	return step
+}
+
+func (r *compiledRule) matches(product map[string]interface{}) bool {
+	if len(r.conditions) == 0 {
+		return true
+	}
+	for _, c := range r.conditions {
+		ok := c.holds(product)
+		if r.Match == MatchAny && ok {
+			return true
+		}
+		if r.Match != MatchAny && !ok {
+			return false
+		}
+	}
+	return r.Match != MatchAny
+}
+
+func (c *compiledCondition) holds(product map[string]interface{}) bool {
+	values := c.field.Eval(product)
+	value := strings.Join(values, ",")
+
+	var expected string
+	if c.valueField != nil {
+		expected = strings.Join(c.valueField.Eval(product), ",")
+	} else {
+		expected = expand(valueString(c.Value), product)
+	}
+
+	fold := func(s string) string {
+		if c.CaseSensitive {
+			return s
+		}
+		return strings.ToLower(s)
+	}
+
+	switch c.Operator {
+	case OpEquals:
+		return anyValue(values, func(v string) bool { return fold(v) == fold(expected) })
+	case OpNotEquals:
+		return !anyValue(values, func(v string) bool { return fold(v) == fold(expected) })
+	case OpContains:
+		return strings.Contains(fold(value), fold(expected))
+	case OpNotContains:
+		return !strings.Contains(fold(value), fold(expected))
+	case OpRegex:
+		return anyValue(values, c.pattern.MatchString)
+	case OpIn, OpNotIn:
+		found := anyValue(values, func(v string) bool {
+			for _, item := range c.list {
+				if fold(v) == fold(item) {
+					return true
+				}
+			}
+			return false
+		})
+		return found == (c.Operator == OpIn)
+	case OpEmpty:
+		return value == ""
+	case OpNotEmpty:
+		return value != ""
+	}
+
+	left, ok := toNumber(value)
+	right, ok2 := toNumber(expected)
+	if !ok || !ok2 {
+		return false
+	}
+	switch c.Operator {
+	case OpGreater:
+		return left > right
+	case OpGreaterOrEq:
+		return left >= right
+	case OpLess:
+		return left < right
+	case OpLessOrEq:
+		return left <= right
+	}
+	return false
+}
+
+func (a *compiledAction) apply(value string, product map[string]interface{}) string {
+	switch a.Type {
+	case ActionSet:
+		return expand(a.Value, product)
+	case ActionAppend:
+		return value + expand(a.Value, product)
+	case ActionPrepend:
+		return expand(a.Value, product) + value
+	case ActionReplaceRegex:
+		// A $ in a field's value is literal; only the rule's own $1 refers to groups
+		return a.pattern.ReplaceAllString(value, expandQuoted(a.Value, product, escapeDollars))
+	case ActionLowercase:
+		return strings.ToLower(value)
+	case ActionTitlecase:
+		return titlecase(value)
+	case ActionTruncateWords:
+		return truncateWords(value, a.Length)
+	}
+	return value
+}
+
+// placeholders are {{path}} references to other fields in values
+var placeholders = regexp.MustCompile(`\{\{\s*([^}]+?)\s*\}\}`)
+
+// expand replaces {{path}} placeholders with the fields they name
+func expand(value string, product map[string]interface{}) string {
+	return expandQuoted(value, product, nil)
+}
+
+// expandQuoted is expand with the fields' text passed through quote
+func expandQuoted(value string, product map[string]interface{}, quote func(string) string) string {
+	return placeholders.ReplaceAllStringFunc(value, func(match string) string {
+		path := placeholders.FindStringSubmatch(match)[1]
+		expr, err := feedmap.Compile(path)
+		if err != nil {
+			return ""
+		}
+		text := strings.Join(expr.Eval(product), ",")
+		if quote != nil {
+			text = quote(text)
+		}
+		return text
+	})
+}
+
+// escapeDollars makes text literal in a regexp replacement template
+func escapeDollars(text string) string {
+	return strings.ReplaceAll(text, "$", "$$")
+}
+
+// fieldString reads a top-level field as text
+func fieldString(product map[string]interface{}, field string) string {
+	switch v := product[field].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func valueString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func anyValue(values []string, test func(string) bool) bool {
+	if len(values) == 0 {
+		return test("")
+	}
+	for _, v := range values {
+		if test(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// toNumber parses amounts such as "12.50" or "12.50 USD"
+func toNumber(s string) (float64, bool) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(strings.ReplaceAll(fields[0], ",", ""), 64)
+	return n, err == nil
+}
+
+// titlecase upper-cases the first letter of each word and lower-cases the rest
+func titlecase(s string) string {
+	runes := []rune(strings.ToLower(s))
+	start := true
+	for i, r := range runes {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start {
+				runes[i] = unicode.ToUpper(r)
+			}
+			start = false
+		} else {
+			start = unicode.IsSpace(r) || r == '-' || r == '/'
+		}
+	}
+	return string(runes)
+}
+
+// truncateWords shortens s to at most max characters without cutting a word
+func truncateWords(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	cut := string(runes[:max])
+	if !unicode.IsSpace(runes[max]) {
+		if i := strings.LastIndexFunc(cut, unicode.IsSpace); i > 0 {
+			cut = cut[:i]
+		}
+	}
+	return strings.TrimRightFunc(cut, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	})
+}
@@ -0,0 +1,688 @@
+// Package jobs processes background jobs persisted in the database. A runner holds a lease
+// on the job it works on and renews it while processing; a job whose lease expired because
+// its runner crashed or was frozen is resumed by the next runner.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"lister/internal/llm"
+	"lister/internal/logger"
+	"lister/internal/models"
+	"lister/internal/review"
+	"lister/internal/worker/processors/ai"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Lease timing for bulk optimization jobs
+const (
+	jobLease     = 2 * time.Minute
+	jobHeartbeat = 10 * time.Second
+	itemBatch    = 100
+)
+
+// CreditsPerItem is charged per product when a bulk job is queued. Products answered from
+// the response cache, failed and cancelled items are refunded.
+const CreditsPerItem = 2
+
+// AICredits counters bumped per finished item
+const (
+	creditsSucceeded = "successful_optimizations"
+	creditsFailed    = "failed_optimizations"
+)
+
+// ErrJobNotFound is returned for jobs that don't exist or belong to another organization
+var ErrJobNotFound = errors.New("optimization job not found")
+
+// BulkOptimizer runs bulk optimization jobs
+type BulkOptimizer struct {
+	db        *gorm.DB
+	logger    *logger.Logger
+	optimizer *ai.Optimizer
+	owner     string
+}
+
+// NewBulkOptimizer creates a runner. Each runner identifies itself in the job lease so it
+// notices when another runner has taken a job over.
+func NewBulkOptimizer(db *gorm.DB, logger *logger.Logger, optimizer *ai.Optimizer) *BulkOptimizer {
+	host, _ := os.Hostname()
+	return &BulkOptimizer{
+		db:        db,
+		logger:    logger,
+		optimizer: optimizer,
+		owner:     fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.NewString()[:8]),
+	}
+}
+
+// Enqueue stores a queued job with one pending item per distinct product of the
+// organization. charge is called in the same transaction with the number of items, so a
+// job is only queued once it is paid for.
+func (r *BulkOptimizer) Enqueue(organizationID uuid.UUID, req *models.BulkOptimizationRequest, charge func(tx *gorm.DB, items int) error) (*models.OptimizationJob, error) {
+	productIDs := make([]uuid.UUID, 0, len(req.ProductIDs))
+	seen := map[uuid.UUID]bool{}
+	for _, id := range req.ProductIDs {
+		productID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid product ID %q", id)
+		}
+		if !seen[productID] {
+			seen[productID] = true
+			productIDs = append(productIDs, productID)
+		}
+	}
+
+	concurrency := req.Concurrency
+	if concurrency <= 0 {
+		concurrency = models.DefaultOptimizationJobConcurrency
+	}
+	if concurrency > models.MaxOptimizationJobConcurrency {
+		concurrency = models.MaxOptimizationJobConcurrency
+	}
+
+	job := &models.OptimizationJob{
+		ID:               uuid.New(),
+		OrganizationID:   organizationID,
+		OptimizationType: req.OptimizationType,
+		Status:           models.OptimizationJobQueued,
+		AutoApply:        req.AutoApply,
+		Concurrency:      concurrency,
+		TotalItems:       len(productIDs),
+		Options: models.JSONB{
+			"target_audience":  req.TargetAudience,
+			"language":         req.Language,
+			"tone":             req.Tone,
+			"include_keywords": req.IncludeKeywords,
+			"no_cache":         req.NoCache,
+		},
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if len(productIDs) > 0 {
+			var owned []uuid.UUID
+			err := tx.Model(&models.Product{}).
+				Where("id IN ? AND organization_id = ?", productIDs, organizationID).
+				Pluck("id", &owned).Error
+			if err != nil {
+				return err
+			}
+			if len(owned) < len(productIDs) {
+				found := map[uuid.UUID]bool{}
+				for _, id := range owned {
+					found[id] = true
+				}
+				for _, id := range productIDs {
+					if !found[id] {
+						return fmt.Errorf("product %s not found", id)
+					}
+				}
+			}
+		}
+		if err := charge(tx, job.TotalItems); err != nil {
+			return err
+		}
+		if err := tx.Create(job).Error; err != nil {
+			return err
+		}
+		items := make([]models.OptimizationJobItem, 0, len(productIDs))
+		for _, productID := range productIDs {
+			items = append(items, models.OptimizationJobItem{
+				ID:        uuid.New(),
+				JobID:     job.ID,
+				ProductID: productID,
+				Status:    models.OptimizationJobItemPending,
+			})
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(items, itemBatch).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Get returns one of the organization's jobs
+func (r *BulkOptimizer) Get(organizationID, jobID uuid.UUID) (*models.OptimizationJob, error) {
+	var job models.OptimizationJob
+	err := r.db.Where("id = ? AND organization_id = ?", jobID, organizationID).First(&job).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// SetStatus pauses, resumes or cancels a job. Resuming queues a paused job again; the
+// runner working on a job notices pause and cancel at its next heartbeat.
+func (r *BulkOptimizer) SetStatus(organizationID, jobID uuid.UUID, status models.OptimizationJobStatus) (*models.OptimizationJob, error) {
+	job, err := r.Get(organizationID, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	var from []models.OptimizationJobStatus
+	switch status {
+	case models.OptimizationJobPaused:
+		from = []models.OptimizationJobStatus{models.OptimizationJobQueued, models.OptimizationJobRunning}
+	case models.OptimizationJobQueued:
+		from = []models.OptimizationJobStatus{models.OptimizationJobPaused}
+	case models.OptimizationJobCancelled:
+		from = []models.OptimizationJobStatus{models.OptimizationJobQueued, models.OptimizationJobRunning, models.OptimizationJobPaused}
+	default:
+		return nil, fmt.Errorf("unsupported job status %q", status)
+	}
+
+	updates := map[string]interface{}{"status": status, "updated_at": time.Now()}
+	if status == models.OptimizationJobQueued {
+		updates["error_message"] = nil
+	}
+	res := r.db.Model(&models.OptimizationJob{}).Where("id = ? AND status IN ?", job.ID, from).Updates(updates)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, fmt.Errorf("cannot change a %s job to %s", job.Status, status)
+	}
+
+	if status == models.OptimizationJobCancelled {
+		r.cancelPendingItems(job)
+	}
+	return r.Get(organizationID, jobID)
+}
+
+// Claim leases a job for this runner: jobID when set, otherwise the oldest queued job or
+// running job whose lease expired. It returns nil when there is nothing to run.
+func (r *BulkOptimizer) Claim(jobID *uuid.UUID) (*models.OptimizationJob, error) {
+	filter, args := "", []interface{}{r.owner, time.Now().Add(jobLease)}
+	if jobID != nil {
+		filter = "AND id = ?"
+		args = append(args, *jobID)
+	}
+
+	var job models.OptimizationJob
+	err := r.db.Raw(`
+		UPDATE optimization_jobs
+		SET status = 'running', locked_by = ?, locked_until = ?,
+		    started_at = COALESCE(started_at, NOW()), updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM optimization_jobs
+			WHERE (status = 'queued' OR (status = 'running' AND (locked_until IS NULL OR locked_until < NOW())))
+			`+filter+`
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, args...).Scan(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	if job.ID == uuid.Nil {
+		return nil, nil
+	}
+	return &job, nil
+}
+
+// RunPending claims and runs jobs until none are runnable or ctx is done, and returns how
+// many it ran
+func (r *BulkOptimizer) RunPending(ctx context.Context) (int, error) {
+	ran := 0
+	for ctx.Err() == nil {
+		job, err := r.Claim(nil)
+		if err != nil {
+			return ran, err
+		}
+		if job == nil {
+			break
+		}
+		if err := r.Run(ctx, job); err != nil {
+			r.logger.Error("Optimization job %s failed: %v", job.ID, err)
+		}
+		ran++
+	}
+	return ran, nil
+}
+
+// Start polls for runnable jobs every interval until ctx is cancelled
+func (r *BulkOptimizer) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	r.logger.Info("Optimization job runner %s started", r.owner)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := r.RunPending(ctx); err != nil {
+			r.logger.Error("Failed to run optimization jobs: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run processes the pending items of a job claimed by this runner with bounded
+// concurrency. It returns when every item is processed, the job is paused or cancelled,
+// the lease is lost or ctx is done; in the last case the lease is released so another
+// runner resumes the job.
+func (r *BulkOptimizer) Run(ctx context.Context, job *models.OptimizationJob) error {
+	r.logger.Info("Running optimization job %s (%d items)", job.ID, job.TotalItems)
+
+	// Items a previous runner left half-done are processed again
+	r.db.Model(&models.OptimizationJobItem{}).
+		Where("job_id = ? AND status = ?", job.ID, models.OptimizationJobItemRunning).
+		Update("status", models.OptimizationJobItemPending)
+
+	settings := ai.Settings(r.db, job.OrganizationID)
+	// One budget for the whole job so it pauses once the monthly limit is reached
+	budget := ai.MonthlyBudget(r.db, job.OrganizationID, settings)
+	base := r.optimizer.ForOrganization(r.db, job.OrganizationID, settings, budget)
+
+	runCtx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	var lost bool
+	var mu sync.Mutex
+	go func() {
+		ticker := time.NewTicker(jobHeartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-runCtx.Done():
+				return
+			case <-ticker.C:
+				if !r.heartbeat(job) {
+					mu.Lock()
+					lost = true
+					mu.Unlock()
+					stop()
+					return
+				}
+			}
+		}
+	}()
+
+	workers := job.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	items := make(chan models.OptimizationJobItem)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range items {
+				if err := r.process(job, settings, base, budget, item); errors.Is(err, llm.ErrBudgetExceeded) {
+					r.pause(job, err)
+					stop()
+				}
+			}
+		}()
+	}
+
+	var feedErr error
+	var lastID uuid.UUID
+feed:
+	for runCtx.Err() == nil {
+		var batch []models.OptimizationJobItem
+		query := r.db.Where("job_id = ? AND status = ?", job.ID, models.OptimizationJobItemPending)
+		if lastID != uuid.Nil {
+			query = query.Where("id > ?", lastID)
+		}
+		if feedErr = query.Order("id").Limit(itemBatch).Find(&batch).Error; feedErr != nil || len(batch) == 0 {
+			break
+		}
+		for _, item := range batch {
+			select {
+			case items <- item:
+				lastID = item.ID
+			case <-runCtx.Done():
+				break feed
+			}
+		}
+	}
+	close(items)
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if lost {
+		r.logger.Info("Optimization job %s was taken over or stopped", job.ID)
+		return nil
+	}
+	if feedErr != nil {
+		r.release(job)
+		return feedErr
+	}
+	if ctx.Err() != nil {
+		r.release(job)
+		return nil
+	}
+	return r.finish(job)
+}
+
+// process optimizes one item. It returns llm.ErrBudgetExceeded, leaving the item pending,
+// when the monthly budget is spent.
+func (r *BulkOptimizer) process(job *models.OptimizationJob, settings *models.AISettings, base *ai.Optimizer, budget *llm.Budget, item models.OptimizationJobItem) error {
+	now := time.Now()
+	res := r.db.Model(&models.OptimizationJobItem{}).
+		Where("id = ? AND status = ?", item.ID, models.OptimizationJobItemPending).
+		Updates(map[string]interface{}{
+			"status":     models.OptimizationJobItemRunning,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"started_at": now,
+		})
+	if res.Error != nil || res.RowsAffected == 0 {
+		return res.Error
+	}
+
+	var product models.Product
+	if err := r.db.First(&product, "id = ? AND organization_id = ?", item.ProductID, job.OrganizationID).Error; err != nil {
+		r.updateCredits(job.OrganizationID, 0, creditsFailed, CreditsPerItem)
+		r.complete(job, item.ID, models.OptimizationJobItemFailed, nil, "", "Product not found", 0)
+		return nil
+	}
+
+	productData := jobProductData(job, &product)
+	optimizer := base.WithBudget(budget)
+
+	// Reruns over unchanged products are answered from the cache at no cost
+	cacheKey := optimizer.CacheKey(job.OptimizationType, productData)
+	if noCache, _ := job.Options["no_cache"].(bool); !noCache {
+		if cached := ai.CachedResponse(r.db, settings, cacheKey); cached != nil {
+			history := cached.History(item.ProductID, originalValue(&product, job.OptimizationType))
+			history.ID = uuid.New()
+			history.Metadata["job_id"] = job.ID.String()
+			score(history)
+			if err := r.db.Create(history).Error; err != nil {
+				r.logger.Error("Failed to save optimization history for %s: %v", item.ProductID, err)
+			}
+			r.autoApply(job, settings, history)
+			r.updateCredits(job.OrganizationID, 0, creditsSucceeded, CreditsPerItem)
+			r.complete(job, item.ID, models.OptimizationJobItemSucceeded, &history.ID, history.OptimizedValue, "", 0)
+			return nil
+		}
+	}
+
+	var optimizedValue string
+	var optimizationErr error
+	switch job.OptimizationType {
+	case models.OptimizationTypeTitle:
+		optimizedValue, optimizationErr = optimizer.OptimizeTitle(productData)
+	case models.OptimizationTypeDescription:
+		optimizedValue, optimizationErr = optimizer.OptimizeDescription(productData)
+	case models.OptimizationTypeCategory:
+		optimizedValue, optimizationErr = optimizer.SuggestCategory(productData)
+	default:
+		optimizationErr = errors.New("unsupported optimization type")
+	}
+
+	aiModel, usage, cost := optimizer.RecordUsage(r.db, job.OrganizationID, string(job.OptimizationType), settings.DefaultModel)
+
+	if errors.Is(optimizationErr, llm.ErrBudgetExceeded) {
+		r.db.Model(&models.OptimizationJobItem{}).Where("id = ?", item.ID).
+			Update("status", models.OptimizationJobItemPending)
+		r.addCost(job, cost)
+		return optimizationErr
+	}
+
+	history := &models.OptimizationHistory{
+		ID:               uuid.New(),
+		ProductID:        item.ProductID,
+		OrganizationID:   job.OrganizationID,
+		OptimizationType: job.OptimizationType,
+		OriginalValue:    originalValue(&product, job.OptimizationType),
+		OptimizedValue:   optimizedValue,
+		Status:           models.OptimizationStatusPending,
+		AIModel:          aiModel,
+		Cost:             cost,
+		TokensUsed:       usage.TotalTokens,
+		Metadata:         models.JSONB{"job_id": job.ID.String()},
+	}
+	history.SetPrompt(optimizer.Template(job.OptimizationType))
+
+	status := models.OptimizationJobItemSucceeded
+	errorMsg := ""
+	if optimizationErr != nil {
+		status = models.OptimizationJobItemFailed
+		errorMsg = optimizationErr.Error()
+		history.Status = models.OptimizationStatusFailed
+		history.ErrorMessage = &errorMsg
+	} else {
+		score(history)
+	}
+	if err := r.db.Create(history).Error; err != nil {
+		r.logger.Error("Failed to save optimization history for %s: %v", item.ProductID, err)
+	}
+	if optimizationErr == nil {
+		if err := ai.CacheResponse(r.db, settings, cacheKey, optimizer.Template(job.OptimizationType), aiModel, optimizedValue); err != nil {
+			r.logger.Error("Failed to cache optimization: %v", err)
+		}
+	}
+	if optimizationErr == nil {
+		r.autoApply(job, settings, history)
+	}
+	if optimizationErr == nil {
+		r.updateCredits(job.OrganizationID, cost, creditsSucceeded, 0)
+	} else {
+		r.updateCredits(job.OrganizationID, cost, creditsFailed, CreditsPerItem)
+	}
+
+	r.complete(job, item.ID, status, &history.ID, optimizedValue, errorMsg, cost)
+	return nil
+}
+
+// complete stores an item's result and adds it to the job's progress counters
+func (r *BulkOptimizer) complete(job *models.OptimizationJob, itemID uuid.UUID, status models.OptimizationJobItemStatus, historyID *uuid.UUID, value, errorMsg string, cost float64) {
+	updates := map[string]interface{}{
+		"status":                  status,
+		"optimization_history_id": historyID,
+		"completed_at":            time.Now(),
+	}
+	if value != "" {
+		updates["optimized_value"] = value
+	}
+	if errorMsg != "" {
+		updates["error_message"] = errorMsg
+	}
+	if err := r.db.Model(&models.OptimizationJobItem{}).Where("id = ?", itemID).Updates(updates).Error; err != nil {
+		r.logger.Error("Failed to update optimization job item %s: %v", itemID, err)
+	}
+
+	counter := "failed_items"
+	switch status {
+	case models.OptimizationJobItemSucceeded:
+		counter = "succeeded_items"
+	case models.OptimizationJobItemSkipped:
+		counter = "skipped_items"
+	}
+	r.db.Model(&models.OptimizationJob{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+		"processed_items": gorm.Expr("processed_items + 1"),
+		counter:           gorm.Expr(counter + " + 1"),
+		"cost":            gorm.Expr("cost + ?", cost),
+		"updated_at":      time.Now(),
+	})
+}
+
+func (r *BulkOptimizer) addCost(job *models.OptimizationJob, cost float64) {
+	if cost > 0 {
+		r.db.Model(&models.OptimizationJob{}).Where("id = ?", job.ID).Update("cost", gorm.Expr("cost + ?", cost))
+	}
+}
+
+// heartbeat renews the lease and reports whether the runner should keep going: false when
+// another runner took the job over or it was paused or cancelled
+func (r *BulkOptimizer) heartbeat(job *models.OptimizationJob) bool {
+	res := r.db.Model(&models.OptimizationJob{}).
+		Where("id = ? AND locked_by = ? AND status = ?", job.ID, r.owner, models.OptimizationJobRunning).
+		Update("locked_until", time.Now().Add(jobLease))
+	if res.Error != nil {
+		r.logger.Error("Failed to renew lease of optimization job %s: %v", job.ID, res.Error)
+		return true
+	}
+	return res.RowsAffected > 0
+}
+
+// pause stops a job when the monthly AI budget is spent; it is resumed with SetStatus once
+// the budget allows
+func (r *BulkOptimizer) pause(job *models.OptimizationJob, cause error) {
+	r.db.Model(&models.OptimizationJob{}).
+		Where("id = ? AND status = ?", job.ID, models.OptimizationJobRunning).
+		Updates(map[string]interface{}{
+			"status":        models.OptimizationJobPaused,
+			"error_message": cause.Error(),
+			"locked_by":     nil,
+			"locked_until":  nil,
+			"updated_at":    time.Now(),
+		})
+}
+
+// release gives up the lease so another runner resumes the job right away
+func (r *BulkOptimizer) release(job *models.OptimizationJob) {
+	r.db.Model(&models.OptimizationJob{}).
+		Where("id = ? AND locked_by = ?", job.ID, r.owner).
+		Updates(map[string]interface{}{"locked_by": nil, "locked_until": nil, "updated_at": time.Now()})
+}
+
+// finish marks a job done once no items are pending and releases the lease
+func (r *BulkOptimizer) finish(job *models.OptimizationJob) error {
+	var pending int64
+	if err := r.db.Model(&models.OptimizationJobItem{}).
+		Where("job_id = ? AND status IN ?", job.ID, []models.OptimizationJobItemStatus{models.OptimizationJobItemPending, models.OptimizationJobItemRunning}).
+		Count(&pending).Error; err != nil {
+		r.release(job)
+		return err
+	}
+
+	updates := map[string]interface{}{"locked_by": nil, "locked_until": nil, "updated_at": time.Now()}
+	if pending == 0 {
+		updates["status"] = models.OptimizationJobDone
+		updates["completed_at"] = time.Now()
+	}
+	err := r.db.Model(&models.OptimizationJob{}).
+		Where("id = ? AND locked_by = ?", job.ID, r.owner).
+		Updates(updates).Error
+	if err == nil && pending == 0 {
+		r.logger.Info("Optimization job %s done", job.ID)
+	}
+	return err
+}
+
+// cancelPendingItems cancels the items a job hasn't started and refunds their credits
+func (r *BulkOptimizer) cancelPendingItems(job *models.OptimizationJob) {
+	cancelled := r.db.Model(&models.OptimizationJobItem{}).
+		Where("job_id = ? AND status = ?", job.ID, models.OptimizationJobItemPending).
+		Updates(map[string]interface{}{"status": models.OptimizationJobItemCancelled, "completed_at": time.Now()})
+	if cancelled.RowsAffected > 0 {
+		r.updateCredits(job.OrganizationID, 0, "", int(cancelled.RowsAffected)*CreditsPerItem)
+	}
+	r.db.Model(&models.OptimizationJob{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+		"skipped_items": gorm.Expr("skipped_items + ?", cancelled.RowsAffected),
+		"completed_at":  time.Now(),
+		"locked_by":     nil,
+		"locked_until":  nil,
+	})
+}
+
+// autoApply applies a new optimization when the job or the organization's settings ask for
+// it and its score clears MinScoreThreshold; otherwise it waits in the review queue
+func (r *BulkOptimizer) autoApply(job *models.OptimizationJob, settings *models.AISettings, history *models.OptimizationHistory) {
+	if !review.ShouldAutoApply(settings, job.AutoApply, history) {
+		return
+	}
+	if err := review.Apply(r.db, history, review.Decision{Reviewer: review.AutoReviewer}); err != nil {
+		r.logger.Error("Failed to apply optimization %s: %v", history.ID, err)
+	}
+}
+
+// score rates an optimization against the value it replaces
+func score(history *models.OptimizationHistory) {
+	score, improvement := review.Score(history.OptimizationType, history.OriginalValue, history.OptimizedValue)
+	history.Score = &score
+	history.ImprovementPercentage = &improvement
+}
+
+// updateCredits adds an item's cost and refund to the organization's credits and bumps
+// counter, one of creditsSucceeded and creditsFailed, unless it is empty
+func (r *BulkOptimizer) updateCredits(organizationID uuid.UUID, cost float64, counter string, refund int) {
+	if cost < 0 {
+		cost = 0
+	}
+	if refund < 0 {
+		refund = 0
+	}
+
+	// One atomic update: workers finishing items of the same organization at once would
+	// otherwise overwrite each other's counts. A refund never exceeds the credits used.
+	updates := map[string]interface{}{
+		"total_spent":       gorm.Expr("total_spent + ?", cost),
+		"monthly_spent":     gorm.Expr("monthly_spent + ?", cost),
+		"credits_remaining": gorm.Expr("credits_remaining + LEAST(?, credits_used)", refund),
+		"credits_used":      gorm.Expr("credits_used - LEAST(?, credits_used)", refund),
+	}
+	if counter != "" {
+		updates[counter] = gorm.Expr(counter + " + 1")
+	}
+	err := r.db.Model(&models.AICredits{}).
+		Where("organization_id = ?", organizationID).
+		UpdateColumns(updates).Error
+	if err != nil {
+		r.logger.Error("Failed to update AI credits for %s: %v", organizationID, err)
+	}
+}
+
+// jobProductData maps a product and the job's options onto the optimizer's product data
+func jobProductData(job *models.OptimizationJob, product *models.Product) map[string]interface{} {
+	data := map[string]interface{}{
+		"title":       product.Title,
+		"description": stringValue(product.Description),
+		"brand":       stringValue(product.Brand),
+		"category":    stringValue(product.Category),
+		"price":       product.Price,
+		"currency":    product.Currency,
+		"sku":         product.SKU,
+	}
+	if v, ok := job.Options["target_audience"].(string); ok && v != "" {
+		data["target_audience"] = v
+	}
+	if v, ok := job.Options["language"].(string); ok && v != "" {
+		data["language"] = v
+	}
+	if v, ok := job.Options["tone"].(string); ok && v != "" {
+		data["style"] = v
+	}
+	return data
+}
+
+// originalValue returns the product field an optimization type replaces
+func originalValue(product *models.Product, t models.OptimizationType) string {
+	switch t {
+	case models.OptimizationTypeDescription:
+		return stringValue(product.Description)
+	case models.OptimizationTypeCategory:
+		return stringValue(product.Category)
+	default:
+		return product.Title
+	}
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,148 @@
+// Package labels assigns Google's custom_label_0 to custom_label_4, which Shopping
+// campaigns segment products by. Each of the five slots has a basis, such as the price
+// band or the product's age, and ordered rules mapping values of the basis to a label:
+//
+//	{"slot": 0, "name": "Price band", "basis": "price",
+//	 "rules": [{"max": 20, "label": "under-20"}, {"min": 20, "max": 100, "label": "20-100"}, {"min": 100, "label": "100-plus"}],
+//	 "default": "unpriced"}
+//
+// Numeric bases match rules whose [min, max) holds the value, with a missing bound left
+// open. The category basis matches rules whose match is a prefix of the product's
+// category or Google category path, ignoring case. The first matching rule's label wins;
+// a product no rule matches, or whose value is unknown, gets the slot's default.
+package labels
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Slots is the number of custom label slots channels accept
+const Slots = 5
+
+// Bases
+const (
+	BasisPrice    = "price"    // the store price
+	BasisMargin   = "margin"   // percent of the price over cost
+	BasisStock    = "stock"    // units in stock
+	BasisAge      = "age"      // days since the product was created
+	BasisCategory = "category" // the product's category, as set by AI categorization, or its Google category path
+)
+
+// Bases lists the bases slots can use
+var Bases = []string{BasisPrice, BasisMargin, BasisStock, BasisAge, BasisCategory}
+
+// Slot is the assignment of one custom label
+type Slot struct {
+	Slot    int    `json:"slot"`
+	Name    string `json:"name"`
+	Basis   string `json:"basis"`
+	Rules   []Rule `json:"rules"`
+	Default string `json:"default,omitempty"`
+	Enabled bool   `json:"enabled"`
+}
+
+// Rule maps a band of a numeric basis, or a category prefix, to a label
+type Rule struct {
+	Min   *float64 `json:"min,omitempty"`
+	Max   *float64 `json:"max,omitempty"`
+	Match string   `json:"match,omitempty"`
+	Label string   `json:"label"`
+}
+
+// Facts are what a product's labels are assigned from. Nil values are unknown, such as
+// the margin of a product without a cost.
+type Facts struct {
+	Price      float64  `json:"price"`
+	Margin     *float64 `json:"margin"`
+	Stock      *float64 `json:"stock"`
+	AgeDays    *float64 `json:"age_days"`
+	Categories []string `json:"categories"`
+}
+
+// Validate checks a slot
+func (s *Slot) Validate() error {
+	if s.Slot < 0 || s.Slot >= Slots {
+		return fmt.Errorf("slot must be between 0 and %d", Slots-1)
+	}
+	valid := false
+	for _, basis := range Bases {
+		valid = valid || s.Basis == basis
+	}
+	if !valid {
+		return fmt.Errorf("basis must be one of %s", strings.Join(Bases, ", "))
+	}
+	if s.Rules == nil {
+		s.Rules = []Rule{}
+	}
+	for i, rule := range s.Rules {
+		if strings.TrimSpace(rule.Label) == "" {
+			return fmt.Errorf("rule %d needs a label", i+1)
+		}
+		if s.Basis == BasisCategory {
+			if strings.TrimSpace(rule.Match) == "" {
+				return fmt.Errorf("rule %d needs a category to match", i+1)
+			}
+			continue
+		}
+		if rule.Min != nil && rule.Max != nil && *rule.Max <= *rule.Min {
+			return fmt.Errorf("rule %d needs max above min", i+1)
+		}
+	}
+	return nil
+}
+
+// Assign returns the slot's label for a product
+func (s Slot) Assign(f Facts) string {
+	if s.Basis == BasisCategory {
+		for _, rule := range s.Rules {
+			match := strings.ToLower(strings.TrimSpace(rule.Match))
+			for _, category := range f.Categories {
+				if strings.HasPrefix(strings.ToLower(category), match) {
+					return rule.Label
+				}
+			}
+		}
+		return s.Default
+	}
+
+	value := s.value(f)
+	if value == nil {
+		return s.Default
+	}
+	for _, rule := range s.Rules {
+		if (rule.Min == nil || *value >= *rule.Min) && (rule.Max == nil || *value < *rule.Max) {
+			return rule.Label
+		}
+	}
+	return s.Default
+}
+
+func (s Slot) value(f Facts) *float64 {
+	switch s.Basis {
+	case BasisPrice:
+		if f.Price <= 0 {
+			return nil
+		}
+		return &f.Price
+	case BasisMargin:
+		return f.Margin
+	case BasisStock:
+		return f.Stock
+	case BasisAge:
+		return f.AgeDays
+	}
+	return nil
+}
+
+// Assign returns a product's labels from the enabled slots, indexed by slot. Slots
+// without a configuration, or disabled, are "".
+func Assign(slots []Slot, f Facts) [Slots]string {
+	var assigned [Slots]string
+	for _, slot := range slots {
+		if slot.Enabled && slot.Slot >= 0 && slot.Slot < Slots {
+			assigned[slot.Slot] = slot.Assign(f)
+		}
+	}
+	return assigned
+}
@@ -0,0 +1,127 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const anthropicVersion = "2023-06-01"
+
+// AnthropicProvider talks to the Anthropic messages API
+type AnthropicProvider struct {
+	options   Options
+	transport *httpTransport
+}
+
+func NewAnthropic(options Options) *AnthropicProvider {
+	options = options.withDefaults("https://api.anthropic.com/v1", "claude-3-5-haiku-latest")
+	return &AnthropicProvider{
+		options:   options,
+		transport: newHTTPTransport(ProviderAnthropic, options),
+	}
+}
+
+type anthropicRequest struct {
+	Model       string    `json:"model"`
+	System      string    `json:"system,omitempty"`
+	Messages    []Message `json:"messages"`
+	MaxTokens   int       `json:"max_tokens"`
+	Temperature float64   `json:"temperature,omitempty"`
+	TopP        float64   `json:"top_p,omitempty"`
+}
+
+type anthropicResponse struct {
+	Model   string `json:"model"`
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+func (p *AnthropicProvider) Name() string {
+	return ProviderAnthropic
+}
+
+func (p *AnthropicProvider) DefaultModel() string {
+	return p.options.DefaultModel
+}
+
+func (p *AnthropicProvider) Complete(ctx context.Context, req Request) (*Response, error) {
+	if req.Model == "" {
+		req.Model = p.options.DefaultModel
+	}
+
+	// max_tokens is mandatory for the messages API
+	maxTokens := req.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = 1024
+	}
+
+	system := req.System
+	switch {
+	case req.Schema != nil:
+		system = schemaInstruction(system, req.Schema)
+	case req.JSON:
+		system = strings.TrimSpace(system + "\nRespond with a single JSON object and nothing else.")
+	}
+
+	body := anthropicRequest{
+		Model:       req.Model,
+		System:      system,
+		Messages:    req.Messages,
+		MaxTokens:   maxTokens,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+	}
+	headers := map[string]string{
+		"x-api-key":         p.options.APIKey,
+		"anthropic-version": anthropicVersion,
+	}
+
+	var resp anthropicResponse
+	if err := p.transport.post(ctx, "/messages", headers, body, &resp, parseAnthropicError); err != nil {
+		return nil, err
+	}
+
+	var text strings.Builder
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return nil, fmt.Errorf("no response from %s", ProviderAnthropic)
+	}
+
+	model := resp.Model
+	if model == "" {
+		model = req.Model
+	}
+	return &Response{
+		Content:  strings.TrimSpace(text.String()),
+		Provider: ProviderAnthropic,
+		Model:    model,
+		Usage: Usage{
+			PromptTokens:     resp.Usage.InputTokens,
+			CompletionTokens: resp.Usage.OutputTokens,
+			TotalTokens:      resp.Usage.InputTokens + resp.Usage.OutputTokens,
+		},
+	}, nil
+}
+
+func parseAnthropicError(body []byte) string {
+	var resp struct {
+		Error struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	json.Unmarshal(body, &resp)
+	return resp.Error.Message
+}
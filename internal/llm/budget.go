@@ -0,0 +1,61 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrBudgetExceeded is returned when a request would take an organization over its
+// monthly AI budget and no cheaper model is configured
+var ErrBudgetExceeded = errors.New("monthly AI budget exceeded")
+
+// Budget tracks spend against a monthly limit in USD. Completions made with a budget add
+// their cost to it, so a budget shared by a bulk run stops it once the limit is reached.
+// Completions in flight hold their estimated cost until they settle, so concurrent
+// requests can't all pass the check against the same remaining budget.
+type Budget struct {
+	Limit float64 // <= 0 means unlimited
+
+	mu       sync.Mutex
+	spent    float64
+	reserved float64
+}
+
+func NewBudget(limit, spent float64) *Budget {
+	return &Budget{Limit: limit, spent: spent}
+}
+
+// Spent returns the spend so far this month
+func (b *Budget) Spent() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.spent
+}
+
+// Allows reports whether a request costing up to estimate fits in the budget and, if it
+// does, reserves estimate until the request is settled with Add
+func (b *Budget) Allows(estimate float64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.Limit > 0 && b.spent+b.reserved+estimate > b.Limit {
+		return false
+	}
+	b.reserved += estimate
+	return true
+}
+
+// Add settles a request Allows reserved estimate for with its actual cost, 0 when it failed
+func (b *Budget) Add(estimate, cost float64) {
+	b.mu.Lock()
+	b.reserved -= estimate
+	if b.reserved < 0 {
+		b.reserved = 0
+	}
+	b.spent += cost
+	b.mu.Unlock()
+}
+
+func (b *Budget) exceeded() error {
+	return fmt.Errorf("%w: spent $%.4f of $%.2f", ErrBudgetExceeded, b.Spent(), b.Limit)
+}
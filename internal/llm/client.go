@@ -0,0 +1,240 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"lister/internal/config"
+	"lister/internal/logger"
+)
+
+// Client routes completions to a provider based on the model name.
+//
+// Models may name their provider explicitly ("openai:gpt-4o-mini", "anthropic:claude-3-5-haiku-latest",
+// "openrouter:google/gemini-flash-1.5", "mock"). Otherwise the provider is inferred: gpt-* and o1/o3/o4
+// models go to OpenAI, claude-* to Anthropic and vendor/model names to OpenRouter. When the inferred
+// provider has no API key the model is sent through OpenRouter if that is configured.
+type Client struct {
+	providers    map[string]Provider
+	fallback     []string // configured providers in preference order, used for unrecognised models
+	defaultModel string
+	prices       PriceTable
+	budgetModel  string // cheaper model used instead of refusing requests over budget
+	logger       *logger.Logger
+}
+
+// NewClient builds a client from explicit providers, preferred first. The mock provider is
+// always available under the "mock" model.
+func NewClient(defaultModel string, log *logger.Logger, providers ...Provider) *Client {
+	c := &Client{
+		providers:    map[string]Provider{ProviderMock: NewMock()},
+		defaultModel: defaultModel,
+		prices:       DefaultPrices(),
+		logger:       log,
+	}
+	for _, p := range providers {
+		c.providers[p.Name()] = p
+		c.fallback = append(c.fallback, p.Name())
+	}
+	return c
+}
+
+// NewFromConfig configures every provider that has an API key. LLM_PROVIDER picks the preferred
+// provider; "mock" sends every request to the offline mock.
+func NewFromConfig(cfg *config.Config, log *logger.Logger) *Client {
+	var c *Client
+	if cfg.LLMProvider == ProviderMock {
+		c = NewClient("mock", log, NewMock())
+	} else {
+		c = NewClient(cfg.LLMDefaultModel, log, configuredProviders(cfg)...)
+	}
+
+	prices, err := LoadPrices(cfg.LLMPriceTable)
+	if err != nil {
+		log.Error("Using default LLM prices: %v", err)
+	}
+	c.prices = prices
+	c.budgetModel = cfg.LLMBudgetModel
+	return c
+}
+
+func configuredProviders(cfg *config.Config) []Provider {
+	base := Options{
+		Timeout:    time.Duration(cfg.LLMTimeoutSeconds) * time.Second,
+		MaxRetries: cfg.LLMMaxRetries,
+	}
+
+	available := map[string]Provider{}
+	if cfg.OpenRouterAPIKey != "" {
+		options := base
+		options.APIKey = cfg.OpenRouterAPIKey
+		options.BaseURL = cfg.OpenRouterBaseURL
+		available[ProviderOpenRouter] = NewOpenRouter(options, cfg.OpenRouterReferer, "Product Lister")
+	}
+	if cfg.OpenAIAPIKey != "" {
+		options := base
+		options.APIKey = cfg.OpenAIAPIKey
+		options.BaseURL = cfg.OpenAIBaseURL
+		available[ProviderOpenAI] = NewOpenAI(options)
+	}
+	if cfg.AnthropicAPIKey != "" {
+		options := base
+		options.APIKey = cfg.AnthropicAPIKey
+		options.BaseURL = cfg.AnthropicBaseURL
+		available[ProviderAnthropic] = NewAnthropic(options)
+	}
+
+	order := []string{ProviderOpenRouter, ProviderOpenAI, ProviderAnthropic}
+	if cfg.LLMProvider != "" {
+		order = append([]string{cfg.LLMProvider}, order...)
+	}
+
+	var providers []Provider
+	seen := map[string]bool{}
+	for _, name := range order {
+		if p, ok := available[name]; ok && !seen[name] {
+			providers = append(providers, p)
+			seen[name] = true
+		}
+	}
+
+	return providers
+}
+
+// Prices returns the price table used to cost completions
+func (c *Client) Prices() PriceTable {
+	return c.prices
+}
+
+// Configured reports whether any real provider is available
+func (c *Client) Configured() bool {
+	return len(c.fallback) > 0
+}
+
+// Resolve returns the provider and the provider-specific model name for a model
+func (c *Client) Resolve(model string) (Provider, string, error) {
+	if model == "" {
+		model = c.defaultModel
+	}
+	if model == "" {
+		if len(c.fallback) == 0 {
+			return nil, "", ErrNotConfigured
+		}
+		p := c.providers[c.fallback[0]]
+		return p, p.DefaultModel(), nil
+	}
+
+	// Explicit provider prefix
+	if name, rest, ok := strings.Cut(model, ":"); ok {
+		if p, known := c.providers[name]; known {
+			return p, rest, nil
+		}
+		if isProviderName(name) {
+			return nil, "", fmt.Errorf("%w: %s provider has no API key", ErrNotConfigured, name)
+		}
+	}
+	if model == ProviderMock {
+		return c.providers[ProviderMock], model, nil
+	}
+	// The offline client answers everything
+	if len(c.fallback) == 1 && c.fallback[0] == ProviderMock {
+		return c.providers[ProviderMock], model, nil
+	}
+
+	vendor := inferProvider(model)
+	if p, ok := c.providers[vendor]; ok {
+		return p, model, nil
+	}
+
+	// Reach OpenAI and Anthropic models through OpenRouter when they have no key of their own
+	if p, ok := c.providers[ProviderOpenRouter]; ok {
+		switch vendor {
+		case ProviderOpenAI:
+			return p, "openai/" + model, nil
+		case ProviderAnthropic:
+			return p, "anthropic/" + model, nil
+		}
+	}
+
+	if vendor != "" {
+		return nil, "", fmt.Errorf("%w for model %q", ErrNotConfigured, model)
+	}
+	if len(c.fallback) == 0 {
+		return nil, "", ErrNotConfigured
+	}
+	return c.providers[c.fallback[0]], model, nil
+}
+
+// Complete resolves req.Model and sends the request to its provider. With a budget, a request
+// whose estimated cost does not fit is sent to the budget model instead, or refused with
+// ErrBudgetExceeded.
+func (c *Client) Complete(ctx context.Context, req Request) (*Response, error) {
+	provider, model, err := c.Resolve(req.Model)
+	if err != nil {
+		return nil, err
+	}
+
+	requested := ""
+	estimate := c.prices.Estimate(model, req)
+	if req.Budget != nil && !req.Budget.Allows(estimate) {
+		if c.budgetModel == "" {
+			return nil, req.Budget.exceeded()
+		}
+		cheaper, cheaperModel, err := c.Resolve(c.budgetModel)
+		if err != nil {
+			return nil, req.Budget.exceeded()
+		}
+		estimate = c.prices.Estimate(cheaperModel, req)
+		if !req.Budget.Allows(estimate) {
+			return nil, req.Budget.exceeded()
+		}
+		c.logger.Info("LLM budget nearly spent, downgrading %s to %s", model, cheaperModel)
+		requested = model
+		provider, model = cheaper, cheaperModel
+	}
+	req.Model = model
+
+	started := time.Now()
+	resp, err := provider.Complete(ctx, req)
+	if err != nil {
+		if req.Budget != nil {
+			req.Budget.Add(estimate, 0)
+		}
+		c.logger.Error("LLM completion failed (%s, model %s): %v", provider.Name(), model, err)
+		return nil, err
+	}
+
+	resp.RequestedModel = requested
+	resp.Cost = c.prices.Cost(model, resp.Usage)
+	if req.Budget != nil {
+		req.Budget.Add(estimate, resp.Cost)
+	}
+
+	c.logger.Debug("LLM completion (%s, model %s) took %s, %d+%d tokens, $%.6f", provider.Name(), resp.Model,
+		time.Since(started), resp.Usage.PromptTokens, resp.Usage.CompletionTokens, resp.Cost)
+	return resp, nil
+}
+
+func inferProvider(model string) string {
+	switch {
+	case strings.Contains(model, "/"):
+		return ProviderOpenRouter
+	case strings.HasPrefix(model, "claude"):
+		return ProviderAnthropic
+	case strings.HasPrefix(model, "gpt-"), strings.HasPrefix(model, "chatgpt-"),
+		strings.HasPrefix(model, "o1"), strings.HasPrefix(model, "o3"), strings.HasPrefix(model, "o4"):
+		return ProviderOpenAI
+	default:
+		return ""
+	}
+}
+
+func isProviderName(name string) bool {
+	switch name {
+	case ProviderOpenAI, ProviderAnthropic, ProviderOpenRouter, ProviderMock:
+		return true
+	}
+	return false
+}
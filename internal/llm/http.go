@@ -0,0 +1,105 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// httpTransport posts JSON to a provider and retries transient failures
+type httpTransport struct {
+	provider string
+	options  Options
+	client   *http.Client
+}
+
+func newHTTPTransport(provider string, options Options) *httpTransport {
+	return &httpTransport{
+		provider: provider,
+		options:  options,
+		client:   &http.Client{Timeout: options.Timeout},
+	}
+}
+
+// post sends body to path and decodes a 2xx response into out. parseError extracts the
+// provider's error message from a failed response body.
+func (t *httpTransport) post(ctx context.Context, path string, headers map[string]string, body, out interface{}, parseError func([]byte) string) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	backoff := t.options.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		retryAfter, retryable, err := t.attempt(ctx, path, headers, data, out, parseError)
+		if err == nil {
+			return nil
+		}
+
+		if apiErr, ok := err.(*APIError); ok {
+			retryable = apiErr.Retryable()
+		}
+		if !retryable || attempt >= t.options.MaxRetries || ctx.Err() != nil {
+			return err
+		}
+
+		wait := backoff
+		if retryAfter > wait {
+			wait = retryAfter
+		}
+		backoff *= 2
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// attempt sends one request. It reports whether a failure is retryable: transport failures
+// are, as are the provider errors APIError.Retryable allows; anything else, such as a 2xx
+// response that does not decode, would fail the same way again.
+func (t *httpTransport) attempt(ctx context.Context, path string, headers map[string]string, data []byte, out interface{}, parseError func([]byte) string) (time.Duration, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", t.options.BaseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return 0, true, fmt.Errorf("%s request failed: %w", t.provider, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, true, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message := parseError(respBody)
+		if message == "" {
+			message = string(respBody)
+		}
+		var retryAfter time.Duration
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+		return retryAfter, false, &APIError{Provider: t.provider, StatusCode: resp.StatusCode, Message: message}
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return 0, false, fmt.Errorf("failed to parse %s response: %w", t.provider, err)
+	}
+	return 0, false, nil
+}
@@ -0,0 +1,78 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// MockProvider answers without network access. The same request always produces the same
+// response, which keeps local development and offline runs reproducible.
+type MockProvider struct {
+	// Respond overrides the generated content when set
+	Respond func(req Request) string
+}
+
+func NewMock() *MockProvider {
+	return &MockProvider{}
+}
+
+func (p *MockProvider) Name() string {
+	return ProviderMock
+}
+
+func (p *MockProvider) DefaultModel() string {
+	return "mock"
+}
+
+func (p *MockProvider) Complete(ctx context.Context, req Request) (*Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if req.Model == "" {
+		req.Model = p.DefaultModel()
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString(req.System)
+	for _, m := range req.Messages {
+		prompt.WriteString(m.Role)
+		prompt.WriteString(m.Content)
+	}
+	sum := sha256.Sum256([]byte(prompt.String()))
+	digest := hex.EncodeToString(sum[:])[:12]
+
+	var content string
+	switch {
+	case p.Respond != nil:
+		content = p.Respond(req)
+	case req.Schema != nil:
+		b, _ := json.Marshal(req.Schema.Example())
+		content = string(b)
+	case req.JSON:
+		content = fmt.Sprintf(`{"mock":true,"digest":"%s"}`, digest)
+	default:
+		content = fmt.Sprintf("Mock completion %s", digest)
+	}
+
+	promptTokens := estimateTokens(prompt.String())
+	completionTokens := estimateTokens(content)
+	return &Response{
+		Content:  content,
+		Provider: ProviderMock,
+		Model:    req.Model,
+		Usage: Usage{
+			PromptTokens:     promptTokens,
+			CompletionTokens: completionTokens,
+			TotalTokens:      promptTokens + completionTokens,
+		},
+	}, nil
+}
+
+// estimateTokens approximates a token count at four characters per token
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
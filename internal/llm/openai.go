@@ -0,0 +1,156 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// OpenAIProvider talks to the OpenAI chat completions API. OpenRouter speaks the same
+// protocol and reuses it (see NewOpenRouter).
+type OpenAIProvider struct {
+	name      string
+	options   Options
+	headers   map[string]string
+	transport *httpTransport
+}
+
+func NewOpenAI(options Options) *OpenAIProvider {
+	options = options.withDefaults("https://api.openai.com/v1", "gpt-3.5-turbo")
+	return &OpenAIProvider{
+		name:      ProviderOpenAI,
+		options:   options,
+		headers:   map[string]string{"Authorization": "Bearer " + options.APIKey},
+		transport: newHTTPTransport(ProviderOpenAI, options),
+	}
+}
+
+type openAIRequest struct {
+	Model          string                 `json:"model"`
+	Messages       []Message              `json:"messages"`
+	MaxTokens      int                    `json:"max_tokens,omitempty"`
+	Temperature    *float64               `json:"temperature,omitempty"` // nil for reasoning models, which only accept the default
+	TopP           float64                `json:"top_p,omitempty"`
+	ResponseFormat map[string]interface{} `json:"response_format,omitempty"`
+}
+
+type openAIResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message Message `json:"message"`
+	} `json:"choices"`
+	Usage Usage `json:"usage"`
+	// OpenRouter reports some upstream failures with a 200 status
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func (p *OpenAIProvider) Name() string {
+	return p.name
+}
+
+func (p *OpenAIProvider) DefaultModel() string {
+	return p.options.DefaultModel
+}
+
+func (p *OpenAIProvider) Complete(ctx context.Context, req Request) (*Response, error) {
+	if req.Model == "" {
+		req.Model = p.options.DefaultModel
+	}
+
+	// OpenRouter models vary in json_schema support, so only OpenAI models known to
+	// support structured outputs get it; others get the schema in the prompt
+	jsonSchema := req.Schema != nil && p.name == ProviderOpenAI && supportsJSONSchema(req.Model)
+	system := req.System
+	if req.Schema != nil && !jsonSchema {
+		system = schemaInstruction(system, req.Schema)
+	}
+
+	messages := make([]Message, 0, len(req.Messages)+1)
+	if system != "" {
+		messages = append(messages, Message{Role: "system", Content: system})
+	}
+	messages = append(messages, req.Messages...)
+
+	body := openAIRequest{
+		Model:     req.Model,
+		Messages:  messages,
+		MaxTokens: req.MaxTokens,
+		TopP:      req.TopP,
+	}
+	if !reasoningModel(req.Model) {
+		body.Temperature = &req.Temperature
+	}
+	switch {
+	case jsonSchema:
+		body.ResponseFormat = map[string]interface{}{
+			"type": "json_schema",
+			"json_schema": map[string]interface{}{
+				"name":   schemaName(req.Schema),
+				"schema": req.Schema,
+			},
+		}
+	case req.JSON || (req.Schema != nil && p.name == ProviderOpenAI):
+		body.ResponseFormat = map[string]interface{}{"type": "json_object"}
+	}
+
+	var resp openAIResponse
+	if err := p.transport.post(ctx, "/chat/completions", p.headers, body, &resp, parseOpenAIError); err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, &APIError{Provider: p.name, StatusCode: 200, Message: resp.Error.Message}
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no response from %s", p.name)
+	}
+
+	model := resp.Model
+	if model == "" {
+		model = req.Model
+	}
+	return &Response{
+		Content:  strings.TrimSpace(resp.Choices[0].Message.Content),
+		Provider: p.name,
+		Model:    model,
+		Usage:    resp.Usage,
+	}, nil
+}
+
+// supportsJSONSchema reports whether an OpenAI model accepts a json_schema response format.
+// Structured outputs came with gpt-4o-2024-08-06, gpt-4o-mini and the o1 release; older
+// models such as gpt-3.5-turbo and gpt-4-turbo reject it with a 400.
+func supportsJSONSchema(model string) bool {
+	model = strings.ToLower(strings.TrimPrefix(model, "openai/"))
+	switch {
+	case model == "gpt-4o-2024-05-13", strings.HasPrefix(model, "o1-mini"), strings.HasPrefix(model, "o1-preview"):
+		return false
+	case model == "gpt-4o", model == "o1":
+		return true
+	}
+	for _, prefix := range []string{"gpt-4o-", "chatgpt-4o", "gpt-4.1", "gpt-4.5", "gpt-5", "o1-", "o3", "o4"} {
+		if strings.HasPrefix(model, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// reasoningModel reports whether an OpenAI model is an o-series reasoning model, which
+// rejects a temperature
+func reasoningModel(model string) bool {
+	model = strings.ToLower(strings.TrimPrefix(model, "openai/"))
+	return len(model) > 1 && model[0] == 'o' && model[1] >= '1' && model[1] <= '9'
+}
+
+func parseOpenAIError(body []byte) string {
+	var resp struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	json.Unmarshal(body, &resp)
+	return resp.Error.Message
+}
@@ -0,0 +1,22 @@
+package llm
+
+// NewOpenRouter returns a provider for OpenRouter, which routes "vendor/model" names to
+// many upstream vendors through an OpenAI compatible API.
+func NewOpenRouter(options Options, referer, title string) *OpenAIProvider {
+	options = options.withDefaults("https://openrouter.ai/api/v1", "meta-llama/llama-3.3-70b-instruct:free")
+
+	headers := map[string]string{"Authorization": "Bearer " + options.APIKey}
+	if referer != "" {
+		headers["HTTP-Referer"] = referer
+	}
+	if title != "" {
+		headers["X-Title"] = title
+	}
+
+	return &OpenAIProvider{
+		name:      ProviderOpenRouter,
+		options:   options,
+		headers:   headers,
+		transport: newHTTPTransport(ProviderOpenRouter, options),
+	}
+}
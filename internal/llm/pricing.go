@@ -0,0 +1,135 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Price is the cost of a model in USD per million tokens
+type Price struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// PriceTable maps model name prefixes to prices. Lookups ignore provider prefixes
+// ("openai:", "anthropic/"), case and the "." / "-" difference between OpenRouter and
+// vendor model names, and pick the longest matching prefix.
+type PriceTable map[string]Price
+
+// defaultPriceKey prices models that match no entry
+const defaultPriceKey = "default"
+
+// DefaultPrices are list prices at the time of writing. Override or extend them with
+// LLM_PRICE_TABLE.
+func DefaultPrices() PriceTable {
+	return PriceTable{
+		"gpt-4o-mini":            {Input: 0.15, Output: 0.60},
+		"gpt-4o":                 {Input: 2.50, Output: 10.00},
+		"gpt-4-turbo":            {Input: 10.00, Output: 30.00},
+		"gpt-4-vision":           {Input: 10.00, Output: 30.00},
+		"gpt-4":                  {Input: 30.00, Output: 60.00},
+		"gpt-3.5-turbo":          {Input: 0.50, Output: 1.50},
+		"o1-mini":                {Input: 3.00, Output: 12.00},
+		"o1":                     {Input: 15.00, Output: 60.00},
+		"o3-mini":                {Input: 1.10, Output: 4.40},
+		"claude-3-5-sonnet":      {Input: 3.00, Output: 15.00},
+		"claude-3-5-haiku":       {Input: 0.80, Output: 4.00},
+		"claude-3-opus":          {Input: 15.00, Output: 75.00},
+		"claude-3-sonnet":        {Input: 3.00, Output: 15.00},
+		"claude-3-haiku":         {Input: 0.25, Output: 1.25},
+		"claude-3":               {Input: 15.00, Output: 75.00},
+		"gemini-flash-1.5":       {Input: 0.075, Output: 0.30},
+		"llama-3.3-70b-instruct": {Input: 0.12, Output: 0.30},
+		"llama-3.1-8b-instruct":  {Input: 0.02, Output: 0.05},
+		"mock":                   {Input: 0, Output: 0},
+		defaultPriceKey:          {Input: 2.00, Output: 2.00},
+	}.normalized()
+}
+
+// LoadPrices returns the default prices merged with overrides. overrides is either inline
+// JSON or the path of a JSON file, e.g. {"gpt-4o-mini": {"input": 0.15, "output": 0.6}}.
+func LoadPrices(overrides string) (PriceTable, error) {
+	prices := DefaultPrices()
+	overrides = strings.TrimSpace(overrides)
+	if overrides == "" {
+		return prices, nil
+	}
+
+	data := []byte(overrides)
+	if !strings.HasPrefix(overrides, "{") {
+		var err error
+		if data, err = os.ReadFile(overrides); err != nil {
+			return prices, fmt.Errorf("failed to read price table: %w", err)
+		}
+	}
+
+	var custom PriceTable
+	if err := json.Unmarshal(data, &custom); err != nil {
+		return prices, fmt.Errorf("invalid price table: %w", err)
+	}
+	for model, price := range custom.normalized() {
+		prices[model] = price
+	}
+	return prices, nil
+}
+
+// Lookup returns the price of a model
+func (t PriceTable) Lookup(model string) Price {
+	model = normalizeModel(model)
+	// OpenRouter's free variants cost nothing
+	if strings.HasSuffix(model, ":free") || model == ProviderMock {
+		return Price{}
+	}
+
+	best := ""
+	for prefix := range t {
+		if prefix != defaultPriceKey && strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return t[defaultPriceKey]
+	}
+	return t[best]
+}
+
+// Cost returns the USD cost of the tokens used
+func (t PriceTable) Cost(model string, usage Usage) float64 {
+	price := t.Lookup(model)
+	return (float64(usage.PromptTokens)*price.Input + float64(usage.CompletionTokens)*price.Output) / 1e6
+}
+
+// Estimate is an upper bound on the cost of a request, assuming the full completion
+// budget is used. Prompt tokens are approximated from the prompt length.
+func (t PriceTable) Estimate(model string, req Request) float64 {
+	promptChars := len(req.System)
+	for _, m := range req.Messages {
+		promptChars += len(m.Content)
+	}
+	completion := req.MaxTokens
+	if completion == 0 {
+		completion = 1024
+	}
+	return t.Cost(model, Usage{PromptTokens: (promptChars + 3) / 4, CompletionTokens: completion})
+}
+
+func (t PriceTable) normalized() PriceTable {
+	out := make(PriceTable, len(t))
+	for model, price := range t {
+		out[normalizeModel(model)] = price
+	}
+	return out
+}
+
+func normalizeModel(model string) string {
+	model = strings.ToLower(model)
+	if name, rest, ok := strings.Cut(model, ":"); ok && isProviderName(name) {
+		model = rest
+	}
+	if i := strings.LastIndex(model, "/"); i >= 0 {
+		model = model[i+1:]
+	}
+	return strings.ReplaceAll(model, ".", "-")
+}
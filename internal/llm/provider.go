@@ -0,0 +1,137 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Provider names
+const (
+	ProviderOpenAI     = "openai"
+	ProviderAnthropic  = "anthropic"
+	ProviderOpenRouter = "openrouter"
+	ProviderMock       = "mock"
+)
+
+// ErrNotConfigured is returned when no provider can serve the requested model
+var ErrNotConfigured = errors.New("no LLM provider configured")
+
+// Provider is a chat completion backend
+type Provider interface {
+	Name() string
+	// DefaultModel is used when a request does not name a model
+	DefaultModel() string
+	Complete(ctx context.Context, req Request) (*Response, error)
+}
+
+// Message is a single chat message
+type Message struct {
+	Role    string `json:"role"` // user or assistant
+	Content string `json:"content"`
+}
+
+// Request is a provider independent completion request
+type Request struct {
+	Model       string
+	System      string
+	Messages    []Message
+	MaxTokens   int
+	Temperature float64
+	TopP        float64
+	// JSON asks providers that support it to return a single JSON object
+	JSON bool
+	// Schema, when set, describes the JSON document expected back. OpenAI enforces it with
+	// structured outputs; other providers receive it in the system prompt.
+	Schema *Schema
+	// Budget, when set, refuses or downgrades requests that would exceed it and is
+	// charged with the cost of the completion
+	Budget *Budget
+}
+
+// Usage reports the tokens billed for a completion
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// Response is a provider independent completion result
+type Response struct {
+	Content  string
+	Provider string
+	Model    string
+	Usage    Usage
+	Cost     float64 // USD, from the client's price table
+	// RequestedModel is set when the budget forced a cheaper model than the one requested
+	RequestedModel string
+}
+
+// Options configure a provider's transport
+type Options struct {
+	APIKey       string
+	BaseURL      string
+	DefaultModel string
+	Timeout      time.Duration
+	MaxRetries   int           // retries after the first attempt on 429, 5xx and network errors
+	RetryBackoff time.Duration // initial backoff, doubled on every retry
+}
+
+func (o Options) withDefaults(baseURL, model string) Options {
+	if o.BaseURL == "" {
+		o.BaseURL = baseURL
+	}
+	if o.DefaultModel == "" {
+		o.DefaultModel = model
+	}
+	if o.Timeout == 0 {
+		o.Timeout = 30 * time.Second
+	}
+	if o.MaxRetries < 0 {
+		o.MaxRetries = 0
+	}
+	if o.RetryBackoff == 0 {
+		o.RetryBackoff = 500 * time.Millisecond
+	}
+	return o
+}
+
+// APIError is a non-2xx response from a provider
+type APIError struct {
+	Provider   string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s API error %d: %s", e.Provider, e.StatusCode, e.Message)
+}
+
+// Retryable reports whether the request may succeed when repeated
+func (e *APIError) Retryable() bool {
+	return e.StatusCode == 429 || e.StatusCode >= 500
+}
+
+// Prompt builds a request with a single user message
+func Prompt(model, prompt string, maxTokens int, temperature float64) Request {
+	return Request{
+		Model:       model,
+		Messages:    []Message{{Role: "user", Content: prompt}},
+		MaxTokens:   maxTokens,
+		Temperature: temperature,
+	}
+}
+
+// Total adds up the usage and cost of several completions
+func Total(responses []*Response) (Usage, float64) {
+	var usage Usage
+	var cost float64
+	for _, r := range responses {
+		usage.PromptTokens += r.Usage.PromptTokens
+		usage.CompletionTokens += r.Usage.CompletionTokens
+		usage.TotalTokens += r.Usage.TotalTokens
+		cost += r.Cost
+	}
+	return usage, cost
+}
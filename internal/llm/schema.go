@@ -0,0 +1,324 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Schema is the subset of JSON Schema used to describe structured output. It marshals to
+// a JSON Schema document that is sent to providers with structured output support.
+type Schema struct {
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        string             `json:"type"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Minimum     *float64           `json:"minimum,omitempty"`
+	Maximum     *float64           `json:"maximum,omitempty"`
+	MaxLength   int                `json:"maxLength,omitempty"`
+	MinItems    int                `json:"minItems,omitempty"`
+}
+
+// Schema types
+const (
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeString  = "string"
+	TypeNumber  = "number"
+	TypeInteger = "integer"
+	TypeBoolean = "boolean"
+)
+
+// Range returns a pointer for Minimum and Maximum
+func Range(v float64) *float64 {
+	return &v
+}
+
+// String returns the schema as JSON, for prompts of providers without schema support
+func (s *Schema) String() string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// schemaInstruction appends the schema to a system prompt for providers that can't
+// enforce it
+func schemaInstruction(system string, schema *Schema) string {
+	return strings.TrimSpace(system + "\nRespond with a single JSON document matching this JSON schema and nothing else:\n" + schema.String())
+}
+
+// schemaName is the identifier OpenAI requires for a response schema
+func schemaName(schema *Schema) string {
+	name := strings.Trim(nonIdentifier.ReplaceAllString(strings.ToLower(schema.Title), "_"), "_")
+	if name == "" {
+		return "response"
+	}
+	return name
+}
+
+// Parse extracts the JSON document from a model response, repairs common deviations
+// (code fences, trailing commas, objects where a string is expected, numbers as strings)
+// and validates the result against the schema
+func (s *Schema) Parse(content string) (interface{}, error) {
+	raw := ExtractJSON(content)
+	if raw == "" {
+		return nil, fmt.Errorf("response contains no JSON")
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		if err := json.Unmarshal([]byte(trailingCommas.ReplaceAllString(raw, "$1")), &value); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %v", err)
+		}
+	}
+
+	value = s.repair(value)
+	if err := s.Validate(value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Validate reports every place where value does not match the schema
+func (s *Schema) Validate(value interface{}) error {
+	var problems []string
+	s.validate("$", value, &problems)
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func (s *Schema) validate(path string, value interface{}, problems *[]string) {
+	fail := func(format string, args ...interface{}) {
+		*problems = append(*problems, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	switch s.Type {
+	case TypeObject:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			fail("expected object, got %s", typeName(value))
+			return
+		}
+		for _, key := range s.Required {
+			if v, ok := obj[key]; !ok || v == nil {
+				fail("missing required property %q", key)
+			}
+		}
+		keys := make([]string, 0, len(s.Properties))
+		for key := range s.Properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if v, ok := obj[key]; ok && v != nil {
+				s.Properties[key].validate(path+"."+key, v, problems)
+			}
+		}
+	case TypeArray:
+		arr, ok := value.([]interface{})
+		if !ok {
+			fail("expected array, got %s", typeName(value))
+			return
+		}
+		if len(arr) < s.MinItems {
+			fail("expected at least %d items, got %d", s.MinItems, len(arr))
+		}
+		if s.Items != nil {
+			for i, item := range arr {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, problems)
+			}
+		}
+	case TypeString:
+		str, ok := value.(string)
+		if !ok {
+			fail("expected string, got %s", typeName(value))
+			return
+		}
+		if s.MaxLength > 0 && len([]rune(str)) > s.MaxLength {
+			fail("longer than %d characters", s.MaxLength)
+		}
+		if len(s.Enum) > 0 && !contains(s.Enum, str) {
+			fail("must be one of %s", strings.Join(s.Enum, ", "))
+		}
+	case TypeNumber, TypeInteger:
+		num, ok := value.(float64)
+		if !ok {
+			fail("expected %s, got %s", s.Type, typeName(value))
+			return
+		}
+		if s.Type == TypeInteger && num != math.Trunc(num) {
+			fail("expected integer, got %v", num)
+		}
+		if s.Minimum != nil && num < *s.Minimum {
+			fail("must be at least %v", *s.Minimum)
+		}
+		if s.Maximum != nil && num > *s.Maximum {
+			fail("must be at most %v", *s.Maximum)
+		}
+	case TypeBoolean:
+		if _, ok := value.(bool); !ok {
+			fail("expected boolean, got %s", typeName(value))
+		}
+	}
+}
+
+// repair coerces values the model got almost right into the shape of the schema
+func (s *Schema) repair(value interface{}) interface{} {
+	switch s.Type {
+	case TypeObject:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			// A bare array where the schema wraps a single array property
+			if arr, isArray := value.([]interface{}); isArray && len(s.Required) == 1 {
+				if prop := s.Properties[s.Required[0]]; prop != nil && prop.Type == TypeArray {
+					return map[string]interface{}{s.Required[0]: prop.repair(arr)}
+				}
+			}
+			return value
+		}
+		for key, prop := range s.Properties {
+			if v, ok := obj[key]; ok {
+				obj[key] = prop.repair(v)
+			}
+		}
+		return obj
+	case TypeArray:
+		if arr, ok := value.([]interface{}); ok && s.Items != nil {
+			for i := range arr {
+				arr[i] = s.Items.repair(arr[i])
+			}
+			return arr
+		}
+		// A comma separated list where an array of strings is expected
+		if str, ok := value.(string); ok && s.Items != nil && s.Items.Type == TypeString {
+			items := []interface{}{}
+			for _, part := range strings.Split(str, ",") {
+				if part = strings.TrimSpace(part); part != "" {
+					items = append(items, part)
+				}
+			}
+			return items
+		}
+	case TypeString:
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			if b, err := json.Marshal(value); err == nil {
+				return string(b)
+			}
+		}
+	case TypeNumber, TypeInteger:
+		if str, ok := value.(string); ok {
+			if num, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(str), "%"), 64); err == nil {
+				return num
+			}
+		}
+	case TypeBoolean:
+		if str, ok := value.(string); ok {
+			if b, err := strconv.ParseBool(strings.TrimSpace(str)); err == nil {
+				return b
+			}
+		}
+	}
+	return value
+}
+
+// Example returns a minimal document that satisfies the schema, used by the mock provider
+func (s *Schema) Example() interface{} {
+	switch s.Type {
+	case TypeObject:
+		obj := map[string]interface{}{}
+		for key, prop := range s.Properties {
+			obj[key] = prop.Example()
+		}
+		return obj
+	case TypeArray:
+		items := []interface{}{}
+		if s.Items != nil {
+			for i := 0; i < max(s.MinItems, 1); i++ {
+				items = append(items, s.Items.Example())
+			}
+		}
+		return items
+	case TypeString:
+		if len(s.Enum) > 0 {
+			return s.Enum[0]
+		}
+		return "mock"
+	case TypeNumber, TypeInteger:
+		if s.Maximum != nil {
+			return *s.Maximum
+		}
+		if s.Minimum != nil {
+			return *s.Minimum
+		}
+		return 0
+	case TypeBoolean:
+		return false
+	}
+	return nil
+}
+
+var (
+	codeFence      = regexp.MustCompile("(?s)```(?:json)?\\s*(.*?)```")
+	trailingCommas = regexp.MustCompile(`,\s*([}\]])`)
+	nonIdentifier  = regexp.MustCompile(`[^a-z0-9_-]+`)
+)
+
+// ExtractJSON returns the JSON object or array in a response, dropping markdown code
+// fences and any prose around it
+func ExtractJSON(content string) string {
+	content = strings.TrimSpace(content)
+	if m := codeFence.FindStringSubmatch(content); m != nil {
+		content = strings.TrimSpace(m[1])
+	}
+
+	start := strings.IndexAny(content, "{[")
+	if start < 0 {
+		return ""
+	}
+	closing := "}"
+	if content[start] == '[' {
+		closing = "]"
+	}
+	end := strings.LastIndex(content, closing)
+	if end < start {
+		return ""
+	}
+	return content[start : end+1]
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return TypeObject
+	case []interface{}:
+		return TypeArray
+	case string:
+		return TypeString
+	case float64:
+		return TypeNumber
+	case bool:
+		return TypeBoolean
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// DefaultStructuredAttempts is how many completions a structured request may take when the
+// caller has no retry setting
+const DefaultStructuredAttempts = 3
+
+// StructuredError is returned when no attempt produced JSON matching the schema. Raw is
+// the last response, so the failure can be inspected later.
+type StructuredError struct {
+	Attempts int
+	Err      error
+	Raw      string
+}
+
+func (e *StructuredError) Error() string {
+	return fmt.Sprintf("invalid structured response after %d attempt(s): %v\nraw response: %s", e.Attempts, e.Err, e.Raw)
+}
+
+func (e *StructuredError) Unwrap() error {
+	return e.Err
+}
+
+// CompleteStructured sends req with schema and decodes the validated result into out.
+// A response that doesn't match the schema is sent back to the model with the validation
+// errors so it can correct itself, up to attempts completions in total. complete performs
+// a single completion, which lets callers charge budgets and record usage per attempt;
+// every response is returned, including those of failed attempts.
+func CompleteStructured(req Request, schema *Schema, out interface{}, attempts int, complete func(Request) (*Response, error)) ([]*Response, error) {
+	if attempts < 1 {
+		attempts = 1
+	}
+	req.JSON = true
+	req.Schema = schema
+	req.Messages = append([]Message(nil), req.Messages...)
+
+	var responses []*Response
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		resp, err := complete(req)
+		if err != nil {
+			return responses, err
+		}
+		responses = append(responses, resp)
+
+		value, err := schema.Parse(resp.Content)
+		if err == nil {
+			b, _ := json.Marshal(value)
+			if err = json.Unmarshal(b, out); err == nil {
+				return responses, nil
+			}
+		}
+		lastErr = err
+
+		req.Messages = append(req.Messages,
+			Message{Role: "assistant", Content: resp.Content},
+			Message{Role: "user", Content: fmt.Sprintf(
+				"That response does not match the required JSON schema: %v\nReply with only the corrected JSON document.", err)},
+		)
+	}
+
+	raw := ""
+	if len(responses) > 0 {
+		raw = responses[len(responses)-1].Content
+	}
+	return responses, &StructuredError{Attempts: attempts, Err: lastErr, Raw: raw}
+}
+
+// CompleteStructured runs CompleteStructured through the client
+func (c *Client) CompleteStructured(ctx context.Context, req Request, schema *Schema, out interface{}, attempts int) ([]*Response, error) {
+	return CompleteStructured(req, schema, out, attempts, func(r Request) (*Response, error) {
+		return c.Complete(ctx, r)
+	})
+}
+
+// Combine merges the responses of a multi-attempt completion into one carrying the last
+// content and model with the total usage and cost
+func Combine(responses []*Response) *Response {
+	if len(responses) == 0 {
+		return nil
+	}
+	combined := *responses[len(responses)-1]
+	combined.Usage, combined.Cost = Total(responses)
+	return &combined
+}
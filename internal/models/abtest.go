@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -25,8 +26,22 @@ type ABTest struct {
 	CreatedAt   time.Time    `json:"created_at"`
 	UpdatedAt   time.Time    `json:"updated_at"`
 
+	// Evaluation: the metric compared, how often the variants rotate in feeds, and
+	// when a difference is significant enough to declare and promote a winner
+	Metric          string  `json:"metric" gorm:"default:ctr"`
+	RotationHours   int     `json:"rotation_hours" gorm:"default:24"`
+	MinSampleSize   int     `json:"min_sample_size" gorm:"default:100"`
+	ConfidenceLevel float64 `json:"confidence_level" gorm:"type:decimal(5,4);default:0.95"`
+	AutoPromote     bool    `json:"auto_promote" gorm:"default:true"`
+
+	// Pauses are the periods the test was paused, skipped by the rotation. The test is
+	// decided once both variants reach the planned sample; Result is the comparison then.
+	Pauses    json.RawMessage `json:"pauses" gorm:"type:jsonb;default:'[]'"`
+	DecidedAt *time.Time      `json:"decided_at"`
+	Result    json.RawMessage `json:"result,omitempty" gorm:"type:jsonb"`
+
 	// Relations
-	Product  Product     `json:"product" gorm:"foreignKey:ProductID"`
+	Product  *Product    `json:"product,omitempty" gorm:"foreignKey:ProductID"`
 	VariantA FeedVariant `json:"variant_a" gorm:"foreignKey:VariantAID"`
 	VariantB FeedVariant `json:"variant_b" gorm:"foreignKey:VariantBID"`
 }
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CategoryMapping maps one of an organization's store product types or collections to a
+// node of a channel taxonomy. Feeds use the product type's mapping first, then the first
+// mapped collection.
+type CategoryMapping struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_category_mappings_source" json:"organization_id"`
+	SourceType     string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_category_mappings_source" json:"source_type"`
+	SourceValue    string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_category_mappings_source" json:"source_value"`
+	Taxonomy       string    `gorm:"type:varchar(20);not null;default:'google';uniqueIndex:idx_category_mappings_source" json:"taxonomy"`
+	CategoryID     int       `gorm:"not null" json:"category_id"`
+	CategoryPath   string    `gorm:"type:text;not null" json:"category_path"`
+	CreatedAt      time.Time `gorm:"type:timestamp with time zone;default:now()" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"type:timestamp with time zone;default:now()" json:"updated_at"`
+}
+
+// TableName specifies the table name for CategoryMapping
+func (CategoryMapping) TableName() string {
+	return "category_mappings"
+}
+
+// CategoryMappingRequest maps a product type or collection to a taxonomy node, given by
+// ID or path
+type CategoryMappingRequest struct {
+	SourceType  string `json:"source_type" binding:"required"`
+	SourceValue string `json:"source_value" binding:"required"`
+	Taxonomy    string `json:"taxonomy"`
+	Category    string `json:"category" binding:"required"`
+}
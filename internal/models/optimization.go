@@ -18,6 +18,10 @@ const (
 	OptimizationTypeCategory    OptimizationType = "category"
 	OptimizationTypeImage       OptimizationType = "image"
 	OptimizationTypeBulk        OptimizationType = "bulk"
+	OptimizationTypeSEO         OptimizationType = "seo"
+	OptimizationTypeGTIN        OptimizationType = "gtin"
+	OptimizationTypeTranslation OptimizationType = "translation"
+	OptimizationTypeAttribute   OptimizationType = "attribute"
 )
 
 // OptimizationStatus represents the status of an optimization
@@ -28,6 +32,7 @@ const (
 	OptimizationStatusApplied  OptimizationStatus = "applied"
 	OptimizationStatusRejected OptimizationStatus = "rejected"
 	OptimizationStatusFailed   OptimizationStatus = "failed"
+	OptimizationStatusReverted OptimizationStatus = "reverted"
 )
 
 // JSONB is a custom type for PostgreSQL JSONB columns
@@ -65,15 +70,28 @@ type OptimizationHistory struct {
 	Status                OptimizationStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
 	Score                 *int               `gorm:"type:integer" json:"score,omitempty"`
 	ImprovementPercentage *float64           `gorm:"type:decimal(5,2)" json:"improvement_percentage,omitempty"`
-	AIModel               string             `gorm:"type:varchar(50);not null" json:"ai_model"`
+	AIModel               string             `gorm:"type:varchar(100);not null" json:"ai_model"`
 	Cost                  float64            `gorm:"type:decimal(10,4);default:0.0000" json:"cost"`
 	TokensUsed            int                `gorm:"type:integer;default:0" json:"tokens_used"`
+	PromptTemplateID      *uuid.UUID         `gorm:"type:uuid;index" json:"prompt_template_id,omitempty"`
+	PromptVersion         int                `gorm:"type:integer;default:0" json:"prompt_version"`
 	Metadata              JSONB              `gorm:"type:jsonb;default:'{}'" json:"metadata"`
 	ErrorMessage          *string            `gorm:"type:text" json:"error_message,omitempty"`
 	CreatedAt             time.Time          `gorm:"type:timestamp with time zone;default:now()" json:"created_at"`
 	UpdatedAt             time.Time          `gorm:"type:timestamp with time zone;default:now()" json:"updated_at"`
 	AppliedAt             *time.Time         `gorm:"type:timestamp with time zone" json:"applied_at,omitempty"`
 
+	// Review: SuggestedValue keeps the AI suggestion when the reviewer edited it before approving
+	SuggestedValue *string    `gorm:"type:text" json:"suggested_value,omitempty"`
+	ReviewedBy     *string    `gorm:"type:varchar(255)" json:"reviewed_by,omitempty"`
+	ReviewReason   *string    `gorm:"type:text" json:"review_reason,omitempty"`
+	ReviewedAt     *time.Time `gorm:"type:timestamp with time zone" json:"reviewed_at,omitempty"`
+
+	// Rollback: an applied optimization reverted to OriginalValue
+	RevertedAt   *time.Time `gorm:"type:timestamp with time zone" json:"reverted_at,omitempty"`
+	RevertedBy   *string    `gorm:"type:varchar(255)" json:"reverted_by,omitempty"`
+	RevertReason *string    `gorm:"type:text" json:"revert_reason,omitempty"`
+
 	// Relations
 	Product      *Product      `gorm:"foreignKey:ProductID;references:ID" json:"product,omitempty"`
 	Organization *Organization `gorm:"foreignKey:OrganizationID;references:ID" json:"organization,omitempty"`
@@ -92,13 +110,26 @@ func (o *OptimizationHistory) BeforeCreate() error {
 	return nil
 }
 
+// SetPrompt records the prompt template an optimization was generated with. Built-in
+// templates have no ID and version 0.
+func (o *OptimizationHistory) SetPrompt(t *PromptTemplate) {
+	if t == nil {
+		return
+	}
+	o.PromptVersion = t.Version
+	if t.ID != uuid.Nil {
+		id := t.ID
+		o.PromptTemplateID = &id
+	}
+}
+
 // AISettings stores AI optimization configuration per organization
 type AISettings struct {
 	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	OrganizationID uuid.UUID `gorm:"type:uuid;unique;not null" json:"organization_id"`
 
 	// General Settings
-	DefaultModel     string  `gorm:"type:varchar(50);default:'gpt-3.5-turbo'" json:"default_model"`
+	DefaultModel     string  `gorm:"type:varchar(100);default:'gpt-3.5-turbo'" json:"default_model"`
 	AutoOptimize     bool    `gorm:"default:false" json:"auto_optimize"`
 	AutoApply        bool    `gorm:"default:false" json:"auto_apply"`
 	MaxCostPerMonth  float64 `gorm:"type:decimal(10,2);default:25.00" json:"max_cost_per_month"`
@@ -120,6 +151,10 @@ type AISettings struct {
 	RequireApproval   bool `gorm:"default:true" json:"require_approval"`
 	MaxRetries        int  `gorm:"type:integer;default:3" json:"max_retries"`
 
+	// Response Cache: identical optimizations are answered from the cache for this many
+	// hours; 0 disables the cache
+	CacheTTLHours int `gorm:"type:integer;default:168" json:"cache_ttl_hours"`
+
 	// Channel Settings
 	GoogleOptimization    bool `gorm:"default:true" json:"google_optimization"`
 	FacebookOptimization  bool `gorm:"default:true" json:"facebook_optimization"`
@@ -155,6 +190,29 @@ func (s *AISettings) BeforeCreate() error {
 	return nil
 }
 
+// DefaultAICacheTTLHours is how long optimization results are reused by default
+const DefaultAICacheTTLHours = 168
+
+// DefaultAISettings returns the settings that apply until an organization saves its own
+func DefaultAISettings(organizationID uuid.UUID) *AISettings {
+	return &AISettings{
+		OrganizationID:          organizationID,
+		DefaultModel:            "gpt-3.5-turbo",
+		MaxCostPerMonth:         25.00,
+		MaxTokens:               500,
+		Temperature:             0.7,
+		TopP:                    0.9,
+		TitleOptimization:       true,
+		DescriptionOptimization: true,
+		CategoryOptimization:    true,
+		ImageOptimization:       true,
+		MinScoreThreshold:       80,
+		RequireApproval:         true,
+		MaxRetries:              3,
+		CacheTTLHours:           DefaultAICacheTTLHours,
+	}
+}
+
 // Validate validates the AI settings
 func (s *AISettings) Validate() error {
 	if s.MaxTokens < 1 || s.MaxTokens > 4000 {
@@ -172,6 +230,9 @@ func (s *AISettings) Validate() error {
 	if s.MaxRetries < 0 || s.MaxRetries > 10 {
 		return errors.New("max_retries must be between 0 and 10")
 	}
+	if s.CacheTTLHours < 0 {
+		return errors.New("cache_ttl_hours must not be negative")
+	}
 	return nil
 }
 
@@ -239,6 +300,15 @@ func (c *AICredits) DeductCredits(amount int) error {
 	return nil
 }
 
+// RefundCredits returns credits deducted for work that turned out to cost nothing
+func (c *AICredits) RefundCredits(amount int) {
+	if amount > c.CreditsUsed {
+		amount = c.CreditsUsed
+	}
+	c.CreditsRemaining += amount
+	c.CreditsUsed -= amount
+}
+
 // AddCost adds to the cost tracking
 func (c *AICredits) AddCost(cost float64) error {
 	if cost < 0 {
@@ -264,6 +334,69 @@ func (c *AICredits) Reset() {
 	c.ResetDate = now.AddDate(0, 1, 0) // Reset in 1 month
 }
 
+// AIUsage records the tokens and cost of a single AI call
+type AIUsage struct {
+	ID               uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	OrganizationID   uuid.UUID `gorm:"type:uuid;not null;index" json:"organization_id"`
+	Purpose          string    `gorm:"type:varchar(50);not null" json:"purpose"`
+	Provider         string    `gorm:"type:varchar(20);not null" json:"provider"`
+	Model            string    `gorm:"type:varchar(100);not null" json:"model"`
+	RequestedModel   *string   `gorm:"type:varchar(100)" json:"requested_model,omitempty"`
+	PromptTokens     int       `gorm:"type:integer;default:0" json:"prompt_tokens"`
+	CompletionTokens int       `gorm:"type:integer;default:0" json:"completion_tokens"`
+	TotalTokens      int       `gorm:"type:integer;default:0" json:"total_tokens"`
+	Cost             float64   `gorm:"type:decimal(12,6);default:0" json:"cost"`
+	CreatedAt        time.Time `gorm:"type:timestamp with time zone;default:now()" json:"created_at"`
+}
+
+// TableName specifies the table name for AIUsage
+func (AIUsage) TableName() string {
+	return "ai_usage"
+}
+
+// AIResponseCache stores the result of an optimization under the content address of its
+// prompt (see prompts.CacheKey), so identical requests are answered without calling the model
+type AIResponseCache struct {
+	ID               uuid.UUID        `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	OrganizationID   uuid.UUID        `gorm:"type:uuid;not null;uniqueIndex:idx_ai_response_cache_key" json:"organization_id"`
+	CacheKey         string           `gorm:"type:varchar(64);not null;uniqueIndex:idx_ai_response_cache_key" json:"cache_key"`
+	OptimizationType OptimizationType `gorm:"type:varchar(50);not null" json:"optimization_type"`
+	Model            string           `gorm:"type:varchar(100)" json:"model"`
+	PromptTemplateID *uuid.UUID       `gorm:"type:uuid" json:"prompt_template_id,omitempty"`
+	PromptVersion    int              `gorm:"type:integer;default:0" json:"prompt_version"`
+	Response         string           `gorm:"type:text;not null" json:"response"`
+	Hits             int              `gorm:"type:integer;default:0" json:"hits"`
+	CreatedAt        time.Time        `gorm:"type:timestamp with time zone;default:now()" json:"created_at"`
+	ExpiresAt        time.Time        `gorm:"type:timestamp with time zone;not null" json:"expires_at"`
+	LastHitAt        *time.Time       `gorm:"type:timestamp with time zone" json:"last_hit_at,omitempty"`
+}
+
+// TableName specifies the table name for AIResponseCache
+func (AIResponseCache) TableName() string {
+	return "ai_response_cache"
+}
+
+// History returns the optimization history entry recorded for a cache hit. It carries the
+// cached model and prompt but no cost or tokens, since the model was not called.
+func (c *AIResponseCache) History(productID uuid.UUID, originalValue string) *OptimizationHistory {
+	return &OptimizationHistory{
+		ProductID:        productID,
+		OrganizationID:   c.OrganizationID,
+		OptimizationType: c.OptimizationType,
+		OriginalValue:    originalValue,
+		OptimizedValue:   c.Response,
+		Status:           OptimizationStatusPending,
+		AIModel:          c.Model,
+		PromptTemplateID: c.PromptTemplateID,
+		PromptVersion:    c.PromptVersion,
+		Metadata: JSONB{
+			"cache_hit":       true,
+			"cache_key":       c.CacheKey,
+			"cache_stored_at": c.CreatedAt,
+		},
+	}
+}
+
 // OptimizationAnalytics represents aggregated analytics data
 type OptimizationAnalytics struct {
 	OrganizationID       uuid.UUID `json:"organization_id"`
@@ -299,6 +432,7 @@ type OptimizationRequest struct {
 	Keywords           string   `json:"keywords,omitempty"`
 	MaxLength          int      `json:"max_length,omitempty"`
 	CustomInstructions string   `json:"custom_instructions,omitempty"`
+	NoCache            bool     `json:"no_cache,omitempty"` // always call the model, ignoring cached results
 }
 
 // BulkOptimizationRequest represents a bulk optimization request
@@ -310,6 +444,8 @@ type BulkOptimizationRequest struct {
 	Tone             string            `json:"tone,omitempty"`
 	IncludeKeywords  bool              `json:"include_keywords"`
 	AutoApply        bool              `json:"auto_apply"`
+	Concurrency      int               `json:"concurrency,omitempty"`
+	NoCache          bool              `json:"no_cache,omitempty"`
 	Settings         map[string]interface{} `json:"settings,omitempty"`
 }
 
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OptimizationJobStatus is the state of a bulk optimization job
+type OptimizationJobStatus string
+
+const (
+	OptimizationJobQueued    OptimizationJobStatus = "queued"
+	OptimizationJobRunning   OptimizationJobStatus = "running"
+	OptimizationJobPaused    OptimizationJobStatus = "paused"
+	OptimizationJobCancelled OptimizationJobStatus = "cancelled"
+	OptimizationJobDone      OptimizationJobStatus = "done"
+)
+
+// OptimizationJobItemStatus is the state of one product in a bulk optimization job
+type OptimizationJobItemStatus string
+
+const (
+	OptimizationJobItemPending   OptimizationJobItemStatus = "pending"
+	OptimizationJobItemRunning   OptimizationJobItemStatus = "running"
+	OptimizationJobItemSucceeded OptimizationJobItemStatus = "succeeded"
+	OptimizationJobItemFailed    OptimizationJobItemStatus = "failed"
+	OptimizationJobItemSkipped   OptimizationJobItemStatus = "skipped"
+	OptimizationJobItemCancelled OptimizationJobItemStatus = "cancelled"
+)
+
+// Concurrency bounds for bulk optimization jobs
+const (
+	DefaultOptimizationJobConcurrency = 4
+	MaxOptimizationJobConcurrency     = 10
+)
+
+// OptimizationJob is a bulk optimization processed in the background. The runner working
+// on a job holds a lease (LockedBy, LockedUntil); a running job whose lease expired is
+// picked up again by the next runner.
+type OptimizationJob struct {
+	ID               uuid.UUID             `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	OrganizationID   uuid.UUID             `gorm:"type:uuid;not null;index" json:"organization_id"`
+	OptimizationType OptimizationType      `gorm:"type:varchar(50);not null" json:"optimization_type"`
+	Status           OptimizationJobStatus `gorm:"type:varchar(20);not null;default:'queued'" json:"status"`
+	AutoApply        bool                  `gorm:"default:false" json:"auto_apply"`
+	Concurrency      int                   `gorm:"type:integer;default:4" json:"concurrency"`
+	Options          JSONB                 `gorm:"type:jsonb;default:'{}'" json:"options"`
+
+	TotalItems     int     `gorm:"type:integer;default:0" json:"total_items"`
+	ProcessedItems int     `gorm:"type:integer;default:0" json:"processed_items"`
+	SucceededItems int     `gorm:"type:integer;default:0" json:"succeeded_items"`
+	FailedItems    int     `gorm:"type:integer;default:0" json:"failed_items"`
+	SkippedItems   int     `gorm:"type:integer;default:0" json:"skipped_items"`
+	Cost           float64 `gorm:"type:decimal(12,6);default:0" json:"cost"`
+	ErrorMessage   *string `gorm:"type:text" json:"error_message,omitempty"`
+
+	LockedBy    *string    `gorm:"type:varchar(255)" json:"-"`
+	LockedUntil *time.Time `gorm:"type:timestamp with time zone" json:"-"`
+
+	CreatedAt   time.Time  `gorm:"type:timestamp with time zone;default:now()" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"type:timestamp with time zone;default:now()" json:"updated_at"`
+	StartedAt   *time.Time `gorm:"type:timestamp with time zone" json:"started_at,omitempty"`
+	CompletedAt *time.Time `gorm:"type:timestamp with time zone" json:"completed_at,omitempty"`
+}
+
+// TableName specifies the table name for OptimizationJob
+func (OptimizationJob) TableName() string {
+	return "optimization_jobs"
+}
+
+// Progress returns the share of items processed, from 0 to 100
+func (j *OptimizationJob) Progress() float64 {
+	if j.TotalItems == 0 {
+		return 100
+	}
+	return float64(j.ProcessedItems) * 100 / float64(j.TotalItems)
+}
+
+// Finished reports whether the job will not be processed any further
+func (j *OptimizationJob) Finished() bool {
+	return j.Status == OptimizationJobDone || j.Status == OptimizationJobCancelled
+}
+
+// OptimizationJobItem is one product of a bulk optimization job. Processed items link to
+// the optimization history entry they produced.
+type OptimizationJobItem struct {
+	ID                    uuid.UUID                 `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	JobID                 uuid.UUID                 `gorm:"type:uuid;not null;index" json:"job_id"`
+	ProductID             uuid.UUID                 `gorm:"type:uuid;not null" json:"product_id"`
+	Status                OptimizationJobItemStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	OptimizationHistoryID *uuid.UUID                `gorm:"type:uuid" json:"optimization_history_id,omitempty"`
+	OptimizedValue        *string                   `gorm:"type:text" json:"optimized_value,omitempty"`
+	ErrorMessage          *string                   `gorm:"type:text" json:"error_message,omitempty"`
+	Attempts              int                       `gorm:"type:integer;default:0" json:"attempts"`
+	StartedAt             *time.Time                `gorm:"type:timestamp with time zone" json:"started_at,omitempty"`
+	CompletedAt           *time.Time                `gorm:"type:timestamp with time zone" json:"completed_at,omitempty"`
+}
+
+// TableName specifies the table name for OptimizationJobItem
+func (OptimizationJobItem) TableName() string {
+	return "optimization_job_items"
+}
@@ -19,6 +19,10 @@ type Product struct {
 	Category     *string             `json:"category"`
 	Price        float64             `json:"price" gorm:"type:decimal(10,2)"`
 	CompareAtPrice *float64          `json:"compare_at_price" gorm:"type:decimal(10,2)"`
+	SalePrice    *float64            `json:"sale_price" gorm:"type:decimal(10,2)"`
+	SaleStartsAt *time.Time          `json:"sale_starts_at"`
+	SaleEndsAt   *time.Time          `json:"sale_ends_at"`
+	Cost         *float64            `json:"cost" gorm:"type:decimal(10,2)"`
 	Currency     string              `json:"currency" gorm:"default:USD"`
 	Availability string              `json:"availability" gorm:"default:IN_STOCK"`
 	Images       []string            `json:"images" gorm:"type:jsonb"`
@@ -44,6 +48,7 @@ type ProductVariant struct {
 
 type ShippingInfo struct {
 	Weight        *float64    `json:"weight"`
+	WeightUnit    *string     `json:"weight_unit,omitempty"` // g, kg, oz or lb; feeds assume the shipping profile's unit when unset
 	Dimensions    *Dimensions `json:"dimensions"`
 	ShippingLabel *string     `json:"shipping_label"`
 }
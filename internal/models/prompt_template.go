@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PromptTemplate is a versioned Go text/template prompt for one optimization type.
+// Templates without an organization are global; an organization's active template
+// overrides the global one, and the built-in prompt applies when neither exists.
+type PromptTemplate struct {
+	ID               uuid.UUID        `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	OrganizationID   *uuid.UUID       `gorm:"type:uuid;index" json:"organization_id,omitempty"`
+	OptimizationType OptimizationType `gorm:"type:varchar(50);not null;index" json:"optimization_type"`
+	Version          int              `gorm:"type:integer;not null" json:"version"`
+	Name             string           `gorm:"type:varchar(255)" json:"name"`
+	System           string           `gorm:"type:text" json:"system"`
+	Body             string           `gorm:"type:text;not null" json:"body"`
+	MaxTokens        int              `gorm:"type:integer;default:500" json:"max_tokens"`
+	Temperature      float64          `gorm:"type:decimal(3,2);default:0.70" json:"temperature"`
+	IsActive         bool             `gorm:"default:false" json:"is_active"`
+	CreatedAt        time.Time        `gorm:"type:timestamp with time zone;default:now()" json:"created_at"`
+	UpdatedAt        time.Time        `gorm:"type:timestamp with time zone;default:now()" json:"updated_at"`
+}
+
+// TableName specifies the table name for PromptTemplate
+func (PromptTemplate) TableName() string {
+	return "prompt_templates"
+}
+
+// Builtin reports whether the template is one of the prompts shipped with the code
+func (t *PromptTemplate) Builtin() bool {
+	return t.ID == uuid.Nil
+}
+
+// PromptTemplateRequest creates a new version of an optimization type's prompt
+type PromptTemplateRequest struct {
+	OptimizationType OptimizationType `json:"optimization_type" binding:"required"`
+	Name             string           `json:"name,omitempty"`
+	System           string           `json:"system,omitempty"`
+	Body             string           `json:"body" binding:"required"`
+	MaxTokens        int              `json:"max_tokens,omitempty"`
+	Temperature      *float64         `json:"temperature,omitempty"`
+	Activate         *bool            `json:"activate,omitempty"`
+	Global           bool             `json:"global,omitempty"`
+}
+
+// PromptPreviewRequest renders a stored, built-in or draft template for a product
+type PromptPreviewRequest struct {
+	OptimizationType OptimizationType `json:"optimization_type" binding:"required"`
+	TemplateID       string           `json:"template_id,omitempty"`
+	System           string           `json:"system,omitempty"`
+	Body             string           `json:"body,omitempty"`
+	ProductID        string           `json:"product_id,omitempty"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TranslationSource records how a product translation was produced
+type TranslationSource string
+
+const (
+	TranslationSourceAI     TranslationSource = "ai"
+	TranslationSourceManual TranslationSource = "manual"
+)
+
+// ProductTranslation holds a product's title, description and SEO fields in one locale.
+// Locales are language codes with an optional region, such as "de" or "de-CH"; feeds for
+// a language without a translation use the product's own fields.
+type ProductTranslation struct {
+	ID             uuid.UUID         `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	OrganizationID uuid.UUID         `gorm:"type:uuid;not null;index" json:"organization_id"`
+	ProductID      uuid.UUID         `gorm:"type:uuid;not null;uniqueIndex:idx_product_translations_locale" json:"product_id"`
+	Locale         string            `gorm:"type:varchar(16);not null;uniqueIndex:idx_product_translations_locale" json:"locale"`
+	Title          string            `gorm:"type:text;not null" json:"title"`
+	Description    *string           `gorm:"type:text" json:"description,omitempty"`
+	SEOTitle       *string           `gorm:"column:seo_title;type:text" json:"seo_title,omitempty"`
+	SEODescription *string           `gorm:"column:seo_description;type:text" json:"seo_description,omitempty"`
+	Keywords       *string           `gorm:"type:text" json:"keywords,omitempty"`
+	Source         TranslationSource `gorm:"type:varchar(20);not null;default:'ai'" json:"source"`
+	AIModel        *string           `gorm:"type:varchar(100)" json:"ai_model,omitempty"`
+	CreatedAt      time.Time         `gorm:"type:timestamp with time zone;default:now()" json:"created_at"`
+	UpdatedAt      time.Time         `gorm:"type:timestamp with time zone;default:now()" json:"updated_at"`
+}
+
+// TableName specifies the table name for ProductTranslation
+func (ProductTranslation) TableName() string {
+	return "product_translations"
+}
+
+// GlossaryTerm fixes how a term is translated. Terms without a locale apply to every
+// locale; DoNotTranslate terms, such as brand names, are kept as written.
+type GlossaryTerm struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;not null;index" json:"organization_id"`
+	Locale         *string   `gorm:"type:varchar(16)" json:"locale,omitempty"`
+	Term           string    `gorm:"type:varchar(255);not null" json:"term"`
+	Translation    *string   `gorm:"type:varchar(255)" json:"translation,omitempty"`
+	DoNotTranslate bool      `gorm:"default:false" json:"do_not_translate"`
+	CreatedAt      time.Time `gorm:"type:timestamp with time zone;default:now()" json:"created_at"`
+}
+
+// TableName specifies the table name for GlossaryTerm
+func (GlossaryTerm) TableName() string {
+	return "translation_glossary"
+}
+
+// TranslationRequest generates translations of a product into one or more locales
+type TranslationRequest struct {
+	Locales []string `json:"locales" binding:"required"`
+	Model   string   `json:"model,omitempty"`
+}
+
+// TranslationUpdate sets a translation's fields by hand
+type TranslationUpdate struct {
+	Title          string  `json:"title" binding:"required"`
+	Description    *string `json:"description,omitempty"`
+	SEOTitle       *string `json:"seo_title,omitempty"`
+	SEODescription *string `json:"seo_description,omitempty"`
+	Keywords       *string `json:"keywords,omitempty"`
+}
+
+// GlossaryTermRequest adds a glossary term
+type GlossaryTermRequest struct {
+	Locale         string `json:"locale,omitempty"`
+	Term           string `json:"term" binding:"required"`
+	Translation    string `json:"translation,omitempty"`
+	DoNotTranslate bool   `json:"do_not_translate"`
+}
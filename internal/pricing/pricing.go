@@ -0,0 +1,102 @@
+// Package pricing works out the prices feeds list: a product's regular and sale price
+// with the sale's effective window, and the per-feed price rules that adjust them so a
+// channel's prices can differ from the store's.
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Product is what a feed item's prices are computed from. CompareAtPrice above Price
+// marks a store sale; SalePrice is a scheduled sale below Price, live between SaleStart
+// and SaleEnd when they are set. Cost, when known, is the floor of margin rules.
+type Product struct {
+	Price          float64
+	CompareAtPrice float64
+	SalePrice      float64
+	SaleStart      *time.Time
+	SaleEnd        *time.Time
+	Cost           float64
+	Currency       string
+}
+
+// Listing is a feed item's prices: Price is the regular price, SalePrice the discounted
+// one when the item is on sale, and CompareAtPrice the store's compare-at price, listed
+// only while above Price
+type Listing struct {
+	Price          float64
+	SalePrice      float64
+	CompareAtPrice float64
+	SaleStart      *time.Time
+	SaleEnd        *time.Time
+	Currency       string
+}
+
+// OnSale reports whether the listing has a sale price
+func (l Listing) OnSale() bool {
+	return l.SalePrice > 0 && l.SalePrice < l.Price
+}
+
+// EffectiveDate returns the sale window as channels expect it, ISO 8601 start and end
+// separated by a slash, or "" when the sale has no window
+func (l Listing) EffectiveDate() string {
+	if !l.OnSale() || l.SaleStart == nil || l.SaleEnd == nil {
+		return ""
+	}
+	return l.SaleStart.UTC().Format(time.RFC3339) + "/" + l.SaleEnd.UTC().Format(time.RFC3339)
+}
+
+// Convert returns the listing in currency, with prices multiplied by rate
+func (l Listing) Convert(rate float64, currency string) Listing {
+	l.Price = math.Round(l.Price*rate*100) / 100
+	l.SalePrice = math.Round(l.SalePrice*rate*100) / 100
+	l.CompareAtPrice = math.Round(l.CompareAtPrice*rate*100) / 100
+	l.Currency = strings.ToUpper(currency)
+	return l
+}
+
+// AddTax returns the listing with percent tax added to its prices, for countries where
+// listed prices include VAT
+func (l Listing) AddTax(percent float64) Listing {
+	l.Price = math.Round(l.Price*(1+percent/100)*100) / 100
+	l.SalePrice = math.Round(l.SalePrice*(1+percent/100)*100) / 100
+	l.CompareAtPrice = math.Round(l.CompareAtPrice*(1+percent/100)*100) / 100
+	return l
+}
+
+// List returns a product's listing at now. A compare-at price above the price is a sale
+// running now, listed at the compare-at price with the price as sale price. A scheduled
+// sale price is listed until its window ends, including before it starts, since
+// channels apply the effective date themselves.
+func List(p Product, now time.Time) Listing {
+	l := Listing{Price: p.Price, CompareAtPrice: p.CompareAtPrice, Currency: p.Currency}
+	switch {
+	case p.SalePrice > 0 && p.SalePrice < p.Price && (p.SaleEnd == nil || now.Before(*p.SaleEnd)):
+		l.SalePrice = p.SalePrice
+	case p.CompareAtPrice > p.Price && p.Price > 0:
+		l.Price, l.SalePrice = p.CompareAtPrice, p.Price
+	default:
+		return l
+	}
+	l.SaleStart, l.SaleEnd = p.SaleStart, p.SaleEnd
+	if l.SaleStart != nil && l.SaleEnd != nil && !l.SaleStart.Before(*l.SaleEnd) {
+		l.SaleStart, l.SaleEnd = nil, nil
+	}
+	return l
+}
+
+// ParseTime reads sale window times: RFC 3339, or a date taken as midnight UTC
+func ParseTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid time %q: use RFC 3339 or YYYY-MM-DD", s)
+}
@@ -0,0 +1,150 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Rule types
+const (
+	RuleMarkup    = "markup"
+	RuleMarkdown  = "markdown"
+	RuleRound     = "round"
+	RuleConvert   = "convert"
+	RuleMinMargin = "min_margin"
+)
+
+// Rule is one step of a feed's price rules, run in order on the regular and sale price:
+//
+//	[{"type": "markup", "percent": 10},
+//	 {"type": "convert", "currency": "EUR", "rate": 0.92},
+//	 {"type": "round", "ending": 0.99},
+//	 {"type": "min_margin", "percent": 25}]
+//
+// markup and markdown change prices by percent; round moves them to the nearest price
+// ending in ending (0.99, 0.95, or 0 for whole amounts); convert multiplies by rate and
+// sets the currency; min_margin raises prices that leave less than percent of the price
+// over the product's cost.
+type Rule struct {
+	Type     string  `json:"type"`
+	Percent  float64 `json:"percent,omitempty"`
+	Ending   float64 `json:"ending,omitempty"`
+	Currency string  `json:"currency,omitempty"`
+	Rate     float64 `json:"rate,omitempty"`
+}
+
+// Rules are a feed's price rules
+type Rules []Rule
+
+// ParseRules reads and validates price rules JSON; an empty value has no rules
+func ParseRules(raw string) (Rules, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "null" {
+		return nil, nil
+	}
+	var rules Rules
+	if err := json.Unmarshal([]byte(raw), &rules); err != nil {
+		return nil, fmt.Errorf("invalid price rules: %w", err)
+	}
+	for i, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("price rule %d: %w", i+1, err)
+		}
+	}
+	return rules, nil
+}
+
+func (r Rule) validate() error {
+	switch r.Type {
+	case RuleMarkup, RuleMarkdown:
+		if r.Percent <= 0 || (r.Type == RuleMarkdown && r.Percent >= 100) {
+			return fmt.Errorf("%s needs a percent between 0 and 100", r.Type)
+		}
+	case RuleRound:
+		if r.Ending < 0 || r.Ending >= 1 {
+			return fmt.Errorf("round ending must be between 0 and 0.99")
+		}
+	case RuleConvert:
+		if len(r.Currency) != 3 || r.Rate <= 0 {
+			return fmt.Errorf("convert needs a 3-letter currency and a positive rate")
+		}
+	case RuleMinMargin:
+		if r.Percent <= 0 || r.Percent >= 100 {
+			return fmt.Errorf("min_margin needs a percent between 0 and 100")
+		}
+	default:
+		return fmt.Errorf("unknown price rule %q", r.Type)
+	}
+	return nil
+}
+
+// Apply runs the rules on a listing. cost is the product's cost in the listing's
+// original currency; 0 skips margin rules. A sale price the rules raise to the regular
+// price or above is dropped, as is a compare-at price that ends up at or below it.
+func (rules Rules) Apply(l Listing, cost float64) Listing {
+	for _, rule := range rules {
+		switch rule.Type {
+		case RuleConvert:
+			cost *= rule.Rate
+			l.Currency = strings.ToUpper(rule.Currency)
+		case RuleMinMargin:
+			if cost <= 0 {
+				continue
+			}
+		}
+		l.Price = rule.apply(l.Price, cost)
+		if l.SalePrice > 0 {
+			l.SalePrice = rule.apply(l.SalePrice, cost)
+		}
+		if l.CompareAtPrice > 0 {
+			l.CompareAtPrice = rule.apply(l.CompareAtPrice, cost)
+		}
+	}
+	if l.SalePrice >= l.Price {
+		l.SalePrice, l.SaleStart, l.SaleEnd = 0, nil, nil
+	}
+	if l.CompareAtPrice <= l.Price {
+		l.CompareAtPrice = 0
+	}
+	return l
+}
+
+func (r Rule) apply(price, cost float64) float64 {
+	switch r.Type {
+	case RuleMarkup:
+		price *= 1 + r.Percent/100
+	case RuleMarkdown:
+		price *= 1 - r.Percent/100
+	case RuleConvert:
+		price *= r.Rate
+	case RuleRound:
+		return Round(price, r.Ending)
+	case RuleMinMargin:
+		if floor := cost / (1 - r.Percent/100); price < floor {
+			price = floor
+		}
+	}
+	return math.Round(price*100) / 100
+}
+
+// Round moves a price to the nearest amount ending in ending, such as 19.99 for 20.30
+// and 20.49 for 20.40 with 0.99 and 0.49. Halfway prices go down. Prices never round
+// down to zero.
+func Round(price, ending float64) float64 {
+	if price <= 0 {
+		return price
+	}
+	base := math.Floor(price)
+	rounded := 0.0
+	for _, candidate := range []float64{base - 1 + ending, base + ending, base + 1 + ending} {
+		if candidate <= 0 {
+			continue
+		}
+		if rounded == 0 || math.Abs(candidate-price) < math.Abs(rounded-price)-1e-9 {
+			rounded = candidate
+		}
+	}
+	return math.Round(rounded*100) / 100
+}
@@ -0,0 +1,119 @@
+package pricing
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		price, ending, want float64
+	}{
+		{20.40, 0.49, 20.49},
+		{20.30, 0.99, 19.99},
+		{20.50, 0.99, 20.99},
+		{20.49, 0.99, 19.99}, // halfway goes down
+		{20.99, 0.99, 20.99},
+		{19.999, 0.99, 19.99},
+		{21.00, 0.95, 20.95},
+		{20.95, 0.40, 21.40},
+		{20.44, 0.40, 20.40},
+		{20.50, 0, 20}, // halfway goes down
+		{20.51, 0, 21},
+		{0.30, 0, 1}, // never zero
+		{0.50, 0.99, 0.99},
+		{0.01, 0.99, 0.99},
+		{0, 0.99, 0},
+		{-5, 0.99, -5},
+	}
+	for _, tt := range tests {
+		if got := Round(tt.price, tt.ending); got != tt.want {
+			t.Errorf("Round(%v, %v) = %v, want %v", tt.price, tt.ending, got, tt.want)
+		}
+	}
+}
+
+func TestApplyCompareAtPrice(t *testing.T) {
+	start := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 7)
+
+	tests := []struct {
+		name    string
+		rules   Rules
+		listing Listing
+		cost    float64
+		want    Listing
+	}{
+		{
+			name:    "markup keeps the compare-at price above the price",
+			rules:   Rules{{Type: RuleMarkup, Percent: 10}},
+			listing: Listing{Price: 100, CompareAtPrice: 120, Currency: "USD"},
+			want:    Listing{Price: 110, CompareAtPrice: 132, Currency: "USD"},
+		},
+		{
+			name:    "convert converts the compare-at price",
+			rules:   Rules{{Type: RuleConvert, Currency: "eur", Rate: 0.9}},
+			listing: Listing{Price: 10, CompareAtPrice: 15, Currency: "USD"},
+			want:    Listing{Price: 9, CompareAtPrice: 13.5, Currency: "EUR"},
+		},
+		{
+			name:    "round keeps a compare-at price that ends up above",
+			rules:   Rules{{Type: RuleRound, Ending: 0.99}},
+			listing: Listing{Price: 20.30, CompareAtPrice: 24.10, Currency: "USD"},
+			want:    Listing{Price: 19.99, CompareAtPrice: 23.99, Currency: "USD"},
+		},
+		{
+			name:    "round drops a compare-at price rounded to the price",
+			rules:   Rules{{Type: RuleRound, Ending: 0.99}},
+			listing: Listing{Price: 20.30, CompareAtPrice: 20.40, Currency: "USD"},
+			want:    Listing{Price: 19.99, Currency: "USD"},
+		},
+		{
+			name:    "min_margin drops a compare-at price it raises the price past",
+			rules:   Rules{{Type: RuleMinMargin, Percent: 50}},
+			listing: Listing{Price: 10, CompareAtPrice: 12, Currency: "USD"},
+			cost:    8,
+			want:    Listing{Price: 16, Currency: "USD"},
+		},
+		{
+			name:    "min_margin converts the cost with the prices",
+			rules:   Rules{{Type: RuleConvert, Currency: "GBP", Rate: 0.5}, {Type: RuleMinMargin, Percent: 50}},
+			listing: Listing{Price: 10, CompareAtPrice: 30, Currency: "USD"},
+			cost:    8,
+			want:    Listing{Price: 8, CompareAtPrice: 15, Currency: "GBP"},
+		},
+		{
+			name:    "min_margin is skipped without a cost",
+			rules:   Rules{{Type: RuleMinMargin, Percent: 50}},
+			listing: Listing{Price: 10, CompareAtPrice: 12, Currency: "USD"},
+			want:    Listing{Price: 10, CompareAtPrice: 12, Currency: "USD"},
+		},
+		{
+			name:    "sale and compare-at prices go through the same rules",
+			rules:   Rules{{Type: RuleMarkdown, Percent: 20}},
+			listing: Listing{Price: 50, SalePrice: 40, CompareAtPrice: 60, SaleStart: &start, SaleEnd: &end, Currency: "USD"},
+			want:    Listing{Price: 40, SalePrice: 32, CompareAtPrice: 48, SaleStart: &start, SaleEnd: &end, Currency: "USD"},
+		},
+		{
+			name:    "a sale price raised to the price is dropped with its window",
+			rules:   Rules{{Type: RuleMinMargin, Percent: 20}},
+			listing: Listing{Price: 10, SalePrice: 9, CompareAtPrice: 14, SaleStart: &start, SaleEnd: &end, Currency: "USD"},
+			cost:    9,
+			want:    Listing{Price: 11.25, CompareAtPrice: 14, Currency: "USD"},
+		},
+		{
+			name:    "no compare-at price",
+			rules:   Rules{{Type: RuleMarkup, Percent: 10}},
+			listing: Listing{Price: 10, Currency: "USD"},
+			want:    Listing{Price: 11, Currency: "USD"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rules.Apply(tt.listing, tt.cost); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package prompts
+
+import "lister/internal/models"
+
+// Builtin returns the prompt shipped with the code for an optimization type, or nil when
+// the type has none. Built-in templates have no ID and version 0.
+func Builtin(t models.OptimizationType) *models.PromptTemplate {
+	b, ok := builtins[t]
+	if !ok {
+		return nil
+	}
+	tmpl := b
+	return &tmpl
+}
+
+// Builtins returns the built-in template of every optimization type that has one
+func Builtins() []models.PromptTemplate {
+	list := make([]models.PromptTemplate, 0, len(builtins))
+	for _, t := range []models.OptimizationType{
+		models.OptimizationTypeTitle,
+		models.OptimizationTypeDescription,
+		models.OptimizationTypeCategory,
+		models.OptimizationTypeSEO,
+		models.OptimizationTypeGTIN,
+		models.OptimizationTypeTranslation,
+		models.OptimizationTypeAttribute,
+	} {
+		list = append(list, builtins[t])
+	}
+	return list
+}
+
+// Resolve returns stored, or the built-in template when nothing is stored for the type
+func Resolve(stored *models.PromptTemplate, t models.OptimizationType) *models.PromptTemplate {
+	if stored != nil {
+		return stored
+	}
+	return Builtin(t)
+}
+
+var builtins = map[models.OptimizationType]models.PromptTemplate{
+	models.OptimizationTypeTitle: {
+		OptimizationType: models.OptimizationTypeTitle,
+		Name:             "Built-in title",
+		MaxTokens:        50,
+		Temperature:      0.7,
+		IsActive:         true,
+		Body: `You are a professional e-commerce SEO expert. Transform this product title into a compelling, searchable title that drives clicks and sales.
+
+CURRENT PRODUCT:
+- Original Title: "{{.Product.Title}}"
+- Product Description: "{{.Product.Description}}"
+- Brand: "{{.Product.Brand}}"
+- Category: "{{.Product.Category}}"
+- Keywords to include: "{{.Options.Keywords}}"
+
+TASK: Create a new title that is:
+1. MORE DESCRIPTIVE than the original
+2. Includes the brand name naturally
+3. Contains relevant keywords customers search for
+4. Under {{default 60 .Options.MaxLength}} characters
+5. Compelling and click-worthy
+6. Written {{inLanguage .Language}}
+
+EXAMPLES OF GOOD TRANSFORMATIONS:
+Original: "Summer Necklace"
+Better: "Gold Boho Necklace with Turquoise Pendant - Elegant Summer Jewelry"
+
+Original: "Leather Bag"
+Better: "Premium Leather Crossbody Bag - Stylish Women's Handbag"
+
+Original: "Blue Shirt"
+Better: "Men's Cotton Oxford Blue Dress Shirt - Business Casual"
+{{with .Instructions}}
+CUSTOM INSTRUCTIONS (IMPORTANT - Follow these):
+{{.}}
+{{end}}
+Based on the description "{{.Product.Description}}", create a title that describes what the product actually is and why customers should buy it. Return ONLY the optimized title:`,
+	},
+
+	models.OptimizationTypeDescription: {
+		OptimizationType: models.OptimizationTypeDescription,
+		Name:             "Built-in description",
+		MaxTokens:        300,
+		Temperature:      0.8,
+		IsActive:         true,
+		Body: `You are an expert e-commerce copywriter who specializes in creating compelling product descriptions that drive sales and conversions.
+
+PRODUCT INFORMATION:
+- Product Name: "{{.Product.Title}}"
+- Original Description: "{{.Product.Description}}"
+- Brand: "{{.Product.Brand}}"
+- Category: "{{.Product.Category}}"
+- Price: ${{printf "%.2f" .Product.Price}}
+- Style: {{default "marketing" .Options.Style}}
+- Length: {{default "medium" .Options.Length}}
+
+COPYWRITING REQUIREMENTS:
+1. Write in {{default "marketing" .Options.Style}} style (marketing/technical/casual)
+2. Make it {{default "medium" .Options.Length}} length (short/medium/long)
+3. Focus on CUSTOMER BENEFITS, not just features
+4. Use EMOTIONAL TRIGGERS and POWER WORDS
+5. Include a COMPELLING CALL-TO-ACTION
+6. Make it SCANNABLE with bullet points or short paragraphs
+7. Add RELEVANT EMOJIS to increase engagement
+8. Address CUSTOMER PAIN POINTS and solutions
+9. Create URGENCY and DESIRE to buy
+10. Write {{inLanguage .Language}}{{with .Options.Audience}} for a {{audience .}}{{end}}
+
+STYLE GUIDELINES:
+- Marketing: Focus on benefits, emotional appeal, social proof
+- Technical: Detailed specifications, features, performance
+- Casual: Friendly, conversational, approachable
+{{with .Instructions}}
+CUSTOM INSTRUCTIONS (IMPORTANT - Follow these):
+{{.}}
+{{end}}
+Create a description that makes customers excited to buy this product. Return ONLY the enhanced description:`,
+	},
+
+	models.OptimizationTypeCategory: {
+		OptimizationType: models.OptimizationTypeCategory,
+		Name:             "Built-in category",
+		MaxTokens:        200,
+		Temperature:      0.6,
+		IsActive:         true,
+		Body: `You are an expert e-commerce product categorization specialist. Analyze this product and suggest the most appropriate Google Shopping-style hierarchical categories.
+
+Product: "{{.Product.Title}}"
+Description: "{{.Product.Description}}"
+Brand: "{{.Product.Brand}}"
+Current Category: "{{.Product.Category}}"
+{{with .Options.Categories}}
+ALLOWED CATEGORIES (choose ONLY from these, copied exactly):
+{{range .}}- {{.}}
+{{end}}{{end}}
+Provide 3 category suggestions in this exact JSON format:
+{"suggestions": [
+  {"category": "Apparel & Accessories > Clothing > Outerwear > Coats & Jackets", "confidence": 95, "reason": "Product is clearly outerwear"},
+  {"category": "Apparel & Accessories > Clothing > Activewear", "confidence": 85, "reason": "Can be used for sports"},
+  {"category": "Apparel & Accessories > Clothing", "confidence": 75, "reason": "General clothing item"}
+]}
+
+IMPORTANT REQUIREMENTS:
+- Use hierarchical categories with " > " separators (e.g., "Parent > Child > Grandchild")
+- Use paths of the Google product taxonomy exactly as Google Merchant Center lists them
+- Confidence as WHOLE NUMBERS 0-100 (not decimals like 0.95)
+- Categories should be specific and follow e-commerce standards
+- Focus on the most accurate category paths
+- Common category prefixes: "Apparel & Accessories", "Electronics", "Home & Garden", "Sporting Goods", "Health & Beauty", "Toys & Games"
+{{with .Instructions}}
+CUSTOM INSTRUCTIONS:
+{{.}}
+{{end}}
+Return ONLY the JSON object, no other text:`,
+	},
+
+	models.OptimizationTypeSEO: {
+		OptimizationType: models.OptimizationTypeSEO,
+		Name:             "Built-in SEO",
+		MaxTokens:        500,
+		Temperature:      0.7,
+		IsActive:         true,
+		Body: `You are an expert e-commerce SEO specialist. Analyze this product and provide comprehensive SEO optimization {{inLanguage .Language}}.
+
+Product data: {{json .Product}}
+
+TARGET LANGUAGE: {{inLanguage .Language}}
+TARGET AUDIENCE: {{audience .Options.Audience}}
+OPTIMIZATION LEVEL: {{levelInstruction .Options.Level}}
+FOCUS: {{focusInstruction .Options.Focus}}
+{{with .Instructions}}
+IMPORTANT CUSTOM INSTRUCTIONS FROM USER:
+{{.}}
+
+Follow these custom instructions carefully.
+{{end}}
+Provide a JSON response with the following structure:
+{
+  "seo_title": "Optimized title under 60 characters",
+  "seo_description": "Meta description under 160 characters",
+  "keywords": ["keyword1", "keyword2", "keyword3"],
+  "meta_keywords": "keyword1, keyword2, keyword3",
+  "alt_text": "Descriptive alt text for product images",
+  "schema_markup": "{\"@context\":\"https://schema.org\",\"@type\":\"Product\",\"name\":\"Product Name\",\"description\":\"Description\",\"brand\":{\"@type\":\"Brand\",\"name\":\"Brand\"}}"
+}
+
+CRITICAL REQUIREMENTS:
+- SEO title: Under 60 characters, keyword-rich, compelling, written {{inLanguage .Language}}
+- SEO description: Under 160 characters, persuasive, includes CTA, written {{inLanguage .Language}} for {{audience .Options.Audience}}
+- Keywords: 5-10 relevant keywords from title, category, brand
+- Alt text: Descriptive, includes product name and key features
+- Schema markup: MUST be a JSON STRING (escaped JSON), NOT a JSON object. See example above.
+
+Return ONLY the JSON response, no markdown code blocks, no explanations.`,
+	},
+
+	models.OptimizationTypeGTIN: {
+		OptimizationType: models.OptimizationTypeGTIN,
+		Name:             "Built-in GTIN",
+		MaxTokens:        50,
+		Temperature:      0.2,
+		IsActive:         true,
+		Body: `You are an expert in product identification. Analyze this product and suggest a GTIN (Global Trade Item Number) if possible.
+
+Product data: {{json .Product}}
+
+Requirements:
+- Return only the GTIN if you can determine it
+- If no GTIN can be determined, return empty string
+- GTIN should be 8, 12, 13, or 14 digits
+- Consider product type, brand, and attributes
+
+Return ONLY the GTIN or empty string, no explanations.`,
+	},
+
+	models.OptimizationTypeTranslation: {
+		OptimizationType: models.OptimizationTypeTranslation,
+		Name:             "Built-in translation",
+		MaxTokens:        1500,
+		Temperature:      0.3,
+		IsActive:         true,
+		Body: `You are a professional e-commerce translator. Translate this product's content {{inLanguage .Language}} for shoppers in that market.
+
+Product data: {{json .Product}}
+{{with .Options.Glossary}}
+GLOSSARY (always translate these terms exactly as given):
+{{range $term, $translation := .}}- "{{$term}}" → "{{$translation}}"
+{{end}}{{end}}
+RULES:
+- Translate meaning and tone naturally; don't translate word for word
+- Keep placeholders such as [[0]] exactly as they are, in the position that reads naturally
+- Keep HTML tags, numbers, units and sizes unchanged
+- seo_title: under 60 characters; seo_description: under 160 characters
+- keywords: comma separated, as shoppers search for them in the target language
+- Return an empty string for fields the product data doesn't have
+{{with .Instructions}}
+CUSTOM INSTRUCTIONS:
+{{.}}
+{{end}}
+Provide a JSON response with the following structure:
+{
+  "title": "Translated title",
+  "description": "Translated description",
+  "seo_title": "Translated SEO title",
+  "seo_description": "Translated meta description",
+  "keywords": "keyword1, keyword2, keyword3"
+}
+
+Return ONLY the JSON response, no markdown code blocks, no explanations.`,
+	},
+
+	models.OptimizationTypeAttribute: {
+		OptimizationType: models.OptimizationTypeAttribute,
+		Name:             "Built-in attribute extraction",
+		MaxTokens:        500,
+		Temperature:      0.1,
+		IsActive:         true,
+		Body: `You are a product data specialist preparing shopping feeds for Google Merchant Center and Meta catalogs. Extract this product's apparel attributes from its title, description, category and variant options.
+
+Product data: {{json .Product}}
+
+ATTRIBUTES:
+- color: the product's color(s) as shoppers name them, e.g. "Navy Blue"; join several with "/"
+- size: the size, or every size offered by the variants joined with ", "
+- material: the main fabric or material, e.g. "Organic Cotton"; join blends with "/"
+- gender: one of male, female, unisex
+- age_group: one of newborn, infant, toddler, kids, adult
+
+RULES:
+- Only return attributes the product data states or clearly implies; omit the rest
+- confidence is 0-100: 90+ when stated explicitly, 60-89 when implied, below 60 when guessed
+- source is the field the value came from: title, description, variants or category
+{{with .Instructions}}
+CUSTOM INSTRUCTIONS:
+{{.}}
+{{end}}
+Provide a JSON response with the following structure:
+{
+  "attributes": [
+    {"name": "color", "value": "Navy Blue", "confidence": 95, "source": "variants"},
+    {"name": "gender", "value": "female", "confidence": 70, "source": "description"}
+  ]
+}
+
+Return ONLY the JSON response, no markdown code blocks, no explanations.`,
+	},
+}
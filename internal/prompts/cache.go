@@ -0,0 +1,28 @@
+package prompts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"lister/internal/models"
+)
+
+// CacheKey returns the content address of a rendered prompt: a hash of the model, the
+// template and its version, and the rendered text, which carries every product field the
+// template uses. Requests with the same key would send the model the same prompt.
+func CacheKey(t *models.PromptTemplate, model string, r Rendered) string {
+	key, _ := json.Marshal(struct {
+		Type        models.OptimizationType
+		Model       string
+		TemplateID  string
+		Version     int
+		System      string
+		Prompt      string
+		MaxTokens   int
+		Temperature float64
+	}{t.OptimizationType, model, t.ID.String(), t.Version, r.System, r.Prompt, r.MaxTokens, r.Temperature})
+
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,297 @@
+// Package prompts renders the prompt templates used for AI optimizations. Templates are
+// Go text/template sources stored as versioned records per optimization type; each type
+// also has a built-in template that applies until one is saved.
+package prompts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"lister/internal/llm"
+	"lister/internal/models"
+)
+
+// Product holds the product fields available to templates as .Product
+type Product struct {
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Brand       string  `json:"brand,omitempty"`
+	Category    string  `json:"category,omitempty"`
+	ProductType string  `json:"product_type,omitempty"`
+	Vendor      string  `json:"vendor,omitempty"`
+	SKU         string  `json:"sku,omitempty"`
+	GTIN        string  `json:"gtin,omitempty"`
+	Price       float64 `json:"price"`
+	Currency    string  `json:"currency,omitempty"`
+
+	SEOTitle       string `json:"seo_title,omitempty"`
+	SEODescription string `json:"seo_description,omitempty"`
+	SEOKeywords    string `json:"seo_keywords,omitempty"`
+
+	// VariantOptions holds the values of each variant option, such as {"Size": ["M", "L"]}
+	VariantOptions map[string][]string `json:"variant_options,omitempty"`
+}
+
+// Settings holds the organization's AI settings available to templates as .Settings
+type Settings struct {
+	Model              string
+	Language           string
+	FallbackLanguage   string
+	CustomInstructions string
+}
+
+// SettingsFrom copies the template variables out of an organization's AISettings
+func SettingsFrom(s *models.AISettings) Settings {
+	if s == nil {
+		return Settings{}
+	}
+	settings := Settings{
+		Model:            s.DefaultModel,
+		Language:         s.DefaultLanguage,
+		FallbackLanguage: s.FallbackLanguage,
+	}
+	if s.CustomInstructions != nil {
+		settings.CustomInstructions = *s.CustomInstructions
+	}
+	return settings
+}
+
+// Options holds the choices made for a single request, available as .Options
+type Options struct {
+	Keywords           string
+	MaxLength          int
+	Strategy           string
+	Style              string
+	Length             string
+	Focus              string
+	Audience           string
+	Level              string
+	Language           string
+	CustomInstructions string
+	// Glossary maps source terms to the translation a translation prompt must use
+	Glossary map[string]string
+	// Categories are the taxonomy paths a category prompt must choose from
+	Categories []string
+}
+
+// Data is the value templates are executed against
+type Data struct {
+	Product  Product
+	Settings Settings
+	Options  Options
+}
+
+// Language is the requested language code, falling back to the organization's default
+// and then English
+func (d Data) Language() string {
+	for _, lang := range []string{d.Options.Language, d.Settings.Language, d.Settings.FallbackLanguage} {
+		if lang != "" {
+			return lang
+		}
+	}
+	return "en"
+}
+
+// Instructions joins the organization's custom instructions with the request's own
+func (d Data) Instructions() string {
+	parts := []string{}
+	for _, s := range []string{d.Settings.CustomInstructions, d.Options.CustomInstructions} {
+		if s = strings.TrimSpace(s); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// Sample returns product data used to validate templates before they are saved
+func Sample() Data {
+	return Data{
+		Product: Product{
+			Title:       "Leather Bag",
+			Description: "Handmade bag in full-grain leather with an adjustable strap.",
+			Brand:       "Acme",
+			Category:    "Apparel & Accessories > Handbags",
+			ProductType: "Bags",
+			Vendor:      "Acme",
+			SKU:         "BAG-001",
+			Price:       89.95,
+			Currency:    "USD",
+		},
+		Settings: Settings{Language: "en", FallbackLanguage: "en"},
+		Options:  Options{Keywords: "leather, crossbody", MaxLength: 60},
+	}
+}
+
+// Rendered is a template executed against product data
+type Rendered struct {
+	System      string
+	Prompt      string
+	MaxTokens   int
+	Temperature float64
+}
+
+// Request builds the completion request for model
+func (r Rendered) Request(model string) llm.Request {
+	req := llm.Prompt(model, r.Prompt, r.MaxTokens, r.Temperature)
+	req.System = r.System
+	return req
+}
+
+// Render executes the template's system and body against data
+func Render(t *models.PromptTemplate, data Data) (Rendered, error) {
+	system, err := execute(string(t.OptimizationType)+".system", t.System, data)
+	if err != nil {
+		return Rendered{}, err
+	}
+	prompt, err := execute(string(t.OptimizationType), t.Body, data)
+	if err != nil {
+		return Rendered{}, err
+	}
+
+	rendered := Rendered{
+		System:      strings.TrimSpace(system),
+		Prompt:      strings.TrimSpace(prompt),
+		MaxTokens:   t.MaxTokens,
+		Temperature: t.Temperature,
+	}
+	if rendered.MaxTokens <= 0 {
+		rendered.MaxTokens = 500
+	}
+	return rendered, nil
+}
+
+// Validate checks that a template parses and executes against sample data, so mistakes
+// such as unknown fields are reported when the template is saved
+func Validate(t *models.PromptTemplate) error {
+	if strings.TrimSpace(t.Body) == "" {
+		return fmt.Errorf("template body is required")
+	}
+	if t.MaxTokens < 0 || t.MaxTokens > 4000 {
+		return fmt.Errorf("max_tokens must be between 0 and 4000")
+	}
+	if t.Temperature < 0 || t.Temperature > 2 {
+		return fmt.Errorf("temperature must be between 0 and 2")
+	}
+	_, err := Render(t, Sample())
+	return err
+}
+
+func execute(name, source string, data Data) (string, error) {
+	if source == "" {
+		return "", nil
+	}
+	tmpl, err := template.New(name).Funcs(funcs).Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s template: %v", name, err)
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %v", name, err)
+	}
+	return out.String(), nil
+}
+
+var funcs = template.FuncMap{
+	"json":             toJSON,
+	"default":          defaultValue,
+	"inLanguage":       inLanguage,
+	"audience":         audience,
+	"levelInstruction": levelInstruction,
+	"focusInstruction": focusInstruction,
+	"upper":            strings.ToUpper,
+	"lower":            strings.ToLower,
+	"trim":             strings.TrimSpace,
+	"truncate":         truncate,
+}
+
+func toJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+// defaultValue returns value unless it is empty or zero: {{default 60 .Options.MaxLength}}
+func defaultValue(fallback, value interface{}) interface{} {
+	if value == nil {
+		return fallback
+	}
+	if reflect.ValueOf(value).IsZero() {
+		return fallback
+	}
+	return value
+}
+
+func truncate(n int, s string) string {
+	if n <= 0 || len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
+
+var languages = map[string]string{
+	"en": "in English",
+	"es": "in Spanish (Español)",
+	"fr": "in French (Français)",
+	"de": "in German (Deutsch)",
+	"it": "in Italian (Italiano)",
+	"nl": "in Dutch (Nederlands)",
+	"pt": "in Portuguese (Português)",
+}
+
+// inLanguage turns a language code into an instruction such as "in German (Deutsch)"
+func inLanguage(code string) string {
+	if instruction, ok := languages[strings.ToLower(code)]; ok {
+		return instruction
+	}
+	if code == "" {
+		return languages["en"]
+	}
+	return "in the language with code " + code
+}
+
+var audiences = map[string]string{
+	"general":       "general audience",
+	"professionals": "professional audience (business-focused, technical terms are OK)",
+	"students":      "students and young adults (clear, educational tone)",
+	"families":      "families and parents (warm, family-friendly tone)",
+}
+
+func audience(name string) string {
+	if description, ok := audiences[name]; ok {
+		return description
+	}
+	return audiences["general"]
+}
+
+var levels = map[string]string{
+	"conservative": "Make minimal changes, preserve the original tone and style. Only fix obvious issues.",
+	"balanced":     "Balance between keeping the original style and adding improvements. Moderate SEO optimization.",
+	"aggressive":   "Maximize SEO potential. Rewrite completely for best search visibility and conversion.",
+}
+
+func levelInstruction(level string) string {
+	if instruction, ok := levels[level]; ok {
+		return instruction
+	}
+	return levels["balanced"]
+}
+
+func focusInstruction(focus string) string {
+	switch focus {
+	case "title":
+		return "Focus ONLY on optimizing the SEO title. Keep description and other fields minimal."
+	case "description":
+		return "Focus ONLY on optimizing the SEO description. Keep title and other fields minimal."
+	case "category":
+		return "Focus on improving category classification and keywords."
+	case "tags":
+		return "Focus on generating comprehensive, relevant keywords and tags."
+	case "seo":
+		return "Focus on technical SEO elements: schema markup, meta tags, alt text."
+	default:
+		return "Optimize all aspects: title, description, keywords, and technical SEO."
+	}
+}
@@ -0,0 +1,139 @@
+package prompts
+
+import (
+	"lister/internal/attributes"
+	"lister/internal/llm"
+	"lister/internal/models"
+)
+
+// Schema returns the JSON schema of the structured response an optimization type expects,
+// or nil when the response is free text
+func Schema(t models.OptimizationType) *llm.Schema {
+	switch t {
+	case models.OptimizationTypeSEO:
+		return seoSchema
+	case models.OptimizationTypeCategory:
+		return categorySchema
+	case models.OptimizationTypeTranslation:
+		return translationSchema
+	case models.OptimizationTypeAttribute:
+		return attributeSchema
+	}
+	return nil
+}
+
+// CategorySuggestions is the structured response of category prompts
+type CategorySuggestions struct {
+	Suggestions []CategorySuggestion `json:"suggestions"`
+}
+
+// CategorySuggestion is a Google product category with the model's confidence (0-100)
+type CategorySuggestion struct {
+	Category   string  `json:"category"`
+	Confidence float64 `json:"confidence"`
+	Reason     string  `json:"reason,omitempty"`
+}
+
+// Best returns the most confident suggestion
+func (s CategorySuggestions) Best() CategorySuggestion {
+	var best CategorySuggestion
+	for i, suggestion := range s.Suggestions {
+		if i == 0 || suggestion.Confidence > best.Confidence {
+			best = suggestion
+		}
+	}
+	return best
+}
+
+// Translation is the structured response of translation prompts. Fields the product
+// doesn't have come back empty.
+type Translation struct {
+	Title          string `json:"title"`
+	Description    string `json:"description"`
+	SEOTitle       string `json:"seo_title"`
+	SEODescription string `json:"seo_description"`
+	Keywords       string `json:"keywords"`
+}
+
+// ExtractedAttributes is the structured response of attribute extraction prompts
+type ExtractedAttributes struct {
+	Attributes []ExtractedAttribute `json:"attributes"`
+}
+
+// ExtractedAttribute is an attribute value with the model's confidence (0-100) and the
+// product field it was read from
+type ExtractedAttribute struct {
+	Name       string  `json:"name"`
+	Value      string  `json:"value"`
+	Confidence float64 `json:"confidence"`
+	Source     string  `json:"source,omitempty"`
+}
+
+var seoSchema = &llm.Schema{
+	Title: "seo_enhancement",
+	Type:  llm.TypeObject,
+	Properties: map[string]*llm.Schema{
+		"seo_title":       {Type: llm.TypeString, Description: "Optimized title under 60 characters"},
+		"seo_description": {Type: llm.TypeString, Description: "Meta description under 160 characters"},
+		"keywords":        {Type: llm.TypeArray, Items: &llm.Schema{Type: llm.TypeString}, MinItems: 1},
+		"meta_keywords":   {Type: llm.TypeString, Description: "Comma separated keywords"},
+		"alt_text":        {Type: llm.TypeString, Description: "Descriptive alt text for product images"},
+		"schema_markup":   {Type: llm.TypeString, Description: "JSON-LD Product markup as an escaped JSON string"},
+	},
+	Required: []string{"seo_title", "seo_description", "keywords", "meta_keywords", "alt_text", "schema_markup"},
+}
+
+var categorySchema = &llm.Schema{
+	Title: "category_suggestions",
+	Type:  llm.TypeObject,
+	Properties: map[string]*llm.Schema{
+		"suggestions": {
+			Type:     llm.TypeArray,
+			MinItems: 1,
+			Items: &llm.Schema{
+				Type: llm.TypeObject,
+				Properties: map[string]*llm.Schema{
+					"category":   {Type: llm.TypeString, Description: "Google product category path separated by \" > \""},
+					"confidence": {Type: llm.TypeNumber, Minimum: llm.Range(0), Maximum: llm.Range(100)},
+					"reason":     {Type: llm.TypeString},
+				},
+				Required: []string{"category", "confidence"},
+			},
+		},
+	},
+	Required: []string{"suggestions"},
+}
+
+var translationSchema = &llm.Schema{
+	Title: "product_translation",
+	Type:  llm.TypeObject,
+	Properties: map[string]*llm.Schema{
+		"title":           {Type: llm.TypeString, Description: "Translated product title"},
+		"description":     {Type: llm.TypeString},
+		"seo_title":       {Type: llm.TypeString},
+		"seo_description": {Type: llm.TypeString},
+		"keywords":        {Type: llm.TypeString, Description: "Comma separated keywords"},
+	},
+	Required: []string{"title", "description", "seo_title", "seo_description", "keywords"},
+}
+
+var attributeSchema = &llm.Schema{
+	Title: "product_attributes",
+	Type:  llm.TypeObject,
+	Properties: map[string]*llm.Schema{
+		"attributes": {
+			Type: llm.TypeArray,
+			Items: &llm.Schema{
+				Type: llm.TypeObject,
+				Properties: map[string]*llm.Schema{
+					"name":       {Type: llm.TypeString, Enum: attributes.Names},
+					"value":      {Type: llm.TypeString},
+					"confidence": {Type: llm.TypeNumber, Minimum: llm.Range(0), Maximum: llm.Range(100)},
+					"source":     {Type: llm.TypeString, Enum: []string{"title", "description", "variants", "category"}},
+				},
+				Required: []string{"name", "value", "confidence"},
+			},
+		},
+	},
+	Required: []string{"attributes"},
+}
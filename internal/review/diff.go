@@ -0,0 +1,116 @@
+package review
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Diff operations
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// maxDiffTokens bounds the word diff; longer texts are shown as replaced entirely
+const maxDiffTokens = 2000
+
+// Change is a run of text that is unchanged, added or removed between two versions
+type Change struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// Diff returns the word-level changes that turn before into after. Whitespace is kept
+// with the words, so joining the equal and insert texts gives after.
+func Diff(before, after string) []Change {
+	a, b := tokenize(before), tokenize(after)
+	if len(a) > maxDiffTokens || len(b) > maxDiffTokens {
+		return compact([]Change{{DiffDelete, before}, {DiffInsert, after}})
+	}
+
+	// Longest common subsequence of tokens, filled from the end
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var changes []Change
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			changes = append(changes, Change{DiffEqual, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			changes = append(changes, Change{DiffDelete, a[i]})
+			i++
+		default:
+			changes = append(changes, Change{DiffInsert, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		changes = append(changes, Change{DiffDelete, a[i]})
+	}
+	for ; j < len(b); j++ {
+		changes = append(changes, Change{DiffInsert, b[j]})
+	}
+	return compact(changes)
+}
+
+// tokenize splits text into words and the whitespace between them
+func tokenize(text string) []string {
+	var tokens []string
+	start, space := 0, false
+	for i, r := range text {
+		if i > start && unicode.IsSpace(r) != space {
+			tokens = append(tokens, text[start:i])
+			start = i
+		}
+		space = unicode.IsSpace(r)
+	}
+	if start < len(text) {
+		tokens = append(tokens, text[start:])
+	}
+	return tokens
+}
+
+// compact merges adjacent changes with the same operation and drops empty ones
+func compact(changes []Change) []Change {
+	merged := []Change{}
+	for _, c := range changes {
+		if c.Text == "" {
+			continue
+		}
+		if n := len(merged); n > 0 && merged[n-1].Op == c.Op {
+			merged[n-1].Text += c.Text
+			continue
+		}
+		merged = append(merged, c)
+	}
+	return merged
+}
+
+// Summary counts the words added and removed by a diff
+func Summary(changes []Change) (added, removed int) {
+	for _, c := range changes {
+		switch c.Op {
+		case DiffInsert:
+			added += len(strings.Fields(c.Text))
+		case DiffDelete:
+			removed += len(strings.Fields(c.Text))
+		}
+	}
+	return added, removed
+}
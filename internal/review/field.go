@@ -0,0 +1,61 @@
+package review
+
+import (
+	"time"
+
+	"lister/internal/attributes"
+	"lister/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// field is the product field an optimization sets: a products column, or for attribute
+// optimizations a key of the attributes in the product's metadata
+type field struct {
+	column    string
+	attribute string
+}
+
+// Attribute returns the attribute an attribute optimization sets, from its metadata
+func Attribute(history *models.OptimizationHistory) string {
+	name, _ := history.Metadata["attribute"].(string)
+	return name
+}
+
+// fieldOf returns the product field history sets; ok is false for types without one
+func fieldOf(history *models.OptimizationHistory) (field, bool) {
+	if column := Column(history.OptimizationType); column != "" {
+		return field{column: column}, true
+	}
+	if history.OptimizationType == models.OptimizationTypeAttribute {
+		if name := Attribute(history); attributes.Valid(name) {
+			return field{column: "metadata", attribute: name}, true
+		}
+	}
+	return field{}, false
+}
+
+// read returns the SQL selecting the field's value as text, with its arguments
+func (f field) read() (string, []interface{}) {
+	if f.attribute == "" {
+		// column comes from Column, never from the caller
+		return "COALESCE(" + f.column + ", '')", nil
+	}
+	return "COALESCE(metadata->'attributes'->>?, '')", []interface{}{f.attribute}
+}
+
+// write returns the product updates setting the field to value. An empty attribute value
+// removes the attribute.
+func (f field) write(value string) map[string]interface{} {
+	updates := map[string]interface{}{"updated_at": time.Now()}
+	switch {
+	case f.attribute == "":
+		updates[f.column] = value
+	case value == "":
+		updates["metadata"] = gorm.Expr("COALESCE(metadata, '{}'::jsonb) #- ARRAY['attributes', ?::text]", f.attribute)
+	default:
+		updates["metadata"] = gorm.Expr(`COALESCE(metadata, '{}'::jsonb) || jsonb_build_object('attributes',
+			COALESCE(metadata->'attributes', '{}'::jsonb) || jsonb_build_object(?::text, ?::text))`, f.attribute, value)
+	}
+	return updates
+}
@@ -0,0 +1,78 @@
+package review
+
+import (
+	"errors"
+	"time"
+
+	"lister/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrNotApplied is returned when reverting an optimization that isn't applied
+	ErrNotApplied = errors.New("optimization is not applied")
+	// ErrModified is returned when the product field was edited after the optimization was
+	// applied, so reverting it would overwrite the edit
+	ErrModified = errors.New("product field was modified after the optimization was applied")
+)
+
+// Revert restores an applied optimization's original value on its product and marks it
+// reverted. It fails with ErrModified unless the product field still holds the applied
+// value.
+func Revert(db *gorm.DB, history *models.OptimizationHistory, decision Decision) error {
+	field, ok := fieldOf(history)
+	if !ok {
+		return ErrNotApplicable
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var current models.OptimizationHistory
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&current, "id = ?", history.ID).Error; err != nil {
+			return err
+		}
+		if current.Status != models.OptimizationStatusApplied {
+			return ErrNotApplied
+		}
+
+		var value string
+		read, args := field.read()
+		res := tx.Model(&models.Product{}).Where("id = ?", history.ProductID).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select(read, args...).Scan(&value)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if value != current.OptimizedValue {
+			return ErrModified
+		}
+
+		if err := tx.Model(&models.Product{}).Where("id = ?", history.ProductID).
+			Updates(field.write(current.OriginalValue)).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"status":      models.OptimizationStatusReverted,
+			"reverted_at": time.Now(),
+			"updated_at":  time.Now(),
+		}
+		if decision.Reason != "" {
+			updates["revert_reason"] = decision.Reason
+		}
+		if decision.Reviewer != "" {
+			updates["reverted_by"] = decision.Reviewer
+		}
+		return tx.Model(&models.OptimizationHistory{}).Where("id = ?", history.ID).Updates(updates).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	return db.First(history, "id = ?", history.ID).Error
+}
@@ -0,0 +1,128 @@
+// Package review decides what happens to AI suggestions: it scores them, applies the ones
+// that clear the organization's threshold automatically, applies or rejects the rest once
+// a reviewer has looked at them and reverts applied ones.
+package review
+
+import (
+	"errors"
+	"time"
+
+	"lister/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNotPending is returned when an optimization was already applied, rejected or failed
+	ErrNotPending = errors.New("optimization is not pending review")
+	// ErrNotApplicable is returned for optimization types that don't map to a product field
+	ErrNotApplicable = errors.New("optimization type cannot be applied to a product")
+)
+
+// Decision is a reviewer's verdict on a pending optimization
+type Decision struct {
+	Value    string // edited value to apply instead of the suggestion; empty applies it as is
+	Reason   string
+	Reviewer string
+}
+
+// AutoReviewer is recorded as the reviewer of optimizations applied automatically
+const AutoReviewer = "auto"
+
+// Column returns the products column an optimization type writes to, or "" when it has none.
+// Attribute optimizations write to the product's metadata instead.
+func Column(t models.OptimizationType) string {
+	switch t {
+	case models.OptimizationTypeTitle:
+		return "title"
+	case models.OptimizationTypeDescription:
+		return "description"
+	case models.OptimizationTypeCategory:
+		return "category"
+	}
+	return ""
+}
+
+// ShouldAutoApply reports whether a pending optimization is applied without review: auto
+// apply is on in the settings or was requested, approval isn't required and the score
+// clears MinScoreThreshold
+func ShouldAutoApply(settings *models.AISettings, requested bool, history *models.OptimizationHistory) bool {
+	if _, ok := fieldOf(history); !ok || history.Status != models.OptimizationStatusPending {
+		return false
+	}
+	if settings.RequireApproval || (!settings.AutoApply && !requested) {
+		return false
+	}
+	return history.Score != nil && *history.Score >= settings.MinScoreThreshold
+}
+
+// Apply writes a pending optimization to its product and marks it applied. When the
+// decision carries an edited value it is applied instead, and the original suggestion is
+// kept in SuggestedValue.
+func Apply(db *gorm.DB, history *models.OptimizationHistory, decision Decision) error {
+	field, ok := fieldOf(history)
+	if !ok {
+		return ErrNotApplicable
+	}
+
+	value := history.OptimizedValue
+	updates := reviewed(models.OptimizationStatusApplied, decision)
+	if decision.Value != "" && decision.Value != history.OptimizedValue {
+		suggested := history.OptimizedValue
+		value = decision.Value
+		updates["optimized_value"] = value
+		updates["suggested_value"] = suggested
+	}
+	updates["applied_at"] = updates["reviewed_at"]
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.OptimizationHistory{}).
+			Where("id = ? AND status = ?", history.ID, models.OptimizationStatusPending).
+			Updates(updates)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrNotPending
+		}
+
+		return tx.Model(&models.Product{}).Where("id = ?", history.ProductID).
+			Updates(field.write(value)).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	return db.First(history, "id = ?", history.ID).Error
+}
+
+// Reject marks a pending optimization rejected with the reviewer's reason
+func Reject(db *gorm.DB, history *models.OptimizationHistory, decision Decision) error {
+	res := db.Model(&models.OptimizationHistory{}).
+		Where("id = ? AND status = ?", history.ID, models.OptimizationStatusPending).
+		Updates(reviewed(models.OptimizationStatusRejected, decision))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotPending
+	}
+
+	return db.First(history, "id = ?", history.ID).Error
+}
+
+// reviewed returns the history updates recording a decision
+func reviewed(status models.OptimizationStatus, decision Decision) map[string]interface{} {
+	updates := map[string]interface{}{
+		"status":      status,
+		"reviewed_at": time.Now(),
+		"updated_at":  time.Now(),
+	}
+	if decision.Reason != "" {
+		updates["review_reason"] = decision.Reason
+	}
+	if decision.Reviewer != "" {
+		updates["reviewed_by"] = decision.Reviewer
+	}
+	return updates
+}
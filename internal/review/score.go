@@ -0,0 +1,158 @@
+package review
+
+import (
+	"strings"
+
+	"lister/internal/models"
+)
+
+// DefaultCategoryScore is given to category suggestions, which have no quality heuristic
+const DefaultCategoryScore = 85
+
+// Score rates an optimized value from 0 to 100 and estimates its improvement over the
+// original in percent
+func Score(t models.OptimizationType, original, optimized string) (int, float64) {
+	score := DefaultCategoryScore
+	switch t {
+	case models.OptimizationTypeTitle:
+		score = TitleScore(optimized, original)
+	case models.OptimizationTypeDescription:
+		score = DescriptionScore(optimized)
+	}
+	return score, Improvement(original, optimized)
+}
+
+// TitleScore rates a title on length, formatting and detail
+func TitleScore(optimized, original string) int {
+	score := 0
+
+	// Length check (50-60 optimal for SEO)
+	optLen := len(optimized)
+	if optLen >= 50 && optLen <= 60 {
+		score += 25
+	} else if optLen > 30 && optLen < 80 {
+		score += 15
+	} else {
+		score += 5
+	}
+
+	// Check if title is different from original
+	if strings.ToLower(optimized) != strings.ToLower(original) {
+		score += 15
+	}
+
+	// Check for keywords (simple heuristic)
+	words := strings.Fields(optimized)
+	if len(words) >= 5 {
+		score += 20
+	}
+
+	// Check for capital letters (proper formatting)
+	if optimized != strings.ToUpper(optimized) && optimized != strings.ToLower(optimized) {
+		score += 15
+	}
+
+	// Check for special characters (moderate use)
+	specialCount := strings.Count(optimized, "-") + strings.Count(optimized, "|") + strings.Count(optimized, "·")
+	if specialCount > 0 && specialCount <= 3 {
+		score += 10
+	}
+
+	// Check for numbers (product specs)
+	hasNumbers := strings.ContainsAny(optimized, "0123456789")
+	if hasNumbers {
+		score += 15
+	}
+
+	// Ensure score is between 0-100
+	if score > 100 {
+		score = 100
+	}
+
+	return score
+}
+
+// DescriptionScore rates a description on length, structure and sales copy
+func DescriptionScore(description string) int {
+	score := 0
+
+	// Length check
+	length := len(description)
+	if length >= 150 && length <= 300 {
+		score += 30
+	} else if length > 100 && length < 500 {
+		score += 20
+	} else {
+		score += 10
+	}
+
+	// Sentence count
+	sentences := strings.Count(description, ".") + strings.Count(description, "!") + strings.Count(description, "?")
+	if sentences >= 3 && sentences <= 8 {
+		score += 20
+	}
+
+	// Check for bullets or lists
+	hasBullets := strings.Contains(description, "•") || strings.Contains(description, "-") || strings.Contains(description, "*")
+	if hasBullets {
+		score += 15
+	}
+
+	// Check for key product terms
+	hasFeatures := strings.Contains(strings.ToLower(description), "feature") ||
+		strings.Contains(strings.ToLower(description), "benefit") ||
+		strings.Contains(strings.ToLower(description), "quality")
+	if hasFeatures {
+		score += 15
+	}
+
+	// Check for call to action
+	hasCTA := strings.Contains(strings.ToLower(description), "buy") ||
+		strings.Contains(strings.ToLower(description), "order") ||
+		strings.Contains(strings.ToLower(description), "get") ||
+		strings.Contains(strings.ToLower(description), "shop")
+	if hasCTA {
+		score += 20
+	}
+
+	if score > 100 {
+		score = 100
+	}
+
+	return score
+}
+
+// Improvement estimates how much better optimized is than original, from 0 to 100 percent
+func Improvement(original, optimized string) float64 {
+	if original == "" {
+		return 100.0
+	}
+
+	// Simple improvement calculation based on length and quality indicators
+	improvementFactor := 1.0
+
+	// Length improvement
+	if len(optimized) > len(original) {
+		improvementFactor += 0.1
+	}
+
+	// Quality indicators
+	if strings.Contains(optimized, "|") || strings.Contains(optimized, "·") {
+		improvementFactor += 0.05
+	}
+
+	if len(strings.Fields(optimized)) > len(strings.Fields(original)) {
+		improvementFactor += 0.1
+	}
+
+	// Calculate percentage
+	improvement := (improvementFactor - 1.0) * 100
+	if improvement > 100 {
+		improvement = 100
+	}
+	if improvement < 0 {
+		improvement = 0
+	}
+
+	return improvement
+}
@@ -102,9 +102,45 @@ func (c *Client) GetProduct(productID string) (*Product, error) {
 	return &productResp.Product, nil
 }
 
+// UpdateProductFields updates only the given fields of a product in Shopify, leaving the
+// rest as they are
+func (c *Client) UpdateProductFields(productID int64, fields map[string]interface{}) error {
+	url := fmt.Sprintf("https://%s.myshopify.com/admin/api/2023-10/products/%d.json", c.shopDomain, productID)
+
+	product := map[string]interface{}{"id": productID}
+	for field, value := range fields {
+		product[field] = value
+	}
+	jsonData, err := json.Marshal(map[string]interface{}{"product": product})
+	if err != nil {
+		return fmt.Errorf("failed to marshal product: %w", err)
+	}
+
+	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("X-Shopify-Access-Token", c.accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API request failed: %d - %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
 // UpdateProduct updates a product in Shopify
 func (c *Client) UpdateProduct(product *Product) error {
-	url := fmt.Sprintf("https://%s.myshopify.com/admin/api/2023-10/products/%s.json", c.shopDomain, product.ID)
+	url := fmt.Sprintf("https://%s.myshopify.com/admin/api/2023-10/products/%d.json", c.shopDomain, product.ID)
 	
 	payload := struct {
 		Product Product `json:"product"`
@@ -66,7 +66,8 @@ func (t *Transformer) TransformProduct(shopifyProduct *Product) (*models.Product
 
 	// Transform shipping info
 	shipping := &models.ShippingInfo{
-		Weight: &primaryVariant.Weight,
+		Weight:     &primaryVariant.Weight,
+		WeightUnit: &primaryVariant.WeightUnit,
 		Dimensions: &models.Dimensions{
 			Length: 0, // Shopify doesn't provide dimensions by default
 			Width:  0,
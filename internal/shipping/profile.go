@@ -0,0 +1,289 @@
+// Package shipping works out feed items' shipping from an organization's shipping
+// profiles: the options a profile's zones quote for a destination country, priced by
+// weight or price band with a free-shipping threshold and handling and transit times,
+// the tax rates that apply, and the unit conversions channels need for weights and
+// dimensions.
+package shipping
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Rate bases: what a zone's rate bands are measured in
+const (
+	BasisFlat   = "flat"
+	BasisWeight = "weight"
+	BasisPrice  = "price"
+)
+
+// Profile is a set of shipping zones and tax rates. Products whose shipping_label is one
+// of Labels use the profile; others use the organization's default profile. Rates and
+// thresholds are in Currency, and weight bands in WeightUnit, which is also the unit
+// feeds list weights in; dimensions are listed in DimensionUnit.
+type Profile struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Labels        []string  `json:"labels"`
+	Default       bool      `json:"is_default"`
+	Currency      string    `json:"currency,omitempty"`
+	WeightUnit    string    `json:"weight_unit"`
+	DimensionUnit string    `json:"dimension_unit"`
+	Zones         []Zone    `json:"zones"`
+	TaxRates      []TaxRate `json:"tax_rates"`
+}
+
+// Zone is a shipping service to a set of countries, optionally limited to a region such
+// as a US state. Rates are bands of Basis: the band whose [min, max) holds the item's
+// weight or price sets the price; flat zones have one rate. Items at or over
+// FreeThreshold ship free.
+type Zone struct {
+	Name          string   `json:"name"`
+	Countries     []string `json:"countries"`
+	Region        string   `json:"region,omitempty"`
+	Service       string   `json:"service"`
+	Basis         string   `json:"basis"`
+	Rates         []Rate   `json:"rates"`
+	FreeThreshold float64  `json:"free_shipping_threshold,omitempty"`
+	HandlingDays  Days     `json:"handling_days"`
+	TransitDays   Days     `json:"transit_days"`
+}
+
+// Rate is a band's price; Max 0 leaves the band open-ended
+type Rate struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max,omitempty"`
+	Price float64 `json:"price"`
+}
+
+// Days is a range of business days
+type Days struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// TaxRate is a sales tax rate in percent for a country, or one of its regions. A rate
+// with a TaxClass applies only to products of that tax class, and wins over rates
+// without one.
+type TaxRate struct {
+	Country  string  `json:"country"`
+	Region   string  `json:"region,omitempty"`
+	Rate     float64 `json:"rate"`
+	TaxShip  bool    `json:"tax_ship,omitempty"`
+	TaxClass string  `json:"tax_class,omitempty"`
+}
+
+// Item is what a quote is for: its listed price in the profile's currency and its
+// weight in the profile's weight unit
+type Item struct {
+	Price  float64
+	Weight float64
+}
+
+// Option is a quoted shipping option, priced in the profile's currency
+type Option struct {
+	Country         string  `json:"country"`
+	Region          string  `json:"region,omitempty"`
+	Service         string  `json:"service,omitempty"`
+	Price           float64 `json:"price"`
+	MinHandlingTime int     `json:"min_handling_time,omitempty"`
+	MaxHandlingTime int     `json:"max_handling_time,omitempty"`
+	MinTransitTime  int     `json:"min_transit_time,omitempty"`
+	MaxTransitTime  int     `json:"max_transit_time,omitempty"`
+}
+
+// Validate checks a profile and normalizes its codes and units, defaulting to kg and cm
+func (p *Profile) Validate() error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	p.Currency = strings.ToUpper(strings.TrimSpace(p.Currency))
+	if p.Currency != "" && len(p.Currency) != 3 {
+		return fmt.Errorf("currency must be a 3-letter code")
+	}
+	if p.WeightUnit = Unit(p.WeightUnit); p.WeightUnit == "" {
+		p.WeightUnit = "kg"
+	}
+	if !oneOf(p.WeightUnit, FeedWeightUnits) {
+		return fmt.Errorf("weight_unit must be one of %s", strings.Join(FeedWeightUnits, ", "))
+	}
+	if p.DimensionUnit = Unit(p.DimensionUnit); p.DimensionUnit == "" {
+		p.DimensionUnit = "cm"
+	}
+	if !oneOf(p.DimensionUnit, FeedDimensionUnits) {
+		return fmt.Errorf("dimension_unit must be one of %s", strings.Join(FeedDimensionUnits, ", "))
+	}
+	if p.Labels == nil {
+		p.Labels = []string{}
+	}
+	for i := range p.Labels {
+		p.Labels[i] = strings.TrimSpace(p.Labels[i])
+	}
+	if p.Zones == nil {
+		p.Zones = []Zone{}
+	}
+	if p.TaxRates == nil {
+		p.TaxRates = []TaxRate{}
+	}
+
+	for i := range p.Zones {
+		if err := p.Zones[i].validate(); err != nil {
+			return fmt.Errorf("zone %d: %w", i+1, err)
+		}
+	}
+	for i := range p.TaxRates {
+		tax := &p.TaxRates[i]
+		tax.Country = strings.ToUpper(strings.TrimSpace(tax.Country))
+		if len(tax.Country) != 2 {
+			return fmt.Errorf("tax rate %d needs a 2-letter country code", i+1)
+		}
+		if tax.Rate < 0 || tax.Rate > 100 {
+			return fmt.Errorf("tax rate %d must be between 0 and 100", i+1)
+		}
+	}
+	return nil
+}
+
+func (z *Zone) validate() error {
+	if len(z.Countries) == 0 {
+		return fmt.Errorf("countries is required")
+	}
+	for i, country := range z.Countries {
+		z.Countries[i] = strings.ToUpper(strings.TrimSpace(country))
+		if len(z.Countries[i]) != 2 {
+			return fmt.Errorf("%q is not a 2-letter country code", country)
+		}
+	}
+	if z.Basis == "" {
+		z.Basis = BasisFlat
+	}
+	switch z.Basis {
+	case BasisFlat:
+		if len(z.Rates) != 1 {
+			return fmt.Errorf("a flat zone needs exactly one rate")
+		}
+	case BasisWeight, BasisPrice:
+		if len(z.Rates) == 0 {
+			return fmt.Errorf("a %s zone needs at least one rate band", z.Basis)
+		}
+	default:
+		return fmt.Errorf("basis must be %s, %s or %s", BasisFlat, BasisWeight, BasisPrice)
+	}
+	for _, rate := range z.Rates {
+		if rate.Price < 0 || rate.Min < 0 || (rate.Max != 0 && rate.Max <= rate.Min) {
+			return fmt.Errorf("rate bands need a price of 0 or more and max above min")
+		}
+	}
+	sort.Slice(z.Rates, func(i, j int) bool { return z.Rates[i].Min < z.Rates[j].Min })
+	if z.FreeThreshold < 0 {
+		return fmt.Errorf("free_shipping_threshold can't be negative")
+	}
+	if z.HandlingDays.Min < 0 || z.HandlingDays.Max < z.HandlingDays.Min || z.TransitDays.Min < 0 || z.TransitDays.Max < z.TransitDays.Min {
+		return fmt.Errorf("handling and transit days need 0 <= min <= max")
+	}
+	return nil
+}
+
+// Quote returns the item's shipping options to country, one per zone that ships there
+// with a rate band for the item, or to every country the profile ships to when country
+// is empty
+func (p *Profile) Quote(country string, item Item) []Option {
+	country = strings.ToUpper(country)
+	var options []Option
+	for _, zone := range p.Zones {
+		price, ok := zone.price(item)
+		if !ok {
+			continue
+		}
+		for _, c := range zone.Countries {
+			if country != "" && c != country {
+				continue
+			}
+			options = append(options, Option{
+				Country:         c,
+				Region:          zone.Region,
+				Service:         zone.Service,
+				Price:           price,
+				MinHandlingTime: zone.HandlingDays.Min,
+				MaxHandlingTime: zone.HandlingDays.Max,
+				MinTransitTime:  zone.TransitDays.Min,
+				MaxTransitTime:  zone.TransitDays.Max,
+			})
+		}
+	}
+	return options
+}
+
+// price returns the zone's price for the item, or false when no band holds it
+func (z Zone) price(item Item) (float64, bool) {
+	if z.FreeThreshold > 0 && item.Price >= z.FreeThreshold {
+		return 0, true
+	}
+	measure := item.Price
+	switch z.Basis {
+	case BasisFlat:
+		return z.Rates[0].Price, true
+	case BasisWeight:
+		measure = item.Weight
+	}
+	measure = math.Round(measure*1000) / 1000
+	for _, rate := range z.Rates {
+		if measure >= rate.Min && (rate.Max == 0 || measure < rate.Max) {
+			return rate.Price, true
+		}
+	}
+	return 0, false
+}
+
+// Taxes returns the tax rates for products of taxClass in country, or in every country
+// the profile has rates for when country is empty. For each country and region, a rate
+// for the tax class wins over one for all classes.
+func (p *Profile) Taxes(country, taxClass string) []TaxRate {
+	country = strings.ToUpper(country)
+	best := map[string]TaxRate{}
+	var keys []string
+	for _, tax := range p.TaxRates {
+		if country != "" && tax.Country != country {
+			continue
+		}
+		if tax.TaxClass != "" && !strings.EqualFold(tax.TaxClass, taxClass) {
+			continue
+		}
+		key := tax.Country + ":" + strings.ToUpper(tax.Region)
+		current, seen := best[key]
+		if !seen {
+			keys = append(keys, key)
+		}
+		if !seen || (current.TaxClass == "" && tax.TaxClass != "") {
+			best[key] = tax
+		}
+	}
+	taxes := make([]TaxRate, 0, len(keys))
+	for _, key := range keys {
+		taxes = append(taxes, best[key])
+	}
+	return taxes
+}
+
+// Select returns the profile for a product's shipping label: the profile listing the
+// label, else the default profile, else nil
+func Select(profiles []Profile, label string) *Profile {
+	var fallback *Profile
+	for i := range profiles {
+		profile := &profiles[i]
+		if label != "" {
+			for _, l := range profile.Labels {
+				if strings.EqualFold(l, label) {
+					return profile
+				}
+			}
+		}
+		if profile.Default && fallback == nil {
+			fallback = profile
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,73 @@
+package shipping
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// grams and centimetres per unit
+var (
+	weightUnits = map[string]float64{"g": 1, "kg": 1000, "oz": 28.349523125, "lb": 453.59237}
+	lengthUnits = map[string]float64{"mm": 0.1, "cm": 1, "m": 100, "in": 2.54, "ft": 30.48}
+)
+
+var unitAliases = map[string]string{
+	"gram": "g", "grams": "g", "kgs": "kg", "kilogram": "kg", "kilograms": "kg",
+	"ounce": "oz", "ounces": "oz", "lbs": "lb", "pound": "lb", "pounds": "lb",
+	"millimeter": "mm", "millimeters": "mm", "centimeter": "cm", "centimeters": "cm",
+	"meter": "m", "meters": "m", "inch": "in", "inches": "in", "foot": "ft", "feet": "ft",
+}
+
+// Channel units: Google and Meta accept weights in these units and dimensions in these
+var (
+	FeedWeightUnits    = []string{"kg", "g", "lb", "oz"}
+	FeedDimensionUnits = []string{"cm", "in"}
+)
+
+// Unit returns a weight or length unit's short form, such as "lb" for "pounds"
+func Unit(unit string) string {
+	unit = strings.ToLower(strings.TrimSpace(unit))
+	if alias, ok := unitAliases[unit]; ok {
+		return alias
+	}
+	return unit
+}
+
+// ConvertWeight converts a weight between g, kg, oz and lb
+func ConvertWeight(value float64, from, to string) (float64, error) {
+	return convert(weightUnits, value, from, to)
+}
+
+// ConvertLength converts a length between mm, cm, m, in and ft
+func ConvertLength(value float64, from, to string) (float64, error) {
+	return convert(lengthUnits, value, from, to)
+}
+
+func convert(units map[string]float64, value float64, from, to string) (float64, error) {
+	fromFactor, ok := units[Unit(from)]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", from)
+	}
+	toFactor, ok := units[Unit(to)]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", to)
+	}
+	return value * fromFactor / toFactor, nil
+}
+
+// Format writes a measurement as channels expect it, such as "1.25 kg", with at most
+// three decimals
+func Format(value float64, unit string) string {
+	return strconv.FormatFloat(math.Round(value*1000)/1000, 'f', -1, 64) + " " + Unit(unit)
+}
+
+func oneOf(unit string, units []string) bool {
+	for _, u := range units {
+		if unit == u {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package taxonomy
+
+import "strings"
+
+// Mapping sources: what a store value a mapping applies to is
+const (
+	SourceProductType = "product_type"
+	SourceCollection  = "collection"
+)
+
+// Sources lists the mapping sources
+var Sources = []string{SourceProductType, SourceCollection}
+
+// ValidSource reports whether source is one of Sources
+func ValidSource(source string) bool {
+	return source == SourceProductType || source == SourceCollection
+}
+
+// Mappings are an organization's store product types and collections mapped to nodes of
+// a taxonomy. Store values are compared case-insensitively.
+type Mappings struct {
+	productTypes map[string]int
+	collections  map[string]int
+}
+
+// Add maps a product type or collection to a node ID
+func (m *Mappings) Add(source, value string, id int) {
+	if m.productTypes == nil {
+		m.productTypes = make(map[string]int)
+		m.collections = make(map[string]int)
+	}
+	key := strings.ToLower(strings.TrimSpace(value))
+	switch source {
+	case SourceProductType:
+		m.productTypes[key] = id
+	case SourceCollection:
+		m.collections[key] = id
+	}
+}
+
+// Categorize picks a product's node: the mapping of its product type, then the first
+// mapped collection, then the product type itself when it is an ID or path of t.
+func (t *Taxonomy) Categorize(m Mappings, productType string, collections []string) (*Node, bool) {
+	if id, ok := m.productTypes[strings.ToLower(strings.TrimSpace(productType))]; ok {
+		if node, ok := t.ByID(id); ok {
+			return node, true
+		}
+	}
+	for _, collection := range collections {
+		if id, ok := m.collections[strings.ToLower(strings.TrimSpace(collection))]; ok {
+			if node, ok := t.ByID(id); ok {
+				return node, true
+			}
+		}
+	}
+	return t.Resolve(productType)
+}
+
+// MinSnapScore is the search score from which Snap accepts a node
+const MinSnapScore = 0.6
+
+// Snap returns the node s refers to, or the best search match for it when that scores
+// at least MinSnapScore. It turns free-form category suggestions into taxonomy nodes.
+func (t *Taxonomy) Snap(s string) (*Node, bool) {
+	if node, ok := t.Resolve(s); ok {
+		return node, true
+	}
+	// Search the most specific part of a path first
+	query := s
+	if i := strings.LastIndex(s, ">"); i >= 0 {
+		query = s[i+1:]
+	}
+	for _, q := range []string{query, s} {
+		if matches := t.Search(q, 1); len(matches) > 0 && matches[0].Score >= MinSnapScore {
+			return matches[0].Node, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,180 @@
+package taxonomy
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Match is a search result with its relevance from 0 to 1
+type Match struct {
+	*Node
+	Score float64 `json:"score"`
+}
+
+// Search returns the nodes best matching a free-text query such as a product type or
+// title, most relevant first. Words match a node's name more strongly than its parents'
+// names; plurals, prefixes and single typos still match. A query that is an ID or a path
+// returns that node first.
+func (t *Taxonomy) Search(query string, limit int) []Match {
+	if limit <= 0 {
+		limit = 10
+	}
+	var matches []Match
+	if node, ok := t.Resolve(query); ok {
+		matches = append(matches, Match{Node: node, Score: 1})
+	}
+
+	words := tokenize(query)
+	if len(words) == 0 {
+		return matches
+	}
+	for _, node := range t.nodes {
+		if len(matches) > 0 && matches[0].Node == node {
+			continue
+		}
+		if score := relevance(words, node); score > 0 {
+			matches = append(matches, Match{Node: node, Score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		// More specific categories first
+		return matches[i].Level > matches[j].Level
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// Candidate limits: a category prompt lists at most MaxCandidates paths, of which each
+// query contributes at most candidatesPerQuery
+const (
+	MaxCandidates      = 25
+	candidatesPerQuery = 10
+)
+
+// Candidates returns the paths of the nodes best matching each of queries, in query
+// order, for prompts that must choose a category of t
+func (t *Taxonomy) Candidates(queries ...string) []string {
+	var candidates []string
+	seen := make(map[int]bool)
+	for _, query := range queries {
+		if strings.TrimSpace(query) == "" {
+			continue
+		}
+		for _, match := range t.Search(query, candidatesPerQuery) {
+			if seen[match.ID] || len(candidates) == MaxCandidates {
+				continue
+			}
+			seen[match.ID] = true
+			candidates = append(candidates, match.Path)
+		}
+	}
+	return candidates
+}
+
+// relevance scores how well query words match a node: 3 per word matching its name, 1 per
+// word matching a parent's, 2 and 0.5 for near matches, over 3 per word
+func relevance(words []string, node *Node) float64 {
+	name := tokenize(node.Name)
+	parents := tokenize(strings.TrimSuffix(node.Path, node.Name))
+
+	score := 0.0
+	for _, word := range words {
+		switch {
+		case contains(name, word, exact):
+			score += 3
+		case contains(name, word, near):
+			score += 2
+		case contains(parents, word, exact):
+			score += 1
+		case contains(parents, word, near):
+			score += 0.5
+		}
+	}
+	return score / float64(3*len(words))
+}
+
+func contains(tokens []string, word string, match func(a, b string) bool) bool {
+	for _, token := range tokens {
+		if match(token, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func exact(a, b string) bool {
+	return a == b
+}
+
+// near matches prefixes of at least 4 letters and one typo in words of 6 letters or more,
+// so short words such as "shirt" and "skirt" stay apart
+func near(a, b string) bool {
+	if len(a) >= 4 && len(b) >= 4 && (strings.HasPrefix(a, b) || strings.HasPrefix(b, a)) {
+		return true
+	}
+	return max(len(a), len(b)) >= 6 && min(len(a), len(b)) >= 5 && withinOneEdit(a, b)
+}
+
+// withinOneEdit reports whether a and b differ by at most one insertion, deletion or
+// substitution
+func withinOneEdit(a, b string) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	if len(b)-len(a) > 1 {
+		return false
+	}
+	i, j, edits := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if a[i] == b[j] {
+			i++
+			j++
+			continue
+		}
+		edits++
+		if edits > 1 {
+			return false
+		}
+		if len(a) == len(b) {
+			i++
+		}
+		j++
+	}
+	return edits+(len(b)-j)+(len(a)-i) <= 1
+}
+
+// tokenize splits text into lower-case singular words, dropping "&", "and" and other
+// separators
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	words := fields[:0]
+	for _, field := range fields {
+		if field == "and" || field == "for" || field == "the" || field == "with" {
+			continue
+		}
+		words = append(words, singular(field))
+	}
+	return words
+}
+
+// singular strips common English plural endings: "dresses" → "dress", "shoes" → "shoe"
+func singular(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "sses"):
+		return strings.TrimSuffix(word, "es")
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return strings.TrimSuffix(word, "ies") + "y"
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
@@ -0,0 +1,228 @@
+// Package taxonomy loads the product taxonomies shopping channels categorize products
+// with — Google's google_product_category and Meta's — and looks nodes up by ID, path or
+// fuzzy search. Taxonomies are read from the official "ID - Parent > Child" text files:
+// a configured directory's google.txt and meta.txt take precedence over the bundled files.
+package taxonomy
+
+import (
+	"bufio"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Taxonomy names
+const (
+	Google = "google"
+	Meta   = "meta"
+)
+
+// Names lists the supported taxonomies
+var Names = []string{Google, Meta}
+
+// ErrUnknown is returned for taxonomies other than Names
+var ErrUnknown = errors.New("unknown taxonomy")
+
+//go:embed data/*.txt
+var bundled embed.FS
+
+// Node is a category of a taxonomy
+type Node struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	ParentID int    `json:"parent_id,omitempty"`
+	Level    int    `json:"level"`
+}
+
+// Taxonomy is a loaded taxonomy
+type Taxonomy struct {
+	Name    string
+	Version string
+
+	nodes    []*Node
+	byID     map[int]*Node
+	byPath   map[string]*Node
+	children map[int][]*Node
+}
+
+var (
+	mu     sync.Mutex
+	loaded = make(map[string]*Taxonomy)
+)
+
+// Load returns the named taxonomy, read from dir/<name>.txt when that exists and from the
+// bundled file otherwise. Taxonomies are parsed once and shared.
+func Load(name, dir string) (*Taxonomy, error) {
+	if !Valid(name) {
+		return nil, fmt.Errorf("%w %q", ErrUnknown, name)
+	}
+	key := name + "\x00" + dir
+
+	mu.Lock()
+	defer mu.Unlock()
+	if t, ok := loaded[key]; ok {
+		return t, nil
+	}
+
+	var r io.ReadCloser
+	var err error
+	if dir != "" {
+		r, err = os.Open(filepath.Join(dir, name+".txt"))
+	}
+	if dir == "" || errors.Is(err, os.ErrNotExist) {
+		r, err = bundled.Open("data/" + name + ".txt")
+		if errors.Is(err, os.ErrNotExist) {
+			// Nothing bundled: the taxonomy is empty until its file is configured
+			t := newTaxonomy(name)
+			loaded[key] = t
+			return t, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	t, err := Parse(name, r)
+	if err != nil {
+		return nil, err
+	}
+	loaded[key] = t
+	return t, nil
+}
+
+// Valid reports whether name is a supported taxonomy
+func Valid(name string) bool {
+	for _, n := range Names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse reads a taxonomy in the official format: one "ID - Parent > Child" line per node,
+// parents before children. Lines starting with # are comments; a
+// "# Google_Product_Taxonomy_Version: ..." comment sets the version.
+func Parse(name string, r io.Reader) (*Taxonomy, error) {
+	t := newTaxonomy(name)
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if strings.HasPrefix(text, "#") {
+			if _, version, ok := strings.Cut(text, "Version:"); ok && t.Version == "" {
+				t.Version = strings.TrimSpace(version)
+			}
+			continue
+		}
+
+		idText, path, ok := strings.Cut(text, " - ")
+		id, err := strconv.Atoi(strings.TrimSpace(idText))
+		if !ok || err != nil {
+			return nil, fmt.Errorf("%s taxonomy line %d: expected \"ID - Path\"", name, line)
+		}
+		parts := strings.Split(path, ">")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		node := &Node{
+			ID:    id,
+			Name:  parts[len(parts)-1],
+			Path:  strings.Join(parts, " > "),
+			Level: len(parts),
+		}
+		if len(parts) > 1 {
+			if parent, ok := t.byPath[pathKey(strings.Join(parts[:len(parts)-1], " > "))]; ok {
+				node.ParentID = parent.ID
+			}
+		}
+		t.add(node)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func newTaxonomy(name string) *Taxonomy {
+	return &Taxonomy{
+		Name:     name,
+		byID:     make(map[int]*Node),
+		byPath:   make(map[string]*Node),
+		children: make(map[int][]*Node),
+	}
+}
+
+func (t *Taxonomy) add(node *Node) {
+	t.nodes = append(t.nodes, node)
+	t.byID[node.ID] = node
+	t.byPath[pathKey(node.Path)] = node
+	t.children[node.ParentID] = append(t.children[node.ParentID], node)
+}
+
+// Len returns the number of nodes
+func (t *Taxonomy) Len() int {
+	return len(t.nodes)
+}
+
+// ByID returns the node with an ID
+func (t *Taxonomy) ByID(id int) (*Node, bool) {
+	node, ok := t.byID[id]
+	return node, ok
+}
+
+// ByPath returns the node with a path, compared case-insensitively
+func (t *Taxonomy) ByPath(path string) (*Node, bool) {
+	node, ok := t.byPath[pathKey(path)]
+	return node, ok
+}
+
+// Resolve returns the node an ID ("5598") or path ("Apparel & Accessories > Clothing")
+// refers to
+func (t *Taxonomy) Resolve(s string) (*Node, bool) {
+	s = strings.TrimSpace(s)
+	if id, err := strconv.Atoi(s); err == nil {
+		return t.ByID(id)
+	}
+	return t.ByPath(s)
+}
+
+// Children returns a node's children, or the top-level nodes for 0
+func (t *Taxonomy) Children(id int) []*Node {
+	return t.children[id]
+}
+
+// Ancestors returns a node's parents, top-level first
+func (t *Taxonomy) Ancestors(node *Node) []*Node {
+	var ancestors []*Node
+	for node.ParentID != 0 {
+		parent, ok := t.byID[node.ParentID]
+		if !ok {
+			break
+		}
+		ancestors = append([]*Node{parent}, ancestors...)
+		node = parent
+	}
+	return ancestors
+}
+
+// pathKey normalizes a path for lookups: lower-cased with single spaces around ">"
+func pathKey(path string) string {
+	parts := strings.Split(strings.ToLower(path), ">")
+	for i := range parts {
+		parts[i] = strings.Join(strings.Fields(parts[i]), " ")
+	}
+	return strings.Join(parts, " > ")
+}
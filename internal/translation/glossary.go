@@ -0,0 +1,118 @@
+package translation
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"lister/internal/models"
+	"lister/internal/prompts"
+)
+
+// Glossary is the part of an organization's glossary that applies to one locale
+type Glossary struct {
+	// Terms maps source terms to their fixed translation
+	Terms map[string]string
+	// Protected terms are kept as written
+	Protected []string
+}
+
+// ForLocale selects the glossary terms for locale. Terms for the exact locale override
+// those for its language, which override terms without a locale.
+func ForLocale(terms []models.GlossaryTerm, locale string) Glossary {
+	specificity := func(t models.GlossaryTerm) int {
+		switch {
+		case t.Locale == nil || *t.Locale == "":
+			return 0
+		case strings.EqualFold(*t.Locale, locale):
+			return 2
+		case strings.EqualFold(*t.Locale, Language(locale)):
+			return 1
+		}
+		return -1
+	}
+
+	sorted := make([]models.GlossaryTerm, 0, len(terms))
+	for _, t := range terms {
+		if specificity(t) >= 0 {
+			sorted = append(sorted, t)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return specificity(sorted[i]) < specificity(sorted[j]) })
+
+	// Later, more specific terms replace earlier ones
+	entries := map[string]models.GlossaryTerm{}
+	for _, t := range sorted {
+		entries[strings.ToLower(t.Term)] = t
+	}
+
+	glossary := Glossary{Terms: map[string]string{}}
+	for _, t := range entries {
+		if t.DoNotTranslate {
+			glossary.Protected = append(glossary.Protected, t.Term)
+		} else if t.Translation != nil && *t.Translation != "" {
+			glossary.Terms[t.Term] = *t.Translation
+		}
+	}
+	// Longest first, so "Acme Pro" is protected before "Acme"
+	sort.Slice(glossary.Protected, func(i, j int) bool {
+		return len(glossary.Protected[i]) > len(glossary.Protected[j])
+	})
+	return glossary
+}
+
+// Protect replaces the protected terms in text with numbered placeholders such as [[0]]
+func (g Glossary) Protect(text string) string {
+	for i, term := range g.Protected {
+		text = termPattern(term).ReplaceAllString(text, placeholder(i))
+	}
+	return text
+}
+
+// Restore puts the protected terms back in place of their placeholders
+func (g Glossary) Restore(text string) string {
+	for i, term := range g.Protected {
+		text = strings.ReplaceAll(text, placeholder(i), term)
+	}
+	return text
+}
+
+// ProtectProduct protects the glossary's terms in the product fields a translation
+// prompt translates
+func (g Glossary) ProtectProduct(p prompts.Product) prompts.Product {
+	p.Title = g.Protect(p.Title)
+	p.Description = g.Protect(p.Description)
+	p.SEOTitle = g.Protect(p.SEOTitle)
+	p.SEODescription = g.Protect(p.SEODescription)
+	p.SEOKeywords = g.Protect(p.SEOKeywords)
+	return p
+}
+
+// RestoreTranslation restores the protected terms in a translation
+func (g Glossary) RestoreTranslation(t prompts.Translation) prompts.Translation {
+	t.Title = g.Restore(t.Title)
+	t.Description = g.Restore(t.Description)
+	t.SEOTitle = g.Restore(t.SEOTitle)
+	t.SEODescription = g.Restore(t.SEODescription)
+	t.Keywords = g.Restore(t.Keywords)
+	return t
+}
+
+func placeholder(i int) string {
+	return fmt.Sprintf("[[%d]]", i)
+}
+
+var wordChar = regexp.MustCompile(`\w`)
+
+// termPattern matches term as a whole word, ignoring case
+func termPattern(term string) *regexp.Regexp {
+	pattern := regexp.QuoteMeta(term)
+	if wordChar.MatchString(term[:1]) {
+		pattern = `\b` + pattern
+	}
+	if wordChar.MatchString(term[len(term)-1:]) {
+		pattern += `\b`
+	}
+	return regexp.MustCompile(`(?i)` + pattern)
+}
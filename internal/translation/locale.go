@@ -0,0 +1,75 @@
+// Package translation resolves locales and glossaries for per-locale product translations.
+// Translations are generated through the AI pipeline with the organization's glossary:
+// fixed translations are passed to the model, and do-not-translate terms are swapped for
+// placeholders so the model can't alter them.
+package translation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NormalizeLocale turns codes such as "DE", "de_de" or "de-DE" into "de" or "de-DE"
+func NormalizeLocale(code string) (string, error) {
+	code = strings.TrimSpace(strings.ReplaceAll(code, "_", "-"))
+	parts := strings.Split(code, "-")
+	if len(parts) > 2 || !letters(parts[0], 2, 3) {
+		return "", fmt.Errorf("invalid locale %q", code)
+	}
+	locale := strings.ToLower(parts[0])
+	if len(parts) == 2 {
+		if !letters(parts[1], 2, 2) {
+			return "", fmt.Errorf("invalid locale %q", code)
+		}
+		locale += "-" + strings.ToUpper(parts[1])
+	}
+	return locale, nil
+}
+
+// Language returns the language of a locale: "de" for "de-CH"
+func Language(locale string) string {
+	language, _, _ := strings.Cut(locale, "-")
+	return language
+}
+
+// Candidates returns the locales a feed for language and country looks for, most
+// specific first: "de" and "CH" give ["de-CH", "de"]. An empty language gives none, so
+// the product's own fields are used.
+func Candidates(language, country string) []string {
+	locale, err := NormalizeLocale(language)
+	if err != nil {
+		return nil
+	}
+	candidates := []string{}
+	if !strings.Contains(locale, "-") && country != "" {
+		if regional, err := NormalizeLocale(locale + "-" + country); err == nil {
+			candidates = append(candidates, regional)
+		}
+	}
+	candidates = append(candidates, locale)
+	if lang := Language(locale); lang != locale {
+		candidates = append(candidates, lang)
+	}
+	return candidates
+}
+
+// IsSource reports whether locale is in the source language, whose content is the
+// product's own fields
+func IsSource(locale, sourceLanguage string) bool {
+	if sourceLanguage == "" {
+		sourceLanguage = "en"
+	}
+	return strings.EqualFold(Language(locale), Language(sourceLanguage))
+}
+
+func letters(s string, min, max int) bool {
+	if len(s) < min || len(s) > max {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
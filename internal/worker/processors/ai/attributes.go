@@ -0,0 +1,20 @@
+package ai
+
+import (
+	"lister/internal/models"
+	"lister/internal/prompts"
+)
+
+// ExtractAttributes reads product's color, size, material, gender and age group from its
+// title, description, category and variant options, each with the model's confidence.
+// Responses that don't match the attribute schema fail with an *llm.StructuredError.
+func (o *Optimizer) ExtractAttributes(product interface{}) (*prompts.ExtractedAttributes, error) {
+	o.logger.Debug("Extracting attributes for product: %+v", product)
+
+	var result prompts.ExtractedAttributes
+	if err := o.completeStructured(models.OptimizationTypeAttribute, o.promptData(product), &result); err != nil {
+		o.logger.Error("AI attribute extraction failed: %v", err)
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,83 @@
+package ai
+
+import (
+	"time"
+
+	"lister/internal/models"
+	"lister/internal/prompts"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CacheKey returns the content address of optimizing product (see prompts.CacheKey), or ""
+// when the prompt can't be rendered
+func (o *Optimizer) CacheKey(t models.OptimizationType, product interface{}) string {
+	tmpl := o.Template(t)
+	rendered, err := prompts.Render(tmpl, o.promptData(product))
+	if err != nil {
+		return ""
+	}
+	return prompts.CacheKey(tmpl, o.model, rendered)
+}
+
+// CachedResponse returns the unexpired cached result stored under key and counts the hit.
+// It returns nil when the organization disabled the cache.
+func CachedResponse(db *gorm.DB, settings *models.AISettings, key string) *models.AIResponseCache {
+	if key == "" || settings.CacheTTLHours <= 0 {
+		return nil
+	}
+
+	var entry models.AIResponseCache
+	err := db.Where("organization_id = ? AND cache_key = ? AND expires_at > ?", settings.OrganizationID, key, time.Now()).
+		First(&entry).Error
+	if err != nil {
+		return nil
+	}
+
+	db.Model(&entry).Updates(map[string]interface{}{
+		"hits":        gorm.Expr("hits + 1"),
+		"last_hit_at": time.Now(),
+	})
+	return &entry
+}
+
+// CacheResponse stores the result of an optimization under key for the organization's
+// cache TTL, replacing an expired or bypassed entry
+func CacheResponse(db *gorm.DB, settings *models.AISettings, key string, tmpl *models.PromptTemplate, model, response string) error {
+	if key == "" || response == "" || settings.CacheTTLHours <= 0 {
+		return nil
+	}
+
+	entry := &models.AIResponseCache{
+		OrganizationID:   settings.OrganizationID,
+		CacheKey:         key,
+		OptimizationType: tmpl.OptimizationType,
+		Model:            model,
+		PromptVersion:    tmpl.Version,
+		Response:         response,
+		CreatedAt:        time.Now(),
+		ExpiresAt:        time.Now().Add(time.Duration(settings.CacheTTLHours) * time.Hour),
+	}
+	if !tmpl.Builtin() {
+		id := tmpl.ID
+		entry.PromptTemplateID = &id
+	}
+
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "organization_id"}, {Name: "cache_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"model", "response", "hits", "created_at", "expires_at"}),
+	}).Create(entry).Error
+}
+
+// PurgeCache removes an organization's cached results, or only those of one optimization
+// type, and returns how many were removed
+func PurgeCache(db *gorm.DB, organizationID uuid.UUID, t models.OptimizationType) (int64, error) {
+	query := db.Where("organization_id = ?", organizationID)
+	if t != "" {
+		query = query.Where("optimization_type = ?", t)
+	}
+	result := query.Delete(&models.AIResponseCache{})
+	return result.RowsAffected, result.Error
+}
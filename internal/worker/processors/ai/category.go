@@ -0,0 +1,36 @@
+package ai
+
+import (
+	"fmt"
+	"sort"
+
+	"lister/internal/models"
+	"lister/internal/prompts"
+	"lister/internal/taxonomy"
+)
+
+// SuggestTaxonomyCategory suggests a node of t for product. The prompt lists the nodes a
+// search of t for the product's category and title finds, and the most confident
+// suggestion that is, or snaps to, a node of t wins.
+func (o *Optimizer) SuggestTaxonomyCategory(product interface{}, t *taxonomy.Taxonomy) (*taxonomy.Node, error) {
+	o.logger.Debug("Suggesting %s category for product: %+v", t.Name, product)
+
+	data := o.promptData(product)
+	data.Options.Categories = t.Candidates(data.Product.Category, data.Product.ProductType, data.Product.Title)
+
+	var suggestions prompts.CategorySuggestions
+	if err := o.completeStructured(models.OptimizationTypeCategory, data, &suggestions); err != nil {
+		o.logger.Error("AI category suggestion failed: %v", err)
+		return nil, err
+	}
+
+	sort.SliceStable(suggestions.Suggestions, func(i, j int) bool {
+		return suggestions.Suggestions[i].Confidence > suggestions.Suggestions[j].Confidence
+	})
+	for _, suggestion := range suggestions.Suggestions {
+		if node, ok := t.Snap(suggestion.Category); ok {
+			return node, nil
+		}
+	}
+	return nil, fmt.Errorf("no suggested category is in the %s taxonomy", t.Name)
+}
@@ -1,42 +1,30 @@
 package ai
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"strings"
 	"time"
 
 	"lister/internal/config"
+	"lister/internal/llm"
 	"lister/internal/logger"
+	"lister/internal/models"
+	"lister/internal/prompts"
+	"lister/internal/taxonomy"
 )
 
 type Optimizer struct {
-	config *config.Config
-	logger *logger.Logger
-}
-
-// OpenAI API structures
-type OpenAIRequest struct {
-	Model       string    `json:"model"`
-	Messages    []Message `json:"messages"`
-	Temperature float64   `json:"temperature"`
-	MaxTokens   int       `json:"max_tokens"`
-}
-
-type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-type OpenAIResponse struct {
-	Choices []Choice `json:"choices"`
-}
-
-type Choice struct {
-	Message Message `json:"message"`
+	config    *config.Config
+	logger    *logger.Logger
+	llm       *llm.Client
+	model     string
+	budget    *llm.Budget
+	settings  prompts.Settings
+	retries   int
+	templates map[models.OptimizationType]*models.PromptTemplate
+	calls     []*llm.Response
 }
 
 // SEO Enhancement structures
@@ -51,37 +39,79 @@ type SEOEnhancement struct {
 
 func New(cfg *config.Config, logger *logger.Logger) *Optimizer {
 	return &Optimizer{
-		config: cfg,
-		logger: logger,
+		config:  cfg,
+		logger:  logger,
+		llm:     llm.NewFromConfig(cfg, logger),
+		retries: llm.DefaultStructuredAttempts - 1,
 	}
 }
 
-func (o *Optimizer) OptimizeTitle(product interface{}) (string, error) {
-	o.logger.Debug("Optimizing title for product: %+v", product)
+// WithModel returns an optimizer that sends its prompts to model, typically the
+// organization's AISettings.DefaultModel. An empty model uses the configured default.
+func (o *Optimizer) WithModel(model string) *Optimizer {
+	clone := *o
+	clone.model = model
+	clone.calls = nil
+	return &clone
+}
 
-	// Convert product to JSON for AI processing
-	productJSON, err := json.Marshal(product)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal product: %v", err)
+// WithBudget returns an optimizer whose calls are charged to budget. Calls that don't fit
+// fail with llm.ErrBudgetExceeded instead of falling back.
+func (o *Optimizer) WithBudget(budget *llm.Budget) *Optimizer {
+	clone := *o
+	clone.budget = budget
+	clone.calls = nil
+	return &clone
+}
+
+// WithSettings returns an optimizer whose prompts see the organization's settings, such as
+// its default language and custom instructions. MaxRetries bounds how often an invalid
+// structured response is sent back for repair.
+func (o *Optimizer) WithSettings(settings *models.AISettings) *Optimizer {
+	clone := *o
+	clone.settings = prompts.SettingsFrom(settings)
+	if settings != nil {
+		clone.retries = settings.MaxRetries
 	}
+	clone.calls = nil
+	return &clone
+}
 
-	// Create AI prompt for title optimization
-	prompt := fmt.Sprintf(`
-You are an expert e-commerce SEO specialist. Optimize this product title for maximum search visibility and click-through rates.
+// WithTemplate returns an optimizer that renders prompts of the template's optimization
+// type from it instead of the built-in template
+func (o *Optimizer) WithTemplate(tmpl *models.PromptTemplate) *Optimizer {
+	clone := *o
+	clone.templates = make(map[models.OptimizationType]*models.PromptTemplate, len(o.templates)+1)
+	for t, existing := range o.templates {
+		clone.templates[t] = existing
+	}
+	clone.templates[tmpl.OptimizationType] = tmpl
+	clone.calls = nil
+	return &clone
+}
 
-Product data: %s
+// Template returns the prompt template used for an optimization type
+func (o *Optimizer) Template(t models.OptimizationType) *models.PromptTemplate {
+	return prompts.Resolve(o.templates[t], t)
+}
 
-Requirements:
-- Keep under 60 characters
-- Include primary keywords
-- Make it compelling and click-worthy
-- Ensure it's SEO-optimized
-- Maintain brand appeal
+// Calls returns the completions made by this optimizer, with the usage the provider reported
+func (o *Optimizer) Calls() []*llm.Response {
+	return o.calls
+}
+
+// Prices returns the price table used to cost completions
+func (o *Optimizer) Prices() llm.PriceTable {
+	return o.llm.Prices()
+}
 
-Return ONLY the optimized title, no explanations.
-`, string(productJSON))
+func (o *Optimizer) OptimizeTitle(product interface{}) (string, error) {
+	o.logger.Debug("Optimizing title for product: %+v", product)
 
-	optimizedTitle, err := o.callOpenAI(prompt)
+	optimizedTitle, err := o.complete(models.OptimizationTypeTitle, product)
+	if errors.Is(err, llm.ErrBudgetExceeded) {
+		return "", err
+	}
 	if err != nil {
 		o.logger.Error("AI title optimization failed, using fallback: %v", err)
 		// Fallback to simple optimization
@@ -100,30 +130,10 @@ Return ONLY the optimized title, no explanations.
 func (o *Optimizer) OptimizeDescription(product interface{}) (string, error) {
 	o.logger.Debug("Optimizing description for product: %+v", product)
 
-	// Convert product to JSON for AI processing
-	productJSON, err := json.Marshal(product)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal product: %v", err)
+	optimizedDescription, err := o.complete(models.OptimizationTypeDescription, product)
+	if errors.Is(err, llm.ErrBudgetExceeded) {
+		return "", err
 	}
-
-	// Create AI prompt for description optimization
-	prompt := fmt.Sprintf(`
-You are an expert e-commerce copywriter. Create an SEO-optimized product description that converts browsers into buyers.
-
-Product data: %s
-
-Requirements:
-- Keep under 160 characters for meta description
-- Include primary keywords naturally
-- Highlight key benefits and features
-- Create urgency and desire
-- Use persuasive language
-- Include a call-to-action
-
-Return ONLY the optimized description, no explanations.
-`, string(productJSON))
-
-	optimizedDescription, err := o.callOpenAI(prompt)
 	if err != nil {
 		o.logger.Error("AI description optimization failed, using fallback: %v", err)
 		// Fallback to simple optimization
@@ -139,44 +149,36 @@ Return ONLY the optimized description, no explanations.
 	return strings.TrimSpace(optimizedDescription), nil
 }
 
+// SuggestCategory returns the most confident Google product category the model suggests.
+// Responses that don't match the category schema fail with an *llm.StructuredError.
+// SuggestCategory suggests a Google product category path. When the Google taxonomy is
+// loaded the suggestion is one of its nodes; see SuggestTaxonomyCategory.
 func (o *Optimizer) SuggestCategory(product interface{}) (string, error) {
-	// TODO: Implement AI category suggestion
-	// This would use ML to:
-	// - Predict the best Google product category
-	// - Suggest required attributes
-	// - Validate against channel requirements
+	if t, err := taxonomy.Load(taxonomy.Google, o.config.TaxonomyDir); err == nil && t.Len() > 0 {
+		node, err := o.SuggestTaxonomyCategory(product, t)
+		if err != nil {
+			return "", err
+		}
+		return node.Path, nil
+	}
 
 	o.logger.Debug("Suggesting category for product: %+v", product)
 
-	// For now, return a default category
-	return "Electronics > Audio & Video", nil
+	var suggestions prompts.CategorySuggestions
+	if err := o.completeStructured(models.OptimizationTypeCategory, o.promptData(product), &suggestions); err != nil {
+		o.logger.Error("AI category suggestion failed: %v", err)
+		return "", err
+	}
+	return suggestions.Best().Category, nil
 }
 
 func (o *Optimizer) SuggestGTIN(product interface{}) (string, error) {
 	o.logger.Debug("Suggesting GTIN for product: %+v", product)
 
-	// Convert product to JSON for AI processing
-	productJSON, err := json.Marshal(product)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal product: %v", err)
+	gtin, err := o.complete(models.OptimizationTypeGTIN, product)
+	if errors.Is(err, llm.ErrBudgetExceeded) {
+		return "", err
 	}
-
-	// Create AI prompt for GTIN suggestion
-	prompt := fmt.Sprintf(`
-You are an expert in product identification. Analyze this product and suggest a GTIN (Global Trade Item Number) if possible.
-
-Product data: %s
-
-Requirements:
-- Return only the GTIN if you can determine it
-- If no GTIN can be determined, return empty string
-- GTIN should be 8, 12, 13, or 14 digits
-- Consider product type, brand, and attributes
-
-Return ONLY the GTIN or empty string, no explanations.
-`, string(productJSON))
-
-	gtin, err := o.callOpenAI(prompt)
 	if err != nil {
 		o.logger.Error("AI GTIN suggestion failed: %v", err)
 		return "", nil
@@ -185,176 +187,122 @@ Return ONLY the GTIN or empty string, no explanations.
 	return strings.TrimSpace(gtin), nil
 }
 
-// EnhanceProductSEO - Comprehensive SEO enhancement using AI
+// EnhanceProductSEO - Comprehensive SEO enhancement using AI. Responses that don't match
+// the SEO schema fail with an *llm.StructuredError carrying the raw response.
 func (o *Optimizer) EnhanceProductSEO(product interface{}) (*SEOEnhancement, error) {
 	o.logger.Debug("Enhancing SEO for product: %+v", product)
 
-	// Convert product to JSON for AI processing
-	productJSON, err := json.Marshal(product)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal product: %v", err)
-	}
-
-	// Create comprehensive AI prompt for SEO enhancement
-	prompt := fmt.Sprintf(`
-You are an expert e-commerce SEO specialist. Analyze this product and provide comprehensive SEO optimization.
-
-Product data: %s
-
-Provide a JSON response with the following structure:
-{
-  "seo_title": "Optimized title under 60 characters",
-  "seo_description": "Meta description under 160 characters",
-  "keywords": ["keyword1", "keyword2", "keyword3"],
-  "meta_keywords": "keyword1, keyword2, keyword3",
-  "alt_text": "Descriptive alt text for product images",
-  "schema_markup": "JSON-LD structured data for the product"
-}
-
-Requirements:
-- SEO title: Under 60 characters, keyword-rich, compelling
-- SEO description: Under 160 characters, persuasive, includes CTA
-- Keywords: 5-10 relevant keywords from title, category, brand
-- Alt text: Descriptive, includes product name and key features
-- Schema markup: Valid JSON-LD for Product type with name, description, brand, category
-
-Return ONLY the JSON response, no explanations.
-`, string(productJSON))
-
-	response, err := o.callOpenAI(prompt)
-	if err != nil {
-		o.logger.Error("AI SEO enhancement failed, using fallback: %v", err)
-		return o.createFallbackSEO(product), nil
-	}
-
-	// Parse AI response
 	var enhancement SEOEnhancement
-	if err := json.Unmarshal([]byte(response), &enhancement); err != nil {
-		o.logger.Error("Failed to parse AI SEO response, using fallback: %v", err)
-		return o.createFallbackSEO(product), nil
+	if err := o.completeStructured(models.OptimizationTypeSEO, o.promptData(product), &enhancement); err != nil {
+		o.logger.Error("AI SEO enhancement failed: %v", err)
+		return nil, err
 	}
-
 	return &enhancement, nil
 }
 
-// callOpenAI - Make API call to OpenAI
-func (o *Optimizer) callOpenAI(prompt string) (string, error) {
-	if o.config.OpenAIAPIKey == "" {
-		return "", fmt.Errorf("OpenAI API key not configured")
+// complete - Render the optimization type's prompt for product and send it to the
+// configured LLM provider
+func (o *Optimizer) complete(t models.OptimizationType, product interface{}) (string, error) {
+	req, err := o.request(t, o.promptData(product))
+	if err != nil {
+		return "", err
 	}
 
-	client := &http.Client{Timeout: 30 * time.Second}
-
-	request := OpenAIRequest{
-		Model:       "gpt-3.5-turbo",
-		Temperature: 0.7,
-		MaxTokens:   500,
-		Messages: []Message{
-			{
-				Role:    "system",
-				Content: "You are an expert e-commerce SEO specialist and copywriter.",
-			},
-			{
-				Role:    "user",
-				Content: prompt,
-			},
-		},
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
 
-	jsonData, err := json.Marshal(request)
+	resp, err := o.llm.Complete(ctx, req)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %v", err)
+		return "", err
 	}
+	o.calls = append(o.calls, resp)
+	return resp.Content, nil
+}
 
-	req, err := http.NewRequest("POST", "https://api.openai.com/v1/chat/completions", bytes.NewBuffer(jsonData))
+// completeStructured - Like complete, for optimization types with a JSON schema, rendered
+// against data. The validated response is decoded into out; every attempt is kept in Calls.
+func (o *Optimizer) completeStructured(t models.OptimizationType, data prompts.Data, out interface{}) error {
+	req, err := o.request(t, data)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %v", err)
+		return err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+o.config.OpenAIAPIKey)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to make request: %v", err)
-	}
-	defer resp.Body.Close()
+	responses, err := o.llm.CompleteStructured(ctx, req, prompts.Schema(t), out, 1+o.retries)
+	o.calls = append(o.calls, responses...)
+	return err
+}
 
-	body, err := io.ReadAll(resp.Body)
+// request renders the optimization type's prompt against data
+func (o *Optimizer) request(t models.OptimizationType, data prompts.Data) (llm.Request, error) {
+	rendered, err := prompts.Render(o.Template(t), data)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %v", err)
+		return llm.Request{}, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("OpenAI API error: %s", string(body))
-	}
-
-	var openAIResp OpenAIResponse
-	if err := json.Unmarshal(body, &openAIResp); err != nil {
-		return "", fmt.Errorf("failed to parse response: %v", err)
+	req := rendered.Request(o.model)
+	if req.System == "" {
+		req.System = "You are an expert e-commerce SEO specialist and copywriter."
 	}
+	req.Budget = o.budget
+	return req, nil
+}
 
-	if len(openAIResp.Choices) == 0 {
-		return "", fmt.Errorf("no response from OpenAI")
+// promptData maps the product fields and request options handlers pass to the optimizer
+// onto template variables
+func (o *Optimizer) promptData(product interface{}) prompts.Data {
+	data := prompts.Data{Settings: o.settings}
+	p, ok := product.(map[string]interface{})
+	if !ok {
+		return data
 	}
 
-	return openAIResp.Choices[0].Message.Content, nil
-}
-
-// createFallbackSEO - Create fallback SEO when AI fails
-func (o *Optimizer) createFallbackSEO(product interface{}) *SEOEnhancement {
-	// Extract basic product info
-	title := "Product"
-	category := "General"
-	vendor := "Brand"
-	description := "High-quality product"
-
-	if p, ok := product.(map[string]interface{}); ok {
-		if t, exists := p["title"].(string); exists {
-			title = t
-		}
-		if c, exists := p["product_type"].(string); exists {
-			category = c
-		}
-		if v, exists := p["vendor"].(string); exists {
-			vendor = v
-		}
-		if d, exists := p["description"].(string); exists {
-			description = d
+	str := func(key string) string {
+		if v, ok := p[key].(string); ok {
+			return v
 		}
+		return ""
 	}
-
-	// Create fallback SEO
-	seoTitle := title
-	if len(seoTitle) > 60 {
-		seoTitle = seoTitle[:57] + "..."
+	data.Product = prompts.Product{
+		Title:       str("title"),
+		Description: str("description"),
+		Brand:       str("brand"),
+		Category:    str("category"),
+		ProductType: str("product_type"),
+		Vendor:      str("vendor"),
+		SKU:         str("sku"),
+		GTIN:        str("gtin"),
+		Currency:    str("currency"),
+
+		SEOTitle:       str("seo_title"),
+		SEODescription: str("seo_description"),
+		SEOKeywords:    str("seo_keywords"),
 	}
-
-	seoDescription := description
-	if len(seoDescription) > 160 {
-		seoDescription = seoDescription[:157] + "..."
-	} else if seoDescription == "" {
-		seoDescription = fmt.Sprintf("Shop %s online. High-quality %s from %s. Fast shipping and great customer service.", title, category, vendor)
+	if options, ok := p["variant_options"].(map[string][]string); ok {
+		data.Product.VariantOptions = options
 	}
-
-	keywords := []string{
-		strings.ToLower(title),
-		strings.ToLower(category),
-		strings.ToLower(vendor),
-		"online shopping",
-		"buy online",
+	switch price := p["price"].(type) {
+	case float64:
+		data.Product.Price = price
+	case string:
+		fmt.Sscanf(price, "%f", &data.Product.Price)
 	}
 
-	if category != "" {
-		keywords = append(keywords, strings.ToLower(category)+" for sale")
+	data.Options = prompts.Options{
+		Keywords:           str("keywords"),
+		Strategy:           str("strategy"),
+		Style:              str("style"),
+		Length:             str("length"),
+		Focus:              str("focus"),
+		Audience:           str("target_audience"),
+		Level:              str("level"),
+		Language:           str("language"),
+		CustomInstructions: str("instructions"),
 	}
-
-	return &SEOEnhancement{
-		SEOTitle:       seoTitle,
-		SEODescription: seoDescription,
-		Keywords:       keywords,
-		MetaKeywords:   strings.Join(keywords, ", "),
-		AltText:        fmt.Sprintf("%s - %s product from %s", title, category, vendor),
-		SchemaMarkup:   fmt.Sprintf(`{"@context":"https://schema.org","@type":"Product","name":"%s","description":"%s","brand":{"@type":"Brand","name":"%s"},"category":"%s"}`, title, description, vendor, category),
+	if maxLength, ok := p["max_length"].(int); ok {
+		data.Options.MaxLength = maxLength
 	}
+	return data
 }
@@ -0,0 +1,101 @@
+package ai
+
+import (
+	"time"
+
+	"lister/internal/llm"
+	"lister/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Settings returns the organization's AI settings, or the defaults when none are saved
+func Settings(db *gorm.DB, organizationID uuid.UUID) *models.AISettings {
+	var settings models.AISettings
+	if err := db.Where("organization_id = ?", organizationID).First(&settings).Error; err != nil {
+		return models.DefaultAISettings(organizationID)
+	}
+	return &settings
+}
+
+// ForOrganization returns an optimizer using the organization's model, settings, prompt
+// templates and the given budget
+func (o *Optimizer) ForOrganization(db *gorm.DB, organizationID uuid.UUID, settings *models.AISettings, budget *llm.Budget) *Optimizer {
+	optimizer := o.WithModel(settings.DefaultModel).WithSettings(settings).WithBudget(budget)
+	templates, err := ActiveTemplates(db, organizationID)
+	if err != nil {
+		o.logger.Error("Failed to load prompt templates: %v", err)
+	}
+	for i := range templates {
+		optimizer = optimizer.WithTemplate(&templates[i])
+	}
+	return optimizer
+}
+
+// ActiveTemplates returns the active prompt template of each optimization type, preferring
+// the organization's own over the global one
+func ActiveTemplates(db *gorm.DB, organizationID uuid.UUID) ([]models.PromptTemplate, error) {
+	var stored []models.PromptTemplate
+	err := db.Where("is_active AND (organization_id = ? OR organization_id IS NULL)", organizationID).
+		Order("organization_id NULLS LAST, version DESC").
+		Find(&stored).Error
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[models.OptimizationType]bool{}
+	templates := make([]models.PromptTemplate, 0, len(stored))
+	for _, t := range stored {
+		if seen[t.OptimizationType] {
+			continue
+		}
+		seen[t.OptimizationType] = true
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
+
+// MonthlyBudget returns the organization's MaxCostPerMonth with this calendar month's spend
+func MonthlyBudget(db *gorm.DB, organizationID uuid.UUID, settings *models.AISettings) *llm.Budget {
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	var spent float64
+	db.Model(&models.AIUsage{}).
+		Where("organization_id = ? AND created_at >= ?", organizationID, monthStart).
+		Select("COALESCE(SUM(cost), 0)").
+		Scan(&spent)
+
+	return llm.NewBudget(settings.MaxCostPerMonth, spent)
+}
+
+// RecordUsage stores a usage row per completion the optimizer made and returns the model,
+// token usage and cost to put on the optimization history
+func (o *Optimizer) RecordUsage(db *gorm.DB, organizationID uuid.UUID, purpose, defaultModel string) (string, llm.Usage, float64) {
+	calls := o.Calls()
+	model := defaultModel
+	for _, call := range calls {
+		model = call.Model
+		record := &models.AIUsage{
+			OrganizationID:   organizationID,
+			Purpose:          purpose,
+			Provider:         call.Provider,
+			Model:            call.Model,
+			PromptTokens:     call.Usage.PromptTokens,
+			CompletionTokens: call.Usage.CompletionTokens,
+			TotalTokens:      call.Usage.TotalTokens,
+			Cost:             call.Cost,
+		}
+		if call.RequestedModel != "" {
+			requested := call.RequestedModel
+			record.RequestedModel = &requested
+		}
+		if err := db.Create(record).Error; err != nil {
+			o.logger.Error("Failed to record AI usage: %v", err)
+		}
+	}
+
+	usage, cost := llm.Total(calls)
+	return model, usage, cost
+}
@@ -0,0 +1,28 @@
+package ai
+
+import (
+	"lister/internal/models"
+	"lister/internal/prompts"
+	"lister/internal/translation"
+)
+
+// Translate translates product's title, description and SEO fields into locale. The
+// glossary's fixed translations are passed to the prompt, and its protected terms are
+// swapped for placeholders before rendering and restored in the response. Responses that
+// don't match the translation schema fail with an *llm.StructuredError.
+func (o *Optimizer) Translate(product interface{}, locale string, glossary translation.Glossary) (*prompts.Translation, error) {
+	o.logger.Debug("Translating product into %s: %+v", locale, product)
+
+	data := o.promptData(product)
+	data.Product = glossary.ProtectProduct(data.Product)
+	data.Options.Language = locale
+	data.Options.Glossary = glossary.Terms
+
+	var result prompts.Translation
+	if err := o.completeStructured(models.OptimizationTypeTranslation, data, &result); err != nil {
+		o.logger.Error("AI translation into %s failed: %v", locale, err)
+		return nil, err
+	}
+	result = glossary.RestoreTranslation(result)
+	return &result, nil
+}
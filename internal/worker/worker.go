@@ -6,8 +6,11 @@ import (
 	"time"
 
 	"lister/internal/config"
+	"lister/internal/database"
+	"lister/internal/jobs"
 	"lister/internal/logger"
 	"lister/internal/worker/processors"
+	"lister/internal/worker/processors/ai"
 
 	"github.com/segmentio/kafka-go"
 )
@@ -17,9 +20,11 @@ type Worker struct {
 	logger    *logger.Logger
 	reader    *kafka.Reader
 	processor *processors.EventProcessor
+	jobs      *jobs.BulkOptimizer
+	stopJobs  context.CancelFunc
 }
 
-func New(cfg *config.Config, logger *logger.Logger) *Worker {
+func New(cfg *config.Config, logger *logger.Logger, db *database.Database) *Worker {
 	reader := kafka.NewReader(kafka.ReaderConfig{
 		Brokers:        []string{cfg.KafkaBrokers},
 		GroupID:        "lister-worker",
@@ -36,10 +41,16 @@ func New(cfg *config.Config, logger *logger.Logger) *Worker {
 		logger:    logger,
 		reader:    reader,
 		processor: processor,
+		jobs:      jobs.NewBulkOptimizer(db.DB, logger, ai.New(cfg, logger)),
 	}
 }
 
 func (w *Worker) Start() {
+	// Bulk optimization jobs queued by the API
+	ctx, stop := context.WithCancel(context.Background())
+	w.stopJobs = stop
+	go w.jobs.Start(ctx, time.Duration(w.config.JobPollSeconds)*time.Second)
+
 	w.logger.Info("Worker started, listening for events...")
 
 	for {
@@ -73,6 +84,9 @@ func (w *Worker) Start() {
 
 func (w *Worker) Stop() {
 	w.logger.Info("Stopping worker...")
+	if w.stopJobs != nil {
+		w.stopJobs()
+	}
 	w.reader.Close()
 }
 
@@ -0,0 +1,99 @@
+-- ============================================================================
+-- A/B testing for Product Lister
+-- Tests between two feed variants of a product, rotated into feeds on a
+-- time-split schedule, and the channel performance imported for each variant.
+-- Run this in Supabase SQL Editor
+-- ============================================================================
+
+-- ============================================================================
+-- Table: feed_variants
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS feed_variants (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID REFERENCES products(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    config JSONB,
+    transformation JSONB,
+    status VARCHAR(50) DEFAULT 'DRAFT',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_feed_variants_product ON feed_variants(product_id);
+
+COMMENT ON COLUMN feed_variants.transformation IS 'Fields the variant changes in feeds: {"title", "description"}';
+
+-- ============================================================================
+-- Table: ab_tests
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS ab_tests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID DEFAULT '00000000-0000-0000-0000-000000000000'::uuid,
+    name VARCHAR(255) NOT NULL,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_a_id UUID NOT NULL REFERENCES feed_variants(id),
+    variant_b_id UUID NOT NULL REFERENCES feed_variants(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'PAUSED', 'COMPLETED', 'CANCELLED')),
+
+    -- Evaluation
+    metric VARCHAR(20) NOT NULL DEFAULT 'ctr' CHECK (metric IN ('ctr', 'conversion_rate')),
+    rotation_hours INTEGER NOT NULL DEFAULT 24 CHECK (rotation_hours > 0),
+    min_sample_size INTEGER NOT NULL DEFAULT 100,
+    confidence_level DECIMAL(5,4) NOT NULL DEFAULT 0.95,
+    auto_promote BOOLEAN NOT NULL DEFAULT true,
+    pauses JSONB NOT NULL DEFAULT '[]',
+
+    -- Totals of both variants, and the result
+    impressions INTEGER DEFAULT 0,
+    clicks INTEGER DEFAULT 0,
+    conversions INTEGER DEFAULT 0,
+    roas DECIMAL(10,4),
+    winner UUID,
+    confidence DOUBLE PRECISION,
+    decided_at TIMESTAMP WITH TIME ZONE,
+    result JSONB,
+
+    -- Timestamps
+    started_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    ended_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- One running test per product
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ab_tests_running_product ON ab_tests(product_id) WHERE status IN ('ACTIVE', 'PAUSED');
+CREATE INDEX IF NOT EXISTS idx_ab_tests_org_status ON ab_tests(organization_id, status);
+
+COMMENT ON TABLE ab_tests IS 'A/B tests between two feed variants of a product';
+COMMENT ON COLUMN ab_tests.rotation_hours IS 'Variants take turns in feeds for this many hours, counted from the start day''s midnight UTC';
+COMMENT ON COLUMN ab_tests.winner IS 'The promoted feed variant';
+COMMENT ON COLUMN ab_tests.min_sample_size IS 'Planned sample per variant; the test is decided once, when both variants reach it';
+COMMENT ON COLUMN ab_tests.confidence IS 'Confidence (1 - p) of the two-proportion z-test on the metric, frozen once the test is decided';
+COMMENT ON COLUMN ab_tests.pauses IS 'Periods the test was paused, [{"from", "to"}]; the rotation skips them and their days take no metrics';
+COMMENT ON COLUMN ab_tests.result IS 'The comparison when both variants reached the planned sample';
+
+-- ============================================================================
+-- Table: ab_test_metrics
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS ab_test_metrics (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    test_id UUID NOT NULL REFERENCES ab_tests(id) ON DELETE CASCADE,
+    arm CHAR(1) NOT NULL CHECK (arm IN ('A', 'B')),
+    metric_date DATE NOT NULL,
+
+    -- Performance
+    impressions INTEGER NOT NULL DEFAULT 0,
+    clicks INTEGER NOT NULL DEFAULT 0,
+    conversions INTEGER NOT NULL DEFAULT 0,
+    revenue DECIMAL(12,2) NOT NULL DEFAULT 0,
+    spend DECIMAL(12,2) NOT NULL DEFAULT 0,
+    source VARCHAR(50) DEFAULT 'api',
+    imported_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT uq_ab_test_metric UNIQUE (test_id, arm, metric_date)
+);
+
+COMMENT ON TABLE ab_test_metrics IS 'Daily channel performance of each variant; re-importing a day replaces it';
+
+-- Migration complete
+SELECT 'A/B testing tables created successfully! ✅' as status;
@@ -0,0 +1,40 @@
+-- ============================================================================
+-- AI response cache for Product Lister
+-- Optimization results are stored under a hash of the model, the prompt
+-- template version and the rendered prompt. Identical requests within the
+-- organization's cache TTL are answered from the cache and recorded as
+-- zero-cost optimizations.
+-- Run this in Supabase SQL Editor
+-- ============================================================================
+
+-- ============================================================================
+-- Table: ai_response_cache
+-- Purpose: Reusable optimization results keyed by prompt content
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS ai_response_cache (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000'::uuid,
+    cache_key VARCHAR(64) NOT NULL,
+    optimization_type VARCHAR(50) NOT NULL,
+    model VARCHAR(100),
+    prompt_template_id UUID REFERENCES prompt_templates(id) ON DELETE CASCADE,
+    prompt_version INTEGER NOT NULL DEFAULT 0,
+    response TEXT NOT NULL,
+    hits INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_hit_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (organization_id, cache_key)
+);
+
+-- Expired entries are skipped on lookup and can be purged in bulk
+CREATE INDEX IF NOT EXISTS idx_ai_response_cache_expires_at ON ai_response_cache(expires_at);
+
+-- Hours a cached result is reused; 0 disables the cache
+ALTER TABLE ai_settings ADD COLUMN IF NOT EXISTS cache_ttl_hours INTEGER DEFAULT 168 CHECK (cache_ttl_hours >= 0);
+
+COMMENT ON TABLE ai_response_cache IS 'Content-addressed AI optimization results reused for identical requests';
+COMMENT ON COLUMN ai_response_cache.cache_key IS 'SHA-256 of the model, prompt template version and rendered prompt';
+
+-- Migration complete
+SELECT 'AI response cache table created successfully! ✅' as status;
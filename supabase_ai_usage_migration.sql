@@ -0,0 +1,39 @@
+-- ============================================================================
+-- AI usage accounting for Product Lister
+-- One row per LLM call with the tokens reported by the provider and its cost.
+-- The monthly sum is checked against ai_settings.max_cost_per_month.
+-- Run this in Supabase SQL Editor
+-- ============================================================================
+
+-- ============================================================================
+-- Table: ai_usage
+-- Purpose: Per-call token usage and cost
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS ai_usage (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000'::uuid,
+    purpose VARCHAR(50) NOT NULL,
+    provider VARCHAR(20) NOT NULL,
+    model VARCHAR(100) NOT NULL,
+    requested_model VARCHAR(100), -- set when the budget forced a cheaper model
+    prompt_tokens INTEGER NOT NULL DEFAULT 0,
+    completion_tokens INTEGER NOT NULL DEFAULT 0,
+    total_tokens INTEGER NOT NULL DEFAULT 0,
+    cost DECIMAL(12,6) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Month-to-date spend per organization
+CREATE INDEX IF NOT EXISTS idx_ai_usage_organization_created_at ON ai_usage(organization_id, created_at DESC);
+
+-- Monthly AI budget in USD, checked before every call
+ALTER TABLE ai_settings ADD COLUMN IF NOT EXISTS max_cost_per_month DECIMAL(10,2) DEFAULT 25.00;
+
+-- Provider model names such as "anthropic/claude-3.5-sonnet" outgrow 50 characters
+ALTER TABLE optimization_history ALTER COLUMN ai_model TYPE VARCHAR(100);
+ALTER TABLE ai_settings ALTER COLUMN default_model TYPE VARCHAR(100);
+
+COMMENT ON TABLE ai_usage IS 'Tokens and cost of every AI call, used to enforce the monthly AI budget';
+
+-- Migration complete
+SELECT 'AI usage table created successfully! ✅' as status;
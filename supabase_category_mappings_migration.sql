@@ -0,0 +1,31 @@
+-- ============================================================================
+-- Category mappings for Product Lister
+-- Maps an organization's store product types and collections to categories of
+-- the Google and Meta product taxonomies. Feeds use the product type's mapping,
+-- then the first mapped collection's, for google_product_category and
+-- fb_product_category.
+-- Run this in Supabase SQL Editor
+-- ============================================================================
+
+-- ============================================================================
+-- Category mappings
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS category_mappings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL,
+    source_type VARCHAR(20) NOT NULL CHECK (source_type IN ('product_type', 'collection')),
+    source_value VARCHAR(255) NOT NULL,
+    taxonomy VARCHAR(20) NOT NULL DEFAULT 'google' CHECK (taxonomy IN ('google', 'meta')),
+    category_id INTEGER NOT NULL,
+    category_path TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT idx_category_mappings_source UNIQUE (organization_id, source_type, source_value, taxonomy)
+);
+
+COMMENT ON TABLE category_mappings IS 'Store product types and collections mapped to Google or Meta taxonomy categories';
+COMMENT ON COLUMN category_mappings.source_value IS 'Product type or collection name, matched case-insensitively';
+COMMENT ON COLUMN category_mappings.category_path IS 'Taxonomy path of category_id when the mapping was saved';
+
+-- Migration complete
+SELECT 'Category mappings table created successfully! ✅' as status;
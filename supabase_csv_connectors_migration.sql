@@ -0,0 +1,18 @@
+-- ============================================================================
+-- Scheduled file imports for Product Lister
+-- CSV/TSV/XLSX connectors keep their source, format and mapping in connectors.config
+-- Run this in Supabase SQL Editor
+-- ============================================================================
+
+-- ============================================================================
+-- Table: connectors
+-- Purpose: Connector-specific configuration and secrets
+-- ============================================================================
+ALTER TABLE connectors ADD COLUMN IF NOT EXISTS config JSONB DEFAULT '{}'::jsonb;
+ALTER TABLE connectors ADD COLUMN IF NOT EXISTS credentials JSONB DEFAULT '{}'::jsonb;
+
+-- Scheduled syncs look up active connectors by type
+CREATE INDEX IF NOT EXISTS idx_connectors_type_status ON connectors(type, status);
+
+-- Archiving products missing from a file filters on the owning connector and SKU
+CREATE INDEX IF NOT EXISTS idx_products_connector_sku ON products(connector_id, sku);
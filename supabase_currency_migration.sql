@@ -0,0 +1,62 @@
+-- ============================================================================
+-- Multi-currency and per-country feeds for Product Lister
+-- Exchange rates fetched from a rate source (the ECB by default) or set by
+-- hand, and the per-country artifacts a feed with several targets produces.
+-- Run this in Supabase SQL Editor
+-- ============================================================================
+
+-- ============================================================================
+-- Table: exchange_rates
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID DEFAULT '00000000-0000-0000-0000-000000000000'::uuid,
+
+    -- Rate: 1 base_currency = rate quote_currency
+    base_currency VARCHAR(3) NOT NULL,
+    quote_currency VARCHAR(3) NOT NULL,
+    rate NUMERIC(18, 8) NOT NULL CHECK (rate > 0),
+    source VARCHAR(50) NOT NULL DEFAULT 'manual',
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT uq_exchange_rate UNIQUE (organization_id, base_currency, quote_currency, source)
+);
+
+CREATE INDEX IF NOT EXISTS idx_exchange_rates_org_source ON exchange_rates(organization_id, source, updated_at DESC);
+
+COMMENT ON TABLE exchange_rates IS 'Exchange rates used to convert feed prices into a target currency';
+COMMENT ON COLUMN exchange_rates.source IS 'Rate source such as ecb; manual rates override a source''s rate for the same pair';
+
+-- ============================================================================
+-- Table: feed_artifacts
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS feed_artifacts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    feed_id UUID NOT NULL,
+    organization_id UUID DEFAULT '00000000-0000-0000-0000-000000000000'::uuid,
+
+    -- Target
+    country VARCHAR(2) NOT NULL,
+    currency VARCHAR(3),
+    language VARCHAR(10),
+
+    -- Last generation
+    products_included INTEGER DEFAULT 0,
+    products_excluded INTEGER DEFAULT 0,
+    file_size_bytes INTEGER DEFAULT 0,
+    file_format VARCHAR(10),
+    generated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT uq_feed_artifact_country UNIQUE (feed_id, country),
+    CONSTRAINT fk_feed_artifact_feed FOREIGN KEY (feed_id)
+        REFERENCES product_feeds(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE feed_artifacts IS 'One generated file per target country of a feed';
+COMMENT ON COLUMN feed_artifacts.currency IS 'Currency the artifact''s prices were converted to';
+
+-- Migration complete
+SELECT 'Currency tables created successfully! ✅' as status;
@@ -0,0 +1,82 @@
+-- ============================================================================
+-- Custom labels for Product Lister
+-- Rules assigning Google's custom_label_0 to custom_label_4 from price band,
+-- margin, stock, product age or category, the labels each feed's last
+-- generation assigned, and the history of label changes.
+-- Run this in Supabase SQL Editor
+-- ============================================================================
+
+-- ============================================================================
+-- Table: custom_label_slots
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS custom_label_slots (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID DEFAULT '00000000-0000-0000-0000-000000000000'::uuid,
+
+    -- Slot: custom_label_0 to custom_label_4
+    slot SMALLINT NOT NULL CHECK (slot BETWEEN 0 AND 4),
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    basis VARCHAR(20) NOT NULL CHECK (basis IN ('price', 'margin', 'stock', 'age', 'category')),
+    rules JSONB NOT NULL DEFAULT '[]',
+    default_label VARCHAR(100),
+    enabled BOOLEAN DEFAULT true,
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT uq_custom_label_slot UNIQUE (organization_id, slot)
+);
+
+COMMENT ON TABLE custom_label_slots IS 'Rules assigning a custom label slot on every feed generation';
+COMMENT ON COLUMN custom_label_slots.rules IS 'Ordered rules: {"min", "max", "label"} bands for numeric bases, {"match", "label"} category prefixes';
+
+-- ============================================================================
+-- Table: product_custom_labels
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS product_custom_labels (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    feed_id UUID NOT NULL,
+    organization_id UUID DEFAULT '00000000-0000-0000-0000-000000000000'::uuid,
+
+    -- Item: the feed item id, which is the variant's for variant-level feeds
+    item_id VARCHAR(255) NOT NULL,
+    product_id UUID,
+    slot SMALLINT NOT NULL CHECK (slot BETWEEN 0 AND 4),
+    label VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT uq_product_custom_label UNIQUE (feed_id, item_id, slot),
+    CONSTRAINT fk_product_custom_label_feed FOREIGN KEY (feed_id)
+        REFERENCES product_feeds(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_custom_labels_org ON product_custom_labels(organization_id, feed_id, slot);
+
+COMMENT ON TABLE product_custom_labels IS 'Custom labels assigned to each feed item by the feed''s last generation';
+
+-- ============================================================================
+-- Table: custom_label_history
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS custom_label_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID DEFAULT '00000000-0000-0000-0000-000000000000'::uuid,
+    feed_id UUID,
+    item_id VARCHAR(255) NOT NULL,
+    product_id UUID,
+
+    -- Change
+    slot SMALLINT NOT NULL,
+    previous_label VARCHAR(100),
+    label VARCHAR(100),
+    assigned_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_custom_label_history_org ON custom_label_history(organization_id, assigned_at DESC);
+CREATE INDEX IF NOT EXISTS idx_custom_label_history_product ON custom_label_history(product_id, assigned_at DESC);
+
+COMMENT ON TABLE custom_label_history IS 'Custom label changes between feed generations';
+COMMENT ON COLUMN custom_label_history.label IS 'New label; NULL when the slot was cleared';
+
+-- Migration complete
+SELECT 'Custom label tables created successfully! ✅' as status;
@@ -0,0 +1,43 @@
+-- ============================================================================
+-- Feed rules for Product Lister
+-- Ordered, per-feed rules run against products before a feed is generated.
+-- A rule's conditions test product fields; when they match, its actions set,
+-- append, prepend, rewrite or case fields, truncate them at a word, or exclude
+-- the product from the feed.
+-- Run this in Supabase SQL Editor
+-- ============================================================================
+
+-- ============================================================================
+-- Table: feed_rules
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS feed_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    feed_id UUID NOT NULL,
+    organization_id UUID DEFAULT '00000000-0000-0000-0000-000000000000'::uuid,
+
+    -- Rule
+    name VARCHAR(255) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    enabled BOOLEAN DEFAULT TRUE,
+    match VARCHAR(10) NOT NULL DEFAULT 'all' CHECK (match IN ('all', 'any')),
+    conditions JSONB NOT NULL DEFAULT '[]',
+    actions JSONB NOT NULL DEFAULT '[]',
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT fk_feed_rule_feed FOREIGN KEY (feed_id)
+        REFERENCES product_feeds(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_feed_rules_feed_position ON feed_rules(feed_id, position);
+
+COMMENT ON TABLE feed_rules IS 'Ordered conditional transformations applied to a feed''s products';
+COMMENT ON COLUMN feed_rules.position IS 'Rules run in ascending position; later rules see earlier rules'' changes';
+COMMENT ON COLUMN feed_rules.match IS 'all: every condition must hold; any: one condition is enough';
+COMMENT ON COLUMN feed_rules.conditions IS 'Array of {field, operator, value | value_field, case_sensitive}';
+COMMENT ON COLUMN feed_rules.actions IS 'Array of {type, field, value, pattern, length}';
+
+-- Migration complete
+SELECT 'Feed rules table created successfully! ✅' as status;
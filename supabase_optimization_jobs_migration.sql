@@ -0,0 +1,72 @@
+-- ============================================================================
+-- Bulk optimization jobs for Product Lister
+-- Bulk optimizations are persisted as a job with one item per product and are
+-- processed in the background. A runner holds a lease on the job while it works;
+-- a job whose lease expired (crashed runner, serverless timeout) is resumed by
+-- the next runner.
+-- Run this in Supabase SQL Editor
+-- ============================================================================
+
+-- ============================================================================
+-- Table: optimization_jobs
+-- Purpose: A bulk optimization run and its progress
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS optimization_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000'::uuid,
+    optimization_type VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'running', 'paused', 'cancelled', 'done')),
+    auto_apply BOOLEAN NOT NULL DEFAULT FALSE,
+    concurrency INTEGER NOT NULL DEFAULT 4 CHECK (concurrency >= 1 AND concurrency <= 10),
+    options JSONB DEFAULT '{}',
+
+    -- Progress
+    total_items INTEGER NOT NULL DEFAULT 0,
+    processed_items INTEGER NOT NULL DEFAULT 0,
+    succeeded_items INTEGER NOT NULL DEFAULT 0,
+    failed_items INTEGER NOT NULL DEFAULT 0,
+    skipped_items INTEGER NOT NULL DEFAULT 0,
+    cost DECIMAL(12,6) NOT NULL DEFAULT 0,
+    error_message TEXT,
+
+    -- Lease held by the runner processing the job
+    locked_by VARCHAR(255),
+    locked_until TIMESTAMP WITH TIME ZONE,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_optimization_jobs_organization ON optimization_jobs(organization_id, created_at DESC);
+
+-- Runners claim queued jobs and running jobs with an expired lease
+CREATE INDEX IF NOT EXISTS idx_optimization_jobs_runnable ON optimization_jobs(created_at)
+    WHERE status IN ('queued', 'running');
+
+-- ============================================================================
+-- Table: optimization_job_items
+-- Purpose: One product of a bulk optimization job and its result
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS optimization_job_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_id UUID NOT NULL REFERENCES optimization_jobs(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'succeeded', 'failed', 'skipped', 'cancelled')),
+    optimization_history_id UUID REFERENCES optimization_history(id) ON DELETE SET NULL,
+    optimized_value TEXT,
+    error_message TEXT,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (job_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_optimization_job_items_job_status ON optimization_job_items(job_id, status);
+
+COMMENT ON TABLE optimization_jobs IS 'Background bulk optimization runs with progress, pause/cancel and crash recovery';
+COMMENT ON TABLE optimization_job_items IS 'Per-product results of bulk optimization jobs, linked to optimization_history';
+
+-- Migration complete
+SELECT 'Optimization jobs tables created successfully! ✅' as status;
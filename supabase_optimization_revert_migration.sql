@@ -0,0 +1,33 @@
+-- ============================================================================
+-- Optimization rollback for Product Lister
+-- Applied optimizations can be reverted to their original value, one at a
+-- time or for a whole bulk job or time range. Reverts are recorded on the
+-- optimization history entry.
+-- Run this in Supabase SQL Editor
+-- ============================================================================
+
+-- ============================================================================
+-- Reverted status
+-- ============================================================================
+ALTER TABLE optimization_history DROP CONSTRAINT IF EXISTS optimization_history_status_check;
+ALTER TABLE optimization_history ADD CONSTRAINT optimization_history_status_check
+    CHECK (status IN ('pending', 'applied', 'rejected', 'failed', 'reverted'));
+
+-- ============================================================================
+-- Revert details on optimization_history
+-- ============================================================================
+ALTER TABLE optimization_history ADD COLUMN IF NOT EXISTS reverted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE optimization_history ADD COLUMN IF NOT EXISTS reverted_by VARCHAR(255);
+ALTER TABLE optimization_history ADD COLUMN IF NOT EXISTS revert_reason TEXT;
+
+-- Bulk reverts select an organization's applied optimizations by applied_at
+CREATE INDEX IF NOT EXISTS idx_optimization_history_applied_at
+    ON optimization_history(organization_id, applied_at DESC)
+    WHERE status = 'applied';
+
+COMMENT ON COLUMN optimization_history.reverted_at IS 'When the applied value was replaced by original_value again';
+COMMENT ON COLUMN optimization_history.reverted_by IS 'User who reverted the optimization';
+COMMENT ON COLUMN optimization_history.revert_reason IS 'Reason given for the revert';
+
+-- Migration complete
+SELECT 'Optimization revert columns added successfully! ✅' as status;
@@ -0,0 +1,34 @@
+-- ============================================================================
+-- Optimization review queue for Product Lister
+-- Pending optimizations wait for a reviewer to approve (optionally after
+-- editing the suggestion) or reject them. Optimizations scoring at least
+-- min_score_threshold are applied automatically when auto_apply is on and
+-- require_approval is off.
+-- Run this in Supabase SQL Editor
+-- ============================================================================
+
+-- ============================================================================
+-- Review decisions on optimization_history
+-- ============================================================================
+ALTER TABLE optimization_history ADD COLUMN IF NOT EXISTS suggested_value TEXT;
+ALTER TABLE optimization_history ADD COLUMN IF NOT EXISTS reviewed_by VARCHAR(255);
+ALTER TABLE optimization_history ADD COLUMN IF NOT EXISTS review_reason TEXT;
+ALTER TABLE optimization_history ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP WITH TIME ZONE;
+
+-- The review queue lists an organization's pending optimizations, newest first
+CREATE INDEX IF NOT EXISTS idx_optimization_history_pending_review
+    ON optimization_history(organization_id, created_at DESC)
+    WHERE status = 'pending';
+
+-- ============================================================================
+-- Auto apply setting
+-- ============================================================================
+ALTER TABLE ai_settings ADD COLUMN IF NOT EXISTS auto_apply BOOLEAN DEFAULT FALSE;
+
+COMMENT ON COLUMN optimization_history.suggested_value IS 'Original AI suggestion when a reviewer edited it before approving';
+COMMENT ON COLUMN optimization_history.reviewed_by IS 'User who approved or rejected the optimization, or auto';
+COMMENT ON COLUMN optimization_history.review_reason IS 'Reason given with the review decision';
+COMMENT ON COLUMN ai_settings.auto_apply IS 'Apply optimizations scoring at least min_score_threshold without review';
+
+-- Migration complete
+SELECT 'Optimization review columns added successfully! ✅' as status;
@@ -0,0 +1,25 @@
+-- ============================================================================
+-- Sale prices and costs for Product Lister
+-- Adds scheduled sale prices with an effective window, and product costs used
+-- by feed price rules' minimum margin floor. Feeds list a sale from
+-- compare_at_price > price, or from sale_price until sale_ends_at.
+-- Run this in Supabase SQL Editor
+-- ============================================================================
+
+-- ============================================================================
+-- Product pricing columns
+-- ============================================================================
+ALTER TABLE products ADD COLUMN IF NOT EXISTS compare_at_price DECIMAL(10,2);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS sale_price DECIMAL(10,2);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS sale_starts_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS sale_ends_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS cost DECIMAL(10,2);
+
+COMMENT ON COLUMN products.compare_at_price IS 'Store''s original price; above price, the product is on sale';
+COMMENT ON COLUMN products.sale_price IS 'Scheduled sale price below price, listed until sale_ends_at';
+COMMENT ON COLUMN products.sale_starts_at IS 'Start of the sale window (sale_price_effective_date)';
+COMMENT ON COLUMN products.sale_ends_at IS 'End of the sale window (sale_price_effective_date)';
+COMMENT ON COLUMN products.cost IS 'Unit cost, the floor of min_margin price rules';
+
+-- Migration complete
+SELECT 'Product pricing columns added successfully! ✅' as status;
@@ -0,0 +1,25 @@
+-- ============================================================================
+-- Product attribute extraction for Product Lister
+-- Color, size, material, gender and age group are extracted from product
+-- titles, descriptions and variant options as attribute optimizations, one per
+-- value, scored with the model's confidence. Accepted values are stored under
+-- "attributes" in products.metadata, where feed generation reads them.
+-- Run this in Supabase SQL Editor
+-- ============================================================================
+
+-- ============================================================================
+-- Attribute optimization type
+-- ============================================================================
+ALTER TABLE optimization_history DROP CONSTRAINT IF EXISTS optimization_history_optimization_type_check;
+ALTER TABLE optimization_history ADD CONSTRAINT optimization_history_optimization_type_check
+    CHECK (optimization_type IN ('title', 'description', 'category', 'image', 'bulk', 'seo', 'gtin', 'translation', 'attribute'));
+
+-- The review queue and feed checks look up an attribute's optimizations
+CREATE INDEX IF NOT EXISTS idx_optimization_history_attribute
+    ON optimization_history(product_id, (metadata->>'attribute'))
+    WHERE optimization_type = 'attribute';
+
+COMMENT ON COLUMN products.metadata IS 'Connector and AI data; accepted color, size, material, gender and age_group values are kept under "attributes"';
+
+-- Migration complete
+SELECT 'Product attribute extraction enabled successfully! ✅' as status;
@@ -0,0 +1,60 @@
+-- ============================================================================
+-- Product translations for Product Lister
+-- Products keep their own fields in the source language (ai_settings.default_language)
+-- and get one translation per locale of title, description and SEO fields.
+-- Feeds with a language (and country) use the matching translation and fall
+-- back to the product's own fields. The glossary fixes how terms are
+-- translated and which terms, such as brand names, are kept as written.
+-- Run this in Supabase SQL Editor
+-- ============================================================================
+
+-- ============================================================================
+-- Product translations
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS product_translations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    locale VARCHAR(16) NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT,
+    seo_title TEXT,
+    seo_description TEXT,
+    keywords TEXT,
+    source VARCHAR(20) NOT NULL DEFAULT 'ai' CHECK (source IN ('ai', 'manual')),
+    ai_model VARCHAR(100),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT idx_product_translations_locale UNIQUE (product_id, locale)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_translations_organization ON product_translations(organization_id);
+
+-- ============================================================================
+-- Translation glossary
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS translation_glossary (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL,
+    locale VARCHAR(16),
+    term VARCHAR(255) NOT NULL,
+    translation VARCHAR(255),
+    do_not_translate BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_translation_glossary_organization ON translation_glossary(organization_id);
+
+-- ============================================================================
+-- Translation optimization type
+-- ============================================================================
+ALTER TABLE optimization_history DROP CONSTRAINT IF EXISTS optimization_history_optimization_type_check;
+ALTER TABLE optimization_history ADD CONSTRAINT optimization_history_optimization_type_check
+    CHECK (optimization_type IN ('title', 'description', 'category', 'image', 'bulk', 'seo', 'gtin', 'translation'));
+
+COMMENT ON TABLE product_translations IS 'Title, description and SEO fields of a product in one locale, such as de or de-CH';
+COMMENT ON COLUMN product_translations.source IS 'ai for generated translations, manual for edited ones';
+COMMENT ON TABLE translation_glossary IS 'Fixed translations and do-not-translate terms; a NULL locale applies to every locale';
+
+-- Migration complete
+SELECT 'Product translations tables created successfully! ✅' as status;
@@ -0,0 +1,57 @@
+-- ============================================================================
+-- Versioned prompt templates for Product Lister
+-- Go text/template prompts per optimization type. Rows without an organization
+-- are global; an organization's active version overrides the global one and
+-- the built-in prompt applies when neither exists.
+-- Run this in Supabase SQL Editor
+-- ============================================================================
+
+-- ============================================================================
+-- Table: prompt_templates
+-- Purpose: Prompt versions per optimization type and organization
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS prompt_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID, -- NULL for global templates
+    optimization_type VARCHAR(50) NOT NULL,
+    version INTEGER NOT NULL,
+    name VARCHAR(255),
+    system TEXT,
+    body TEXT NOT NULL,
+    max_tokens INTEGER DEFAULT 500 CHECK (max_tokens >= 0 AND max_tokens <= 4000),
+    temperature DECIMAL(3,2) DEFAULT 0.70 CHECK (temperature >= 0 AND temperature <= 2),
+    is_active BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Versions are numbered per organization (or globally) and type
+CREATE UNIQUE INDEX IF NOT EXISTS idx_prompt_templates_version
+    ON prompt_templates(organization_id, optimization_type, version)
+    WHERE organization_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_prompt_templates_global_version
+    ON prompt_templates(optimization_type, version)
+    WHERE organization_id IS NULL;
+
+-- Active template lookup
+CREATE INDEX IF NOT EXISTS idx_prompt_templates_active
+    ON prompt_templates(optimization_type, organization_id) WHERE is_active;
+
+-- ============================================================================
+-- Prompt version on every optimization (0 = built-in prompt)
+-- ============================================================================
+ALTER TABLE optimization_history ADD COLUMN IF NOT EXISTS prompt_template_id UUID REFERENCES prompt_templates(id) ON DELETE SET NULL;
+ALTER TABLE optimization_history ADD COLUMN IF NOT EXISTS prompt_version INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_optimization_history_prompt ON optimization_history(optimization_type, prompt_version);
+
+-- ============================================================================
+-- AI settings available to prompt templates
+-- ============================================================================
+ALTER TABLE ai_settings ADD COLUMN IF NOT EXISTS default_language VARCHAR(10) DEFAULT 'en';
+ALTER TABLE ai_settings ADD COLUMN IF NOT EXISTS custom_instructions TEXT;
+
+COMMENT ON TABLE prompt_templates IS 'Versioned Go text/template prompts per optimization type, with organization overrides';
+
+-- Migration complete
+SELECT 'Prompt templates table created successfully! ✅' as status;
@@ -0,0 +1,46 @@
+-- ============================================================================
+-- Shipping profiles for Product Lister
+-- Per-organization shipping zones priced by weight or price band, with a
+-- free-shipping threshold and handling and transit times, and the tax rates
+-- feeds list as the tax attribute.
+-- Run this in Supabase SQL Editor
+-- ============================================================================
+
+-- ============================================================================
+-- Table: shipping_profiles
+-- ============================================================================
+CREATE TABLE IF NOT EXISTS shipping_profiles (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID DEFAULT '00000000-0000-0000-0000-000000000000'::uuid,
+
+    -- Profile
+    name VARCHAR(255) NOT NULL,
+    labels JSONB NOT NULL DEFAULT '[]',
+    is_default BOOLEAN DEFAULT FALSE,
+    currency VARCHAR(3),
+    weight_unit VARCHAR(2) NOT NULL DEFAULT 'kg' CHECK (weight_unit IN ('kg', 'g', 'lb', 'oz')),
+    dimension_unit VARCHAR(2) NOT NULL DEFAULT 'cm' CHECK (dimension_unit IN ('cm', 'in')),
+    zones JSONB NOT NULL DEFAULT '[]',
+    tax_rates JSONB NOT NULL DEFAULT '[]',
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shipping_profiles_default ON shipping_profiles(organization_id) WHERE is_default;
+
+COMMENT ON TABLE shipping_profiles IS 'Shipping zones, rates and tax rates feed items take their shipping and tax from';
+COMMENT ON COLUMN shipping_profiles.labels IS 'Products whose shipping.shipping_label is listed use this profile; others use the default profile';
+COMMENT ON COLUMN shipping_profiles.zones IS 'Array of {name, countries, region, service, basis, rates: [{min, max, price}], free_shipping_threshold, handling_days, transit_days}';
+COMMENT ON COLUMN shipping_profiles.tax_rates IS 'Array of {country, region, rate, tax_ship, tax_class}';
+
+-- ============================================================================
+-- Products: tax class
+-- ============================================================================
+ALTER TABLE products ADD COLUMN IF NOT EXISTS tax_class TEXT;
+
+COMMENT ON COLUMN products.tax_class IS 'Selects tax rates with a matching tax_class in shipping profiles';
+
+-- Migration complete
+SELECT 'Shipping profiles table created successfully! ✅' as status;
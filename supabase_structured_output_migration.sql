@@ -0,0 +1,23 @@
+-- ============================================================================
+-- Structured AI output for Product Lister
+-- SEO and category responses are validated against JSON schemas. Responses
+-- that stay invalid after the repair retries are stored as failed
+-- optimizations with the raw response in error_message.
+-- Run this in Supabase SQL Editor
+-- ============================================================================
+
+-- ============================================================================
+-- Table: optimization_history
+-- Purpose: Allow SEO and GTIN optimizations to be recorded
+-- ============================================================================
+ALTER TABLE optimization_history DROP CONSTRAINT IF EXISTS optimization_history_optimization_type_check;
+ALTER TABLE optimization_history ADD CONSTRAINT optimization_history_optimization_type_check
+    CHECK (optimization_type IN ('title', 'description', 'category', 'image', 'bulk', 'seo', 'gtin'));
+
+CREATE INDEX IF NOT EXISTS idx_optimization_history_failed
+    ON optimization_history(organization_id, created_at DESC) WHERE status = 'failed';
+
+COMMENT ON COLUMN optimization_history.error_message IS 'Why the optimization failed, including the raw AI response when it did not match the schema';
+
+-- Migration complete
+SELECT 'Structured output migration applied successfully! ✅' as status;